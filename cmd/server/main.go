@@ -4,11 +4,14 @@ import (
 	"blockmind/internal/config"
 	"blockmind/internal/handlers"
 	"blockmind/internal/logger"
+	"blockmind/internal/storage"
+	"context"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // digest timezones must resolve on hosts without zoneinfo
 
 	_ "github.com/mattn/go-sqlite3"
 	qrterminal "github.com/mdp/qrterminal/v3"
@@ -33,7 +36,9 @@ func main() {
 	logger.SetLevel(cfg.WhatsAppLogLevel)
 	logger.Info("Starting BlockMind WhatsApp bot",
 		logger.Field{Key: "debug_mode", Value: cfg.Debug},
-		logger.Field{Key: "model", Value: cfg.HuggingFaceModel})
+		logger.Field{Key: "llm_backend", Value: cfg.LLMBackend},
+		logger.Field{Key: "qa_model", Value: cfg.LLMQAModel},
+		logger.Field{Key: "recommend_model", Value: cfg.LLMRecommendModel})
 
 	// Setup database for WhatsApp
 	dbLog := waLog.Stdout("Database", cfg.WhatsAppLogLevel, cfg.Debug)
@@ -52,8 +57,15 @@ func main() {
 	// Create WhatsApp client
 	client := whatsmeow.NewClient(device, waLog.Stdout("WhatsApp", cfg.WhatsAppLogLevel, cfg.Debug))
 
+	// Open the bot database (alerts and other per-user data)
+	store, err := storage.Open(cfg.DatabasePath)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer store.Close()
+
 	// Create WhatsApp handler
-	whatsappHandler := handlers.NewWhatsAppHandler(client, cfg)
+	whatsappHandler := handlers.NewWhatsAppHandler(client, cfg, store)
 
 	// Register event handlers
 	client.AddEventHandler(func(evt interface{}) {
@@ -75,7 +87,7 @@ func main() {
 			if v.Info.IsFromMe {
 				return
 			}
-			whatsappHandler.HandleMessage(v.Message, v.Info.Chat)
+			whatsappHandler.HandleMessage(v.Message, v.Info.Chat, v.Info.Sender.ToNonAD())
 
 		case *events.Connected:
 			log.Println("Connected to WhatsApp")
@@ -90,6 +102,11 @@ func main() {
 		log.Fatalf("Failed to connect: %v", err)
 	}
 
+	// Start background workers
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	whatsappHandler.Start(ctx)
+
 	log.Println("WhatsApp bot is running")
 
 	// Setup graceful shutdown
@@ -98,6 +115,7 @@ func main() {
 	<-c
 
 	log.Println("Shutting down...")
+	cancel()
 	client.Disconnect()
 	time.Sleep(500 * time.Millisecond) // Give time for cleanup
 }
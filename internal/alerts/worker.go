@@ -0,0 +1,150 @@
+package alerts
+
+import (
+	"blockmind/internal/crypto"
+	"blockmind/internal/i18n"
+	"blockmind/internal/logger"
+	"blockmind/internal/storage"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Notifier sends proactive messages to a chat
+type Notifier interface {
+	Notify(ctx context.Context, jid string, text string) error
+}
+
+// Worker periodically evaluates all active price alerts and notifies the
+// chats they were created in when they trigger
+type Worker struct {
+	store    *storage.Store
+	provider crypto.MarketDataProvider
+	notifier Notifier
+	interval time.Duration
+}
+
+// NewWorker creates a new alert worker checking prices every interval
+func NewWorker(store *storage.Store, provider crypto.MarketDataProvider, notifier Notifier, interval time.Duration) *Worker {
+	return &Worker{
+		store:    store,
+		provider: provider,
+		notifier: notifier,
+		interval: interval,
+	}
+}
+
+// Run evaluates alerts every interval until the context is cancelled
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	logger.Info("Alert worker started", logger.Field{Key: "interval", Value: w.interval.String()})
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Alert worker stopped")
+			return
+		case <-ticker.C:
+			if err := w.Check(ctx); err != nil {
+				logger.Error("Failed to check price alerts", err)
+			}
+		}
+	}
+}
+
+// Check evaluates all active alerts against current prices fetched in a
+// single batched request
+func (w *Worker) Check(ctx context.Context) error {
+	active, err := w.store.ActiveAlerts(ctx)
+	if err != nil {
+		return err
+	}
+	if len(active) == 0 {
+		return nil
+	}
+
+	// Batch every coin and currency into one price request
+	var coins []crypto.Coin
+	var currencies []string
+	seenCoins := make(map[string]bool)
+	seenCurrencies := make(map[string]bool)
+	for _, alert := range active {
+		if !seenCoins[alert.CoinID] {
+			seenCoins[alert.CoinID] = true
+			coins = append(coins, crypto.Coin{ID: alert.CoinID, Symbol: alert.CoinSymbol, Name: alert.CoinName})
+		}
+		if !seenCurrencies[alert.Currency] {
+			seenCurrencies[alert.Currency] = true
+			currencies = append(currencies, alert.Currency)
+		}
+	}
+
+	quotes, err := w.provider.SpotPrices(ctx, coins, currencies)
+	if err != nil {
+		return fmt.Errorf("failed to fetch alert prices: %w", err)
+	}
+
+	now := time.Now()
+	for _, alert := range active {
+		quote, ok := quotes.Get(alert.CoinID, alert.Currency)
+		if !ok {
+			continue
+		}
+
+		triggered := alert.Triggered(quote.Price)
+		switch {
+		case triggered && alert.Armed:
+			if err := w.notifier.Notify(ctx, alert.ChatJID, FormatTriggered(alert, quote.Price, w.locale(ctx, alert.UserJID))); err != nil {
+				logger.Error("Failed to send alert notification", err,
+					logger.Field{Key: "alert_id", Value: alert.ID})
+				continue
+			}
+			if err := w.store.MarkAlertTriggered(ctx, alert.ID, now); err != nil {
+				logger.Error("Failed to update triggered alert", err,
+					logger.Field{Key: "alert_id", Value: alert.ID})
+			}
+		case !triggered && !alert.Armed && alert.Repeat:
+			if err := w.store.RearmAlert(ctx, alert.ID); err != nil {
+				logger.Error("Failed to rearm alert", err,
+					logger.Field{Key: "alert_id", Value: alert.ID})
+			}
+		}
+	}
+	return nil
+}
+
+// locale returns the language notifications to a user are written in. The
+// default is used when the settings cannot be read.
+func (w *Worker) locale(ctx context.Context, userJID string) string {
+	settings, err := w.store.GetSettings(ctx, userJID)
+	if err != nil {
+		logger.Error("Failed to read user settings", err, logger.Field{Key: "user_jid", Value: userJID})
+	}
+	return i18n.Resolve(settings.Language, "", userJID)
+}
+
+// FormatTriggered formats the notification sent when an alert fires
+func FormatTriggered(alert storage.Alert, price float64, locale string) string {
+	coin := crypto.Coin{ID: alert.CoinID, Symbol: alert.CoinSymbol, Name: alert.CoinName}
+	message := i18n.Translate(locale, "alert.triggered",
+		alert.ID, coin, i18n.Translate(locale, "alert.condition."+alert.Condition),
+		crypto.FormatPrice(alert.Target, alert.Currency),
+		crypto.FormatPrice(price, alert.Currency))
+	if alert.Repeat {
+		message += "\n" + i18n.Translate(locale, "alert.triggered.repeat")
+	}
+	return message
+}
+
+// FormatAlert formats an alert for listings, e.g. "#3 BTC above 70,000.00 USD (repeat)"
+func FormatAlert(alert storage.Alert, locale string) string {
+	line := fmt.Sprintf("#%d %s %s %s", alert.ID, strings.ToUpper(alert.CoinSymbol),
+		i18n.Translate(locale, "alert.condition."+alert.Condition),
+		crypto.FormatPrice(alert.Target, alert.Currency))
+	if alert.Repeat {
+		line += " " + i18n.Translate(locale, "alert.repeat_tag")
+	}
+	return line
+}
@@ -0,0 +1,196 @@
+package alerts
+
+import (
+	"blockmind/internal/crypto"
+	"blockmind/internal/storage"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeProvider quotes bitcoin in usd at price
+type fakeProvider struct {
+	price float64
+	calls int
+}
+
+func (p *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *fakeProvider) SpotPrices(ctx context.Context, coins []crypto.Coin, vsCurrencies []string) (crypto.Quotes, error) {
+	p.calls++
+	return crypto.Quotes{"bitcoin": {"usd": {Price: p.price}}}, nil
+}
+
+func (p *fakeProvider) MarketSnapshot(ctx context.Context, coin crypto.Coin, vsCurrency string) (*crypto.Snapshot, error) {
+	return nil, crypto.ErrNotSupported
+}
+
+func (p *fakeProvider) History(ctx context.Context, coin crypto.Coin, vsCurrency string, days int) ([]crypto.PricePoint, error) {
+	return nil, crypto.ErrNotSupported
+}
+
+func (p *fakeProvider) CoinInfo(ctx context.Context, coin crypto.Coin) (*crypto.CoinInfo, error) {
+	return nil, crypto.ErrNotSupported
+}
+
+// notification is a message sent by a fakeNotifier
+type notification struct {
+	jid  string
+	text string
+}
+
+// fakeNotifier records notifications, failing them while err is set
+type fakeNotifier struct {
+	sent []notification
+	err  error
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, jid string, text string) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, notification{jid: jid, text: text})
+	return nil
+}
+
+// newTestWorker returns a worker over an in-memory store
+func newTestWorker(t *testing.T) (*Worker, *storage.Store, *fakeProvider, *fakeNotifier) {
+	t.Helper()
+	store, err := storage.Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	provider := &fakeProvider{}
+	notifier := &fakeNotifier{}
+	return NewWorker(store, provider, notifier, time.Minute), store, provider, notifier
+}
+
+// createAlert stores an alert by alice in a group for bitcoin above 70,000 USD
+func createAlert(t *testing.T, store *storage.Store, repeat bool) storage.Alert {
+	t.Helper()
+	alert := storage.Alert{
+		UserJID:    "alice@s.whatsapp.net",
+		ChatJID:    "friends@g.us",
+		CoinID:     "bitcoin",
+		CoinSymbol: "btc",
+		CoinName:   "Bitcoin",
+		Currency:   "usd",
+		Condition:  storage.AlertAbove,
+		Target:     70000,
+		Repeat:     repeat,
+	}
+	if err := store.CreateAlert(context.Background(), &alert); err != nil {
+		t.Fatalf("CreateAlert() error = %v", err)
+	}
+	return alert
+}
+
+// tick checks the alerts at price and returns how many notifications it sent
+func tick(t *testing.T, worker *Worker, provider *fakeProvider, notifier *fakeNotifier, price float64) int {
+	t.Helper()
+	provider.price = price
+	before := len(notifier.sent)
+	if err := worker.Check(context.Background()); err != nil {
+		t.Fatalf("Check() at %v error = %v", price, err)
+	}
+	return len(notifier.sent) - before
+}
+
+func TestRepeatingAlert(t *testing.T) {
+	worker, store, provider, notifier := newTestWorker(t)
+	createAlert(t, store, true)
+
+	steps := []struct {
+		price float64
+		sent  int
+		armed bool
+	}{
+		{69000, 0, true},
+		// Crossing the target notifies once and disarms the alert
+		{71000, 1, false},
+		{72000, 0, false},
+		{70000, 0, false},
+		// Falling back below the target re-arms it
+		{69000, 0, true},
+		{69500, 0, true},
+		{71000, 1, false},
+	}
+	for i, step := range steps {
+		if sent := tick(t, worker, provider, notifier, step.price); sent != step.sent {
+			t.Errorf("step %d at %v sent %d notifications, want %d", i, step.price, sent, step.sent)
+		}
+		alerts, _ := store.ActiveAlerts(context.Background())
+		if len(alerts) != 1 {
+			t.Fatalf("step %d: %d active alerts, want the repeating alert kept", i, len(alerts))
+		}
+		if alerts[0].Armed != step.armed {
+			t.Errorf("step %d at %v: Armed = %v, want %v", i, step.price, alerts[0].Armed, step.armed)
+		}
+	}
+
+	// Notifications go to the chat the alert was created in
+	for _, sent := range notifier.sent {
+		if sent.jid != "friends@g.us" || !strings.Contains(sent.text, "71,000") {
+			t.Errorf("notification %+v, want the price sent to the group", sent)
+		}
+	}
+}
+
+func TestOneShotAlert(t *testing.T) {
+	worker, store, provider, notifier := newTestWorker(t)
+	createAlert(t, store, false)
+
+	if sent := tick(t, worker, provider, notifier, 71000); sent != 1 {
+		t.Fatalf("sent %d notifications, want 1", sent)
+	}
+	if alerts, _ := store.ActiveAlerts(context.Background()); len(alerts) != 0 {
+		t.Errorf("%d active alerts after triggering, want the one-shot alert deactivated", len(alerts))
+	}
+
+	// Inactive alerts are not checked again
+	provider.calls = 0
+	for _, price := range []float64{69000, 71000} {
+		if sent := tick(t, worker, provider, notifier, price); sent != 0 {
+			t.Errorf("sent %d notifications at %v, want none", sent, price)
+		}
+	}
+	if provider.calls != 0 {
+		t.Errorf("prices fetched %d times without active alerts, want 0", provider.calls)
+	}
+}
+
+func TestAlertRetriedWhenNotificationFails(t *testing.T) {
+	worker, store, provider, notifier := newTestWorker(t)
+	createAlert(t, store, true)
+
+	notifier.err = errors.New("not connected")
+	tick(t, worker, provider, notifier, 71000)
+	if alerts, _ := store.ActiveAlerts(context.Background()); !alerts[0].Armed {
+		t.Error("alert disarmed although the notification failed")
+	}
+
+	notifier.err = nil
+	if sent := tick(t, worker, provider, notifier, 71000); sent != 1 {
+		t.Errorf("sent %d notifications once delivery works, want 1", sent)
+	}
+}
+
+func TestFormatTriggered(t *testing.T) {
+	alert := storage.Alert{ID: 3, CoinID: "bitcoin", CoinSymbol: "btc", CoinName: "Bitcoin",
+		Currency: "usd", Condition: storage.AlertAbove, Target: 70000}
+
+	message := FormatTriggered(alert, 71000, "en")
+	if !strings.Contains(message, "#3") || !strings.Contains(message, "70,000") || !strings.Contains(message, "71,000") {
+		t.Errorf("FormatTriggered() = %q, want the alert, target and price", message)
+	}
+	alert.Repeat = true
+	if repeating := FormatTriggered(alert, 71000, "en"); len(repeating) <= len(message) {
+		t.Errorf("FormatTriggered() of a repeating alert = %q, want a note that it repeats", repeating)
+	}
+}
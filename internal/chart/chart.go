@@ -0,0 +1,171 @@
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"time"
+)
+
+// Point is a single sample of a time series
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Options controls the size of the rendered chart
+type Options struct {
+	Width  int
+	Height int
+}
+
+// DefaultOptions returns a size that renders well in WhatsApp previews
+func DefaultOptions() Options {
+	return Options{Width: 800, Height: 450}
+}
+
+var (
+	backgroundColor = color.RGBA{R: 0x12, G: 0x16, B: 0x1c, A: 0xff}
+	gridColor       = color.RGBA{R: 0x2a, G: 0x31, B: 0x3b, A: 0xff}
+	upColor         = color.RGBA{R: 0x16, G: 0xc7, B: 0x84, A: 0xff}
+	downColor       = color.RGBA{R: 0xea, G: 0x39, B: 0x43, A: 0xff}
+)
+
+const (
+	// padding is the empty border around the plot area, in pixels
+	padding = 24
+	// gridLines is the number of horizontal grid lines
+	gridLines = 4
+	// lineWidth is the thickness of the price line, in pixels
+	lineWidth = 3
+)
+
+// RenderLine draws the points as a line chart with a filled area below it and
+// returns it encoded as PNG. The line is green when the series ends above its
+// start and red otherwise.
+func RenderLine(points []Point, opts Options) ([]byte, error) {
+	if len(points) < 2 {
+		return nil, fmt.Errorf("at least 2 points are needed to draw a chart, got %d", len(points))
+	}
+	if opts.Width <= 2*padding || opts.Height <= 2*padding {
+		return nil, fmt.Errorf("chart size %dx%d is too small", opts.Width, opts.Height)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+	fillRect(img, img.Bounds(), backgroundColor)
+
+	plot := image.Rect(padding, padding, opts.Width-padding, opts.Height-padding)
+	for i := 0; i <= gridLines; i++ {
+		y := plot.Min.Y + i*plot.Dy()/gridLines
+		fillRect(img, image.Rect(plot.Min.X, y, plot.Max.X, y+1), gridColor)
+	}
+
+	// Scale values to the plot area, leaving a margin above and below
+	minValue, maxValue := points[0].Value, points[0].Value
+	for _, point := range points {
+		minValue = math.Min(minValue, point.Value)
+		maxValue = math.Max(maxValue, point.Value)
+	}
+	margin := (maxValue - minValue) * 0.05
+	if margin == 0 {
+		margin = math.Max(math.Abs(maxValue)*0.01, 1e-12)
+	}
+	minValue -= margin
+	maxValue += margin
+
+	start, end := points[0].Time, points[len(points)-1].Time
+	span := end.Sub(start).Seconds()
+
+	coords := make([]image.Point, len(points))
+	for i, point := range points {
+		xRatio := float64(i) / float64(len(points)-1)
+		if span > 0 {
+			xRatio = point.Time.Sub(start).Seconds() / span
+		}
+		yRatio := (point.Value - minValue) / (maxValue - minValue)
+		coords[i] = image.Point{
+			X: plot.Min.X + int(math.Round(xRatio*float64(plot.Dx()-1))),
+			Y: plot.Max.Y - 1 - int(math.Round(yRatio*float64(plot.Dy()-1))),
+		}
+	}
+
+	lineColor := upColor
+	if points[len(points)-1].Value < points[0].Value {
+		lineColor = downColor
+	}
+	areaColor := color.RGBA{R: lineColor.R / 4, G: lineColor.G / 4, B: lineColor.B / 4, A: 0xff}
+
+	// Fill the area below the line column by column
+	for i := 1; i < len(coords); i++ {
+		a, b := coords[i-1], coords[i]
+		for x := a.X; x <= b.X; x++ {
+			y := a.Y
+			if b.X != a.X {
+				y = a.Y + (b.Y-a.Y)*(x-a.X)/(b.X-a.X)
+			}
+			fillRect(img, image.Rect(x, y, x+1, plot.Max.Y), areaColor)
+		}
+	}
+
+	for i := 1; i < len(coords); i++ {
+		drawLine(img, coords[i-1], coords[i], lineColor)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode chart: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawLine draws a thick line between two points using Bresenham's algorithm
+func drawLine(img *image.RGBA, from, to image.Point, c color.RGBA) {
+	dx := abs(to.X - from.X)
+	dy := -abs(to.Y - from.Y)
+	sx, sy := 1, 1
+	if from.X > to.X {
+		sx = -1
+	}
+	if from.Y > to.Y {
+		sy = -1
+	}
+
+	x, y := from.X, from.Y
+	err := dx + dy
+	for {
+		half := lineWidth / 2
+		fillRect(img, image.Rect(x-half, y-half, x-half+lineWidth, y-half+lineWidth), c)
+		if x == to.X && y == to.Y {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y += sy
+		}
+	}
+}
+
+// fillRect fills the intersection of rect and the image bounds with c
+func fillRect(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	rect = rect.Intersect(img.Bounds())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,182 @@
+package coingecko
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheEntry is a raw API response stored in a Cache
+type CacheEntry struct {
+	Body     []byte
+	StoredAt time.Time
+}
+
+// Cache stores raw API responses keyed by normalized request. Implementations
+// must be safe for concurrent use; freshness is decided by the Client.
+type Cache interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry)
+}
+
+// CacheTTLs configures how long responses of each endpoint are considered fresh.
+// A zero TTL disables caching for that endpoint. MaxStale is how long after
+// expiry a response may still be served when CoinGecko fails.
+type CacheTTLs struct {
+	Price       time.Duration
+	Markets     time.Duration
+	CoinDetail  time.Duration
+	MarketChart time.Duration
+	CoinList    time.Duration
+	Search      time.Duration
+	MaxStale    time.Duration
+}
+
+// DefaultCacheTTLs returns TTLs suited to the demo API quota
+func DefaultCacheTTLs() CacheTTLs {
+	return CacheTTLs{
+		Price:       time.Minute,
+		Markets:     2 * time.Minute,
+		CoinDetail:  5 * time.Minute,
+		MarketChart: 5 * time.Minute,
+		CoinList:    24 * time.Hour,
+		Search:      time.Hour,
+		MaxStale:    time.Hour,
+	}
+}
+
+// ttlFor returns the TTL of the endpoint at the given path
+func (t CacheTTLs) ttlFor(path string) time.Duration {
+	switch {
+	case path == "/simple/price":
+		return t.Price
+	case path == "/coins/markets":
+		return t.Markets
+	case path == "/coins/list":
+		return t.CoinList
+	case path == "/search":
+		return t.Search
+	case strings.HasSuffix(path, "/market_chart"):
+		return t.MarketChart
+	case strings.HasPrefix(path, "/coins/"):
+		return t.CoinDetail
+	}
+	return 0
+}
+
+// cacheKey builds a key that is identical for equivalent requests, ignoring
+// the order and case of comma separated ids and currencies
+func cacheKey(path string, query url.Values) string {
+	normalized := url.Values{}
+	for key, values := range query {
+		for _, value := range values {
+			if key == "ids" || key == "vs_currencies" {
+				items := strings.Split(strings.ToLower(value), ",")
+				sort.Strings(items)
+				value = strings.Join(items, ",")
+			}
+			normalized.Add(key, value)
+		}
+	}
+	// Encode sorts by key
+	return path + "?" + normalized.Encode()
+}
+
+// MemoryCache is an in-process Cache that drops entries older than maxAge
+type MemoryCache struct {
+	maxAge  time.Duration
+	mu      sync.Mutex
+	entries map[string]CacheEntry
+	sets    int
+}
+
+// NewMemoryCache creates an in-memory cache keeping entries for at most maxAge
+func NewMemoryCache(maxAge time.Duration) *MemoryCache {
+	return &MemoryCache{
+		maxAge:  maxAge,
+		entries: make(map[string]CacheEntry),
+	}
+}
+
+// Get returns the entry stored under key, if it has not aged out
+func (m *MemoryCache) Get(key string) (CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	if time.Since(entry.StoredAt) > m.maxAge {
+		delete(m.entries, key)
+		return CacheEntry{}, false
+	}
+	return entry, true
+}
+
+// Set stores entry under key
+func (m *MemoryCache) Set(key string, entry CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = entry
+
+	// Periodically sweep entries that aged out without being read again
+	m.sets++
+	if m.sets%100 == 0 {
+		for k, e := range m.entries {
+			if time.Since(e.StoredAt) > m.maxAge {
+				delete(m.entries, k)
+			}
+		}
+	}
+}
+
+// flightGroup coalesces concurrent identical requests into one upstream call
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+// do runs fn once for all concurrent callers sharing the same key. The call
+// runs in the background, so a caller whose ctx ends stops waiting for it
+// without cancelling it for the others.
+func (g *flightGroup) do(ctx context.Context, key string, fn func() ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	call, ok := g.calls[key]
+	if !ok {
+		call = &flightCall{done: make(chan struct{})}
+		g.calls[key] = call
+		go g.run(key, call, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.body, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// run performs a shared call and removes it once finished
+func (g *flightGroup) run(key string, call *flightCall, fn func() ([]byte, error)) {
+	call.body, call.err = fn()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(call.done)
+}
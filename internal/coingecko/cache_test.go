@@ -0,0 +1,253 @@
+package coingecko
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// priceServer is a test /simple/price endpoint that counts its hits. It
+// answers with status, and waits for release first when it is set.
+type priceServer struct {
+	hits    atomic.Int32
+	status  atomic.Int32
+	release chan struct{}
+}
+
+func (s *priceServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.hits.Add(1)
+	if s.release != nil {
+		<-s.release
+	}
+	if status := int(s.status.Load()); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	respond(w, `{"bitcoin": {"usd": 65000}}`)
+}
+
+// newCachedClient returns a client with a price TTL of a minute and an hour
+// of stale serving, talking to server
+func newCachedClient(t *testing.T, server *priceServer) (*Client, *MemoryCache) {
+	t.Helper()
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	ttls := CacheTTLs{Price: time.Minute, MaxStale: time.Hour}
+	cache := NewMemoryCache(ttls.Price + ttls.MaxStale)
+	client := NewClient(httpServer.URL, "", httpServer.Client())
+	client.SetCache(cache, ttls)
+	return client, cache
+}
+
+// age makes every cached entry older by d
+func age(cache *MemoryCache, d time.Duration) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for key, entry := range cache.entries {
+		entry.StoredAt = entry.StoredAt.Add(-d)
+		cache.entries[key] = entry
+	}
+}
+
+// bitcoinPrice asks client for the price of bitcoin in usd
+func bitcoinPrice(ctx context.Context, client *Client, ids ...string) (float64, error) {
+	if len(ids) == 0 {
+		ids = []string{"bitcoin"}
+	}
+	prices, err := client.SimplePrice(ctx, ids, []string{"usd"}, SimplePriceOptions{})
+	if err != nil {
+		return 0, err
+	}
+	quote, _ := prices.Quote("bitcoin", "usd")
+	return quote.Price, nil
+}
+
+// waitForHits waits until server has been hit n times
+func waitForHits(t *testing.T, server *priceServer, n int32) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for server.hits.Load() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("server hit %d times, want %d", server.hits.Load(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCacheServesFreshResponses(t *testing.T) {
+	server := &priceServer{}
+	client, cache := newCachedClient(t, server)
+
+	for i := 0; i < 3; i++ {
+		if price, err := bitcoinPrice(context.Background(), client); err != nil || price != 65000 {
+			t.Fatalf("bitcoinPrice() = %v, %v, want 65000", price, err)
+		}
+	}
+	if hits := server.hits.Load(); hits != 1 {
+		t.Errorf("server hit %d times, want 1", hits)
+	}
+
+	// Expired responses are fetched again
+	age(cache, 2*time.Minute)
+	if _, err := bitcoinPrice(context.Background(), client); err != nil {
+		t.Fatalf("bitcoinPrice() after expiry error = %v", err)
+	}
+	if hits := server.hits.Load(); hits != 2 {
+		t.Errorf("server hit %d times after expiry, want 2", hits)
+	}
+}
+
+func TestCacheKeyIgnoresOrderAndCase(t *testing.T) {
+	server := &priceServer{}
+	client, _ := newCachedClient(t, server)
+
+	bitcoinPrice(context.Background(), client, "bitcoin", "ethereum")
+	bitcoinPrice(context.Background(), client, "Ethereum", "bitcoin")
+	if hits := server.hits.Load(); hits != 1 {
+		t.Errorf("server hit %d times, want 1 for equivalent requests", hits)
+	}
+}
+
+func TestCoalescesConcurrentRequests(t *testing.T) {
+	server := &priceServer{release: make(chan struct{})}
+	client, _ := newCachedClient(t, server)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := bitcoinPrice(context.Background(), client); err != nil {
+				errs <- err
+			}
+		}()
+	}
+
+	// Let the callers join the request in flight before it answers
+	waitForHits(t, server, 1)
+	time.Sleep(20 * time.Millisecond)
+	close(server.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("bitcoinPrice() error = %v", err)
+	}
+	if hits := server.hits.Load(); hits != 1 {
+		t.Errorf("server hit %d times for %d concurrent callers, want 1", hits, callers)
+	}
+}
+
+func TestCancelledCallerDoesNotCancelSharedRequest(t *testing.T) {
+	server := &priceServer{release: make(chan struct{})}
+	client, _ := newCachedClient(t, server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := bitcoinPrice(ctx, client)
+		done <- err
+	}()
+
+	// A waiting caller joins the request before the first one gives up
+	waiting := make(chan error, 1)
+	waitForHits(t, server, 1)
+	go func() {
+		_, err := bitcoinPrice(context.Background(), client)
+		waiting <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("cancelled caller error = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("cancelled caller still waiting for the shared request")
+	}
+
+	close(server.release)
+	if err := <-waiting; err != nil {
+		t.Errorf("waiting caller error = %v, want the shared response", err)
+	}
+
+	// The response was cached although the caller that started it gave up
+	if _, err := bitcoinPrice(context.Background(), client); err != nil {
+		t.Fatalf("bitcoinPrice() error = %v", err)
+	}
+	if hits := server.hits.Load(); hits != 1 {
+		t.Errorf("server hit %d times, want 1", hits)
+	}
+}
+
+func TestCacheServesStaleWhenRateLimited(t *testing.T) {
+	server := &priceServer{}
+	client, cache := newCachedClient(t, server)
+	if _, err := bitcoinPrice(context.Background(), client); err != nil {
+		t.Fatalf("bitcoinPrice() error = %v", err)
+	}
+
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		server.status.Store(int32(status))
+		age(cache, 2*time.Minute)
+		price, err := bitcoinPrice(context.Background(), client)
+		if err != nil || price != 65000 {
+			t.Errorf("bitcoinPrice() with status %d = %v, %v, want the stale 65000", status, price, err)
+		}
+	}
+
+	// Not past the stale window
+	server.status.Store(http.StatusTooManyRequests)
+	age(cache, time.Hour)
+	if _, err := bitcoinPrice(context.Background(), client); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("bitcoinPrice() past the stale window error = %v, want ErrRateLimited", err)
+	}
+}
+
+func TestCacheDoesNotServeStaleOnDefinitiveErrors(t *testing.T) {
+	server := &priceServer{}
+	client, cache := newCachedClient(t, server)
+	if _, err := bitcoinPrice(context.Background(), client); err != nil {
+		t.Fatalf("bitcoinPrice() error = %v", err)
+	}
+
+	server.status.Store(http.StatusNotFound)
+	age(cache, 2*time.Minute)
+	if _, err := bitcoinPrice(context.Background(), client); !errors.Is(err, ErrNotFound) {
+		t.Errorf("bitcoinPrice() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCacheDoesNotServeStaleToCallerThatGaveUp(t *testing.T) {
+	server := &priceServer{}
+	client, cache := newCachedClient(t, server)
+	if _, err := bitcoinPrice(context.Background(), client); err != nil {
+		t.Fatalf("bitcoinPrice() error = %v", err)
+	}
+
+	server.release = make(chan struct{})
+	defer close(server.release)
+	age(cache, 2*time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if price, err := bitcoinPrice(ctx, client); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("bitcoinPrice() = %v, %v, want context.DeadlineExceeded", price, err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if price, err := bitcoinPrice(ctx, client); !errors.Is(err, context.Canceled) {
+		t.Errorf("bitcoinPrice() = %v, %v, want context.Canceled", price, err)
+	}
+}
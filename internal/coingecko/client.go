@@ -0,0 +1,276 @@
+package coingecko
+
+import (
+	"blockmind/internal/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the public CoinGecko v3 API endpoint
+const DefaultBaseURL = "https://api.coingecko.com/api/v3"
+
+// maxErrorBody limits how much of an error response is kept in APIError.Message
+const maxErrorBody = 512
+
+// Client is a typed client for the CoinGecko v3 REST API
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+
+	cache  Cache
+	ttls   CacheTTLs
+	flight flightGroup
+}
+
+// NewClient creates a new CoinGecko client. An empty baseURL falls back to
+// DefaultBaseURL and a nil httpClient to one with a 15 second timeout.
+func NewClient(baseURL, apiKey string, httpClient *http.Client) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 15 * time.Second}
+	}
+
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: httpClient,
+	}
+}
+
+// SetCache enables response caching with the given per-endpoint TTLs
+func (c *Client) SetCache(cache Cache, ttls CacheTTLs) {
+	c.cache = cache
+	c.ttls = ttls
+}
+
+// SimplePrice fetches prices for the given coin ids in the given vs currencies
+func (c *Client) SimplePrice(ctx context.Context, ids, vsCurrencies []string, opts SimplePriceOptions) (SimplePrices, error) {
+	query := url.Values{}
+	query.Set("ids", strings.Join(ids, ","))
+	query.Set("vs_currencies", strings.Join(vsCurrencies, ","))
+	query.Set("include_market_cap", strconv.FormatBool(opts.IncludeMarketCap))
+	query.Set("include_24hr_vol", strconv.FormatBool(opts.Include24hrVol))
+	query.Set("include_24hr_change", strconv.FormatBool(opts.Include24hrChange))
+	query.Set("include_last_updated_at", strconv.FormatBool(opts.IncludeLastUpdatedAt))
+	query.Set("precision", "full")
+
+	var prices SimplePrices
+	if err := c.get(ctx, "/simple/price", query, &prices); err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// Markets fetches market data for the given coin ids, ordered by market cap
+func (c *Client) Markets(ctx context.Context, vsCurrency string, ids []string) ([]Market, error) {
+	query := url.Values{}
+	query.Set("vs_currency", vsCurrency)
+	query.Set("ids", strings.Join(ids, ","))
+	query.Set("order", "market_cap_desc")
+	query.Set("locale", "en")
+	query.Set("precision", "full")
+
+	var markets []Market
+	if err := c.get(ctx, "/coins/markets", query, &markets); err != nil {
+		return nil, err
+	}
+	return markets, nil
+}
+
+// Coin fetches the detail of a single coin including its market data
+func (c *Client) Coin(ctx context.Context, id string) (*CoinDetail, error) {
+	query := url.Values{}
+	query.Set("localization", "false")
+	query.Set("tickers", "false")
+	query.Set("market_data", "true")
+	query.Set("community_data", "false")
+	query.Set("developer_data", "false")
+
+	var coin CoinDetail
+	if err := c.get(ctx, "/coins/"+url.PathEscape(id), query, &coin); err != nil {
+		return nil, err
+	}
+	return &coin, nil
+}
+
+// MarketChart fetches historical prices, market caps and volumes for the last
+// given number of days ("1", "7", "30", "365", "max", ...)
+func (c *Client) MarketChart(ctx context.Context, id, vsCurrency, days string) (*MarketChart, error) {
+	query := url.Values{}
+	query.Set("vs_currency", vsCurrency)
+	query.Set("days", days)
+	query.Set("precision", "full")
+
+	var chart MarketChart
+	if err := c.get(ctx, "/coins/"+url.PathEscape(id)+"/market_chart", query, &chart); err != nil {
+		return nil, err
+	}
+	return &chart, nil
+}
+
+// CoinsList fetches the full list of coins supported by CoinGecko
+func (c *Client) CoinsList(ctx context.Context) ([]CoinListEntry, error) {
+	var coins []CoinListEntry
+	if err := c.get(ctx, "/coins/list", nil, &coins); err != nil {
+		return nil, err
+	}
+	return coins, nil
+}
+
+// Search looks up coins by name or symbol, ordered by market cap rank
+func (c *Client) Search(ctx context.Context, term string) (*SearchResult, error) {
+	query := url.Values{}
+	query.Set("query", term)
+
+	var result SearchResult
+	if err := c.get(ctx, "/search", query, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// get performs a GET request against the API and decodes the JSON body into out.
+// When a cache is configured, fresh responses are served from it, concurrent
+// identical requests share one upstream call and stale responses are served
+// when CoinGecko is rate limiting or failing.
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	ttl := c.ttls.ttlFor(path)
+	if c.cache == nil || ttl <= 0 {
+		body, err := c.fetch(ctx, path, query)
+		if err != nil {
+			return err
+		}
+		return decode(path, body, out)
+	}
+
+	key := cacheKey(path, query)
+	cached, hasCached := c.cache.Get(key)
+	if hasCached && time.Since(cached.StoredAt) < ttl {
+		return decode(path, cached.Body, out)
+	}
+
+	// The shared call must not be cancelled by whichever caller started it,
+	// and caches its response even if every caller stopped waiting
+	body, err := c.flight.do(ctx, key, func() ([]byte, error) {
+		body, err := c.fetch(context.WithoutCancel(ctx), path, query)
+		if err == nil {
+			c.cache.Set(key, CacheEntry{Body: body, StoredAt: time.Now()})
+		}
+		return body, err
+	})
+	if err == nil {
+		return decode(path, body, out)
+	}
+
+	// A caller that gave up or ran out of time gets its own context error,
+	// not stale data as if CoinGecko had failed
+	if ctx.Err() != nil {
+		return err
+	}
+	if hasCached && time.Since(cached.StoredAt) < ttl+c.ttls.MaxStale && isTransient(err) {
+		logger.Warn("Serving stale CoinGecko response",
+			logger.Field{Key: "endpoint", Value: path},
+			logger.Field{Key: "age", Value: time.Since(cached.StoredAt).String()},
+			logger.Field{Key: "error", Value: err.Error()})
+		return decode(path, cached.Body, out)
+	}
+	return err
+}
+
+// fetch performs a GET request against the API and returns the raw body
+func (c *Client) fetch(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Setup headers
+	req.Header.Set("accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("x-cg-demo-api-key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{
+			StatusCode: resp.StatusCode,
+			Endpoint:   path,
+			Message:    errorMessage(body),
+		}
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			apiErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return nil, apiErr
+	}
+
+	return body, nil
+}
+
+// decode unmarshals a response body into out
+func decode(path string, body []byte, out interface{}) error {
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response from %s: %w", path, err)
+	}
+	return nil
+}
+
+// isTransient reports whether err may go away on retry, as opposed to a
+// definitive answer like 404 or 400. The shared request is not bound to the
+// caller's context, so context errors here come from the HTTP client timeout.
+func isTransient(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUnavailable)
+	}
+	return true
+}
+
+// errorMessage extracts a readable message from an error response body
+func errorMessage(body []byte) string {
+	var payload struct {
+		Error  string `json:"error"`
+		Status struct {
+			ErrorMessage string `json:"error_message"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if payload.Error != "" {
+			return payload.Error
+		}
+		if payload.Status.ErrorMessage != "" {
+			return payload.Status.ErrorMessage
+		}
+	}
+
+	message := strings.TrimSpace(string(body))
+	if len(message) > maxErrorBody {
+		message = message[:maxErrorBody]
+	}
+	return message
+}
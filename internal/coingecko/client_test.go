@@ -0,0 +1,245 @@
+package coingecko
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client talking to a test server running handler
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	// A trailing slash in the base URL is ignored
+	return NewClient(server.URL+"/", "demo-key", server.Client())
+}
+
+// respond writes body as a JSON response
+func respond(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(body))
+}
+
+func TestSimplePrice(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/simple/price" {
+			t.Errorf("path = %q, want /simple/price", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("ids") != "bitcoin,ethereum" || query.Get("vs_currencies") != "usd,eur" {
+			t.Errorf("query = %v, want the ids and currencies", query)
+		}
+		if query.Get("include_24hr_change") != "true" || query.Get("include_market_cap") != "false" {
+			t.Errorf("query = %v, want only the 24h change included", query)
+		}
+		if got := r.Header.Get("x-cg-demo-api-key"); got != "demo-key" {
+			t.Errorf("API key header = %q, want demo-key", got)
+		}
+		respond(w, `{
+			"bitcoin": {"usd": 65000.5, "usd_24h_change": -1.25, "eur": 60000, "last_updated_at": 1700000000},
+			"ethereum": {"usd": 3000}
+		}`)
+	})
+
+	prices, err := client.SimplePrice(context.Background(), []string{"bitcoin", "ethereum"}, []string{"usd", "eur"},
+		SimplePriceOptions{Include24hrChange: true})
+	if err != nil {
+		t.Fatalf("SimplePrice() error = %v", err)
+	}
+
+	quote, ok := prices.Quote("bitcoin", "usd")
+	if !ok {
+		t.Fatal("Quote(bitcoin, usd) missing")
+	}
+	if quote.Price != 65000.5 || quote.Change24h != -1.25 || !quote.LastUpdatedAt.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Quote(bitcoin, usd) = %+v", quote)
+	}
+	if quote, _ := prices.Quote("bitcoin", "eur"); quote.Price != 60000 || quote.Change24h != 0 {
+		t.Errorf("Quote(bitcoin, eur) = %+v, want 60000 without change", quote)
+	}
+	if _, ok := prices.Quote("ethereum", "eur"); ok {
+		t.Error("Quote(ethereum, eur) found, want missing")
+	}
+}
+
+func TestMarkets(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/coins/markets" || r.URL.Query().Get("vs_currency") != "usd" || r.URL.Query().Get("ids") != "bitcoin" {
+			t.Errorf("request = %s, want the bitcoin market in usd", r.URL)
+		}
+		respond(w, `[{
+			"id": "bitcoin", "symbol": "btc", "name": "Bitcoin",
+			"current_price": 65000, "market_cap": 1280000000000, "market_cap_rank": 1,
+			"fully_diluted_valuation": null, "total_volume": 30000000000,
+			"high_24h": 66000, "low_24h": 64000, "price_change_percentage_24h": 1.5,
+			"circulating_supply": 19700000, "total_supply": 21000000, "max_supply": null
+		}]`)
+	})
+
+	markets, err := client.Markets(context.Background(), "usd", []string{"bitcoin"})
+	if err != nil {
+		t.Fatalf("Markets() error = %v", err)
+	}
+	if len(markets) != 1 {
+		t.Fatalf("Markets() = %d markets, want 1", len(markets))
+	}
+	market := markets[0]
+	if market.ID != "bitcoin" || market.CurrentPrice != 65000 || market.High24h != 66000 || market.PriceChangePercentage24h != 1.5 {
+		t.Errorf("Markets()[0] = %+v", market)
+	}
+	if market.MarketCapRank == nil || *market.MarketCapRank != 1 {
+		t.Errorf("MarketCapRank = %v, want 1", market.MarketCapRank)
+	}
+	if market.MaxSupply != nil || market.FullyDilutedValuation != nil || market.TotalSupply == nil || *market.TotalSupply != 21000000 {
+		t.Errorf("supplies = %v, %v, %v, want nulls kept as nil", market.TotalSupply, market.MaxSupply, market.FullyDilutedValuation)
+	}
+}
+
+func TestCoin(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/coins/bitcoin" || r.URL.Query().Get("market_data") != "true" {
+			t.Errorf("request = %s, want the bitcoin detail with market data", r.URL)
+		}
+		respond(w, `{
+			"id": "bitcoin", "symbol": "btc", "name": "Bitcoin",
+			"hashing_algorithm": "SHA-256", "categories": ["Cryptocurrency"],
+			"genesis_date": "2009-01-03", "market_cap_rank": 1,
+			"description": {"en": "The first cryptocurrency."},
+			"links": {"homepage": ["http://www.bitcoin.org", ""]},
+			"market_data": {
+				"current_price": {"usd": 65000, "eur": 60000},
+				"price_change_percentage_7d": -3.5,
+				"price_change_percentage_1y": null,
+				"circulating_supply": 19700000, "max_supply": 21000000
+			}
+		}`)
+	})
+
+	coin, err := client.Coin(context.Background(), "bitcoin")
+	if err != nil {
+		t.Fatalf("Coin() error = %v", err)
+	}
+	if coin.Name != "Bitcoin" || coin.HashingAlgorithm != "SHA-256" || coin.Description["en"] != "The first cryptocurrency." {
+		t.Errorf("Coin() = %+v", coin)
+	}
+	if len(coin.Links.Homepage) != 2 || coin.Links.Homepage[0] != "http://www.bitcoin.org" {
+		t.Errorf("Links = %+v", coin.Links)
+	}
+	data := coin.MarketData
+	if data == nil || data.CurrentPrice["eur"] != 60000 {
+		t.Fatalf("MarketData = %+v, want the current prices", data)
+	}
+	if data.PriceChangePercentage7d == nil || *data.PriceChangePercentage7d != -3.5 || data.PriceChangePercentage1y != nil {
+		t.Errorf("price changes = %v, %v, want -3.5 and nil", data.PriceChangePercentage7d, data.PriceChangePercentage1y)
+	}
+}
+
+func TestMarketChart(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/coins/bitcoin/market_chart" || r.URL.Query().Get("days") != "7" {
+			t.Errorf("request = %s, want 7 days of bitcoin", r.URL)
+		}
+		respond(w, `{
+			"prices": [[1700000000000, 65000.5], [1700003600000, 65100]],
+			"market_caps": [[1700000000000, 1280000000000]],
+			"total_volumes": []
+		}`)
+	})
+
+	chart, err := client.MarketChart(context.Background(), "bitcoin", "usd", "7")
+	if err != nil {
+		t.Fatalf("MarketChart() error = %v", err)
+	}
+	if len(chart.Prices) != 2 || len(chart.MarketCaps) != 1 || len(chart.TotalVolumes) != 0 {
+		t.Fatalf("MarketChart() = %d prices, %d caps, %d volumes", len(chart.Prices), len(chart.MarketCaps), len(chart.TotalVolumes))
+	}
+	first := chart.Prices[0]
+	if !first.Time.Equal(time.UnixMilli(1700000000000)) || first.Value != 65000.5 {
+		t.Errorf("Prices[0] = %+v", first)
+	}
+	if chart.Prices[1].Time.Sub(first.Time) != time.Hour {
+		t.Errorf("Prices are %v apart, want an hour", chart.Prices[1].Time.Sub(first.Time))
+	}
+}
+
+func TestMalformedResponse(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		respond(w, `{"prices": [["yesterday", 65000]]}`)
+	})
+	if _, err := client.MarketChart(context.Background(), "bitcoin", "usd", "1"); err == nil {
+		t.Error("MarketChart() with a malformed data point error = nil, want an error")
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		body       string
+		want       error
+		message    string
+		wait       time.Duration
+	}{
+		{"not found", http.StatusNotFound, "", `{"error": "coin not found"}`, ErrNotFound, "coin not found", 0},
+		{"rate limited", http.StatusTooManyRequests, "30",
+			`{"status": {"error_code": 429, "error_message": "You've exceeded the Rate Limit."}}`,
+			ErrRateLimited, "You've exceeded the Rate Limit.", 30 * time.Second},
+		{"rate limited without wait", http.StatusTooManyRequests, "soon", `{}`, ErrRateLimited, "{}", 0},
+		{"unauthorized", http.StatusUnauthorized, "", `{"status": {"error_message": "Missing API key"}}`, ErrUnauthorized, "Missing API key", 0},
+		{"forbidden", http.StatusForbidden, "", "", ErrUnauthorized, "", 0},
+		{"unavailable", http.StatusServiceUnavailable, "", " <html>Service Unavailable</html>\n", ErrUnavailable, "<html>Service Unavailable</html>", 0},
+		{"bad gateway", http.StatusBadGateway, "", "", ErrUnavailable, "", 0},
+		{"bad request", http.StatusBadRequest, "", `{"error": "invalid vs_currency"}`, ErrBadRequest, "invalid vs_currency", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := client.Coin(context.Background(), "nocoin")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Coin() error = %v, want %v", err, tt.want)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Coin() error = %T, want *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Endpoint != "/coins/nocoin" || apiErr.Message != tt.message {
+				t.Errorf("APIError = %+v, want status %d and message %q", apiErr, tt.status, tt.message)
+			}
+			if apiErr.RetryAfter != tt.wait {
+				t.Errorf("RetryAfter = %v, want %v", apiErr.RetryAfter, tt.wait)
+			}
+		})
+	}
+}
+
+func TestAPIErrorMessage(t *testing.T) {
+	err := &APIError{StatusCode: 404, Endpoint: "/coins/x", Message: "coin not found"}
+	if got := err.Error(); got != "coingecko /coins/x: status 404: coin not found" {
+		t.Errorf("Error() = %q", got)
+	}
+	err.Message = ""
+	if got := err.Error(); got != "coingecko /coins/x: status 404" {
+		t.Errorf("Error() without message = %q", got)
+	}
+
+	// Long error pages are cut
+	if got := errorMessage([]byte(strings.Repeat("x", 2*maxErrorBody))); len(got) != maxErrorBody {
+		t.Errorf("errorMessage() kept %d bytes, want %d", len(got), maxErrorBody)
+	}
+	if (&APIError{StatusCode: http.StatusFound}).Unwrap() != nil {
+		t.Error("Unwrap() of a non-error status is not nil")
+	}
+}
@@ -0,0 +1,51 @@
+package coingecko
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors returned (wrapped in an *APIError) for common upstream failures
+var (
+	ErrBadRequest   = errors.New("coingecko: bad request")
+	ErrUnauthorized = errors.New("coingecko: invalid or missing API key")
+	ErrNotFound     = errors.New("coingecko: not found")
+	ErrRateLimited  = errors.New("coingecko: rate limit exceeded")
+	ErrUnavailable  = errors.New("coingecko: service unavailable")
+)
+
+// APIError describes a non-2xx response from the CoinGecko API
+type APIError struct {
+	StatusCode int
+	Endpoint   string
+	Message    string
+	// RetryAfter is how long CoinGecko asked to wait, when it said so
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("coingecko %s: status %d", e.Endpoint, e.StatusCode)
+	}
+	return fmt.Sprintf("coingecko %s: status %d: %s", e.Endpoint, e.StatusCode, e.Message)
+}
+
+// Unwrap maps the status code to one of the sentinel errors so callers can use errors.Is
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case e.StatusCode >= 500:
+		return ErrUnavailable
+	case e.StatusCode >= 400:
+		return ErrBadRequest
+	}
+	return nil
+}
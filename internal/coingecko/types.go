@@ -0,0 +1,163 @@
+package coingecko
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SimplePriceOptions controls the optional fields requested from /simple/price
+type SimplePriceOptions struct {
+	IncludeMarketCap     bool
+	Include24hrVol       bool
+	Include24hrChange    bool
+	IncludeLastUpdatedAt bool
+}
+
+// SimplePrices maps coin ids to the currency-keyed values returned by /simple/price,
+// e.g. prices["bitcoin"]["usd"] or prices["bitcoin"]["usd_24h_change"]
+type SimplePrices map[string]map[string]float64
+
+// Quote is a typed view of a single coin/currency pair in a SimplePrices response
+type Quote struct {
+	Price         float64
+	MarketCap     float64
+	Volume24h     float64
+	Change24h     float64
+	LastUpdatedAt time.Time
+}
+
+// Quote returns the typed quote for the given coin id and vs currency
+func (p SimplePrices) Quote(id, vsCurrency string) (Quote, bool) {
+	values, ok := p[id]
+	if !ok {
+		return Quote{}, false
+	}
+	price, ok := values[vsCurrency]
+	if !ok {
+		return Quote{}, false
+	}
+
+	quote := Quote{
+		Price:     price,
+		MarketCap: values[vsCurrency+"_market_cap"],
+		Volume24h: values[vsCurrency+"_24h_vol"],
+		Change24h: values[vsCurrency+"_24h_change"],
+	}
+	if ts, ok := values["last_updated_at"]; ok && ts > 0 {
+		quote.LastUpdatedAt = time.Unix(int64(ts), 0)
+	}
+	return quote, true
+}
+
+// Market is an entry returned by /coins/markets
+type Market struct {
+	ID                           string   `json:"id"`
+	Symbol                       string   `json:"symbol"`
+	Name                         string   `json:"name"`
+	Image                        string   `json:"image"`
+	CurrentPrice                 float64  `json:"current_price"`
+	MarketCap                    float64  `json:"market_cap"`
+	MarketCapRank                *int     `json:"market_cap_rank"`
+	FullyDilutedValuation        *float64 `json:"fully_diluted_valuation"`
+	TotalVolume                  float64  `json:"total_volume"`
+	High24h                      float64  `json:"high_24h"`
+	Low24h                       float64  `json:"low_24h"`
+	PriceChange24h               float64  `json:"price_change_24h"`
+	PriceChangePercentage24h     float64  `json:"price_change_percentage_24h"`
+	MarketCapChange24h           float64  `json:"market_cap_change_24h"`
+	MarketCapChangePercentage24h float64  `json:"market_cap_change_percentage_24h"`
+	CirculatingSupply            float64  `json:"circulating_supply"`
+	TotalSupply                  *float64 `json:"total_supply"`
+	MaxSupply                    *float64 `json:"max_supply"`
+	ATH                          float64  `json:"ath"`
+	ATHChangePercentage          float64  `json:"ath_change_percentage"`
+	ATHDate                      string   `json:"ath_date"`
+	ATL                          float64  `json:"atl"`
+	ATLChangePercentage          float64  `json:"atl_change_percentage"`
+	ATLDate                      string   `json:"atl_date"`
+	LastUpdated                  string   `json:"last_updated"`
+}
+
+// CoinDetail is the subset of /coins/{id} used by the bot
+type CoinDetail struct {
+	ID                           string            `json:"id"`
+	Symbol                       string            `json:"symbol"`
+	Name                         string            `json:"name"`
+	HashingAlgorithm             string            `json:"hashing_algorithm"`
+	Categories                   []string          `json:"categories"`
+	GenesisDate                  string            `json:"genesis_date"`
+	SentimentVotesUpPercentage   *float64          `json:"sentiment_votes_up_percentage"`
+	SentimentVotesDownPercentage *float64          `json:"sentiment_votes_down_percentage"`
+	MarketCapRank                *int              `json:"market_cap_rank"`
+	Description                  map[string]string `json:"description"`
+	Links                        CoinLinks         `json:"links"`
+	MarketData                   *CoinMarketData   `json:"market_data"`
+	LastUpdated                  string            `json:"last_updated"`
+}
+
+// CoinLinks holds the project links of a coin
+type CoinLinks struct {
+	Homepage []string `json:"homepage"`
+}
+
+// CoinMarketData is the market_data object of /coins/{id}
+type CoinMarketData struct {
+	CurrentPrice              map[string]float64 `json:"current_price"`
+	MarketCap                 map[string]float64 `json:"market_cap"`
+	TotalVolume               map[string]float64 `json:"total_volume"`
+	PriceChangePercentage24h  *float64           `json:"price_change_percentage_24h"`
+	PriceChangePercentage7d   *float64           `json:"price_change_percentage_7d"`
+	PriceChangePercentage14d  *float64           `json:"price_change_percentage_14d"`
+	PriceChangePercentage30d  *float64           `json:"price_change_percentage_30d"`
+	PriceChangePercentage60d  *float64           `json:"price_change_percentage_60d"`
+	PriceChangePercentage200d *float64           `json:"price_change_percentage_200d"`
+	PriceChangePercentage1y   *float64           `json:"price_change_percentage_1y"`
+	CirculatingSupply         float64            `json:"circulating_supply"`
+	TotalSupply               *float64           `json:"total_supply"`
+	MaxSupply                 *float64           `json:"max_supply"`
+}
+
+// CoinListEntry is an entry returned by /coins/list
+type CoinListEntry struct {
+	ID     string `json:"id"`
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+}
+
+// SearchResult is the response of /search
+type SearchResult struct {
+	Coins []SearchCoin `json:"coins"`
+}
+
+// SearchCoin is a coin entry of a SearchResult
+type SearchCoin struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Symbol        string `json:"symbol"`
+	MarketCapRank *int   `json:"market_cap_rank"`
+}
+
+// MarketChart is the response of /coins/{id}/market_chart
+type MarketChart struct {
+	Prices       []DataPoint `json:"prices"`
+	MarketCaps   []DataPoint `json:"market_caps"`
+	TotalVolumes []DataPoint `json:"total_volumes"`
+}
+
+// DataPoint is a single [timestamp_ms, value] pair of a market chart series
+type DataPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// UnmarshalJSON decodes the [timestamp_ms, value] array form used by CoinGecko
+func (p *DataPoint) UnmarshalJSON(data []byte) error {
+	var raw [2]float64
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid data point %s: %w", string(data), err)
+	}
+	p.Time = time.UnixMilli(int64(raw[0]))
+	p.Value = raw[1]
+	return nil
+}
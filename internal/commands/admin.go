@@ -0,0 +1,217 @@
+package commands
+
+import (
+	"blockmind/internal/i18n"
+	"blockmind/internal/knowledge"
+	"blockmind/internal/middleware"
+	"blockmind/internal/storage"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// phonePattern matches a phone number given instead of a full JID
+var phonePattern = regexp.MustCompile(`^\+?[0-9]{6,15}$`)
+
+// AdminCommand runs maintenance tasks and manages user roles. Only admins
+// may use it, and only the owner may grant roles.
+type AdminCommand struct {
+	knowledge *knowledge.Base
+	store     *storage.Store
+}
+
+// NewAdminCommand creates a new admin command. The knowledge base may be nil
+// when it is disabled.
+func NewAdminCommand(kb *knowledge.Base, store *storage.Store) *AdminCommand {
+	return &AdminCommand{
+		knowledge: kb,
+		store:     store,
+	}
+}
+
+// Name returns the name of the command
+func (c *AdminCommand) Name() string {
+	return "admin"
+}
+
+// Aliases returns alternative names for the command
+func (c *AdminCommand) Aliases() []string {
+	return []string{}
+}
+
+// Description returns the description of the command
+func (c *AdminCommand) Description() string {
+	return i18n.Translate(i18n.DefaultLocale, "desc.admin")
+}
+
+// Permission returns the least privileged role allowed to run the command
+func (c *AdminCommand) Permission() string {
+	return storage.RoleAdmin
+}
+
+// Spec returns the arguments the command accepts
+func (c *AdminCommand) Spec() Spec {
+	user := Arg{Name: "user"}
+	return Spec{
+		Subcommands: []Subcommand{
+			{Name: "reindex"},
+			{Name: "block", Aliases: []string{"ban", "bloquear"}, Args: []Arg{user}},
+			{Name: "unblock", Aliases: []string{"unban", "desbloquear"}, Args: []Arg{user}},
+			{Name: "role", Aliases: []string{"rol", "papel"}, Args: []Arg{user, {
+				Name:     "role",
+				Kind:     KindChoice,
+				Values:   []string{storage.RoleAdmin, storage.RoleUser},
+				Optional: true,
+			}}},
+			{Name: "stats", Aliases: []string{"estadisticas", "estatisticas"}},
+		},
+	}
+}
+
+// Help returns a long description of the command in locale
+func (c *AdminCommand) Help(locale string) string {
+	return i18n.Translate(locale, "details.admin")
+}
+
+// Examples returns sample uses of the command, explained in locale
+func (c *AdminCommand) Examples(locale string) []Example {
+	return []Example{
+		{Input: "/admin reindex", Effect: i18n.Translate(locale, "example.admin.reindex")},
+		{Input: "/admin block 34600111222", Effect: i18n.Translate(locale, "example.admin.block")},
+		{Input: "/admin unblock 34600111222", Effect: i18n.Translate(locale, "example.admin.unblock")},
+		{Input: "/admin role 34600111222 admin", Effect: i18n.Translate(locale, "example.admin.role")},
+		{Input: "/admin stats", Effect: i18n.Translate(locale, "example.admin.stats")},
+	}
+}
+
+// Execute executes the command with the given arguments
+func (c *AdminCommand) Execute(ctx context.Context, args []string) (string, error) {
+	parsed, reply := parseArgs(ctx, c, args)
+	if reply != "" {
+		return reply, nil
+	}
+
+	switch parsed.Subcommand {
+	case "reindex":
+		return c.reindex(ctx)
+	case "stats":
+		return c.stats(ctx)
+	}
+
+	target, ok := normalizeJID(parsed.String("user"))
+	if !ok {
+		return i18n.T(ctx, "admin.invalid_user", parsed.String("user")), nil
+	}
+
+	switch parsed.Subcommand {
+	case "block":
+		return c.setRole(ctx, target, storage.RoleBlocked)
+	case "unblock":
+		return c.unblock(ctx, target)
+	case "role":
+		if !parsed.Has("role") {
+			return c.showRole(ctx, target)
+		}
+		if !atLeast(RoleFromContext(ctx), storage.RoleOwner) {
+			return i18n.T(ctx, "admin.owner_only"), nil
+		}
+		return c.setRole(ctx, target, parsed.String("role"))
+	}
+	return "", fmt.Errorf("admin action %q is not implemented", parsed.Subcommand)
+}
+
+// setRole gives target a new role. Admins cannot change their own role or
+// that of users as privileged as them.
+func (c *AdminCommand) setRole(ctx context.Context, target, role string) (string, error) {
+	current, err := c.store.GetRole(ctx, target)
+	if err != nil {
+		return "", err
+	}
+
+	userJID, _ := middleware.GetUserJID(ctx)
+	if target == userJID {
+		return i18n.T(ctx, "admin.self"), nil
+	}
+	if atLeast(current, RoleFromContext(ctx)) {
+		return i18n.T(ctx, "admin.not_above", displayJID(target), i18n.T(ctx, "role."+current)), nil
+	}
+
+	if err := c.store.SetRole(ctx, target, role); err != nil {
+		return "", err
+	}
+	if role == storage.RoleBlocked {
+		return i18n.T(ctx, "admin.blocked", displayJID(target)), nil
+	}
+	return i18n.T(ctx, "admin.role_set", displayJID(target), i18n.T(ctx, "role."+role)), nil
+}
+
+// unblock gives a blocked user the user role back
+func (c *AdminCommand) unblock(ctx context.Context, target string) (string, error) {
+	current, err := c.store.GetRole(ctx, target)
+	if err != nil {
+		return "", err
+	}
+	if current != storage.RoleBlocked {
+		return i18n.T(ctx, "admin.not_blocked", displayJID(target)), nil
+	}
+
+	if err := c.store.SetRole(ctx, target, storage.RoleUser); err != nil {
+		return "", err
+	}
+	return i18n.T(ctx, "admin.unblocked", displayJID(target)), nil
+}
+
+// showRole tells the role of target
+func (c *AdminCommand) showRole(ctx context.Context, target string) (string, error) {
+	role, err := c.store.GetRole(ctx, target)
+	if err != nil {
+		return "", err
+	}
+	return i18n.T(ctx, "admin.role", displayJID(target), i18n.T(ctx, "role."+role)), nil
+}
+
+// stats summarizes the users of the bot and the data they keep in it
+func (c *AdminCommand) stats(ctx context.Context) (string, error) {
+	stats, err := c.store.Stats(ctx)
+	if err != nil {
+		return "", err
+	}
+	return i18n.T(ctx, "admin.stats", stats.Users, stats.Admins, stats.Blocked, stats.Alerts,
+		stats.Subscriptions, stats.Positions, stats.WatchedCoins, stats.Recommendations), nil
+}
+
+// reindex rebuilds the knowledge base index from its directory
+func (c *AdminCommand) reindex(ctx context.Context) (string, error) {
+	if c.knowledge == nil {
+		return i18n.T(ctx, "admin.knowledge_disabled"), nil
+	}
+
+	stats, err := c.knowledge.Reindex()
+	if err != nil {
+		return "", fmt.Errorf("failed to reindex %s: %w", c.knowledge.Dir(), err)
+	}
+	return i18n.T(ctx, "admin.reindexed",
+		stats.Documents, stats.Passages, stats.Duration.Round(time.Millisecond)), nil
+}
+
+// normalizeJID turns a phone number such as +34600111222 into a WhatsApp
+// JID; full JIDs are returned unchanged
+func normalizeJID(user string) (string, bool) {
+	if strings.Contains(user, "@") {
+		return strings.ToLower(user), true
+	}
+	if !phonePattern.MatchString(user) {
+		return "", false
+	}
+	return strings.TrimPrefix(user, "+") + "@s.whatsapp.net", true
+}
+
+// displayJID shows a user JID as the phone number it belongs to
+func displayJID(jid string) string {
+	if phone, found := strings.CutSuffix(jid, "@s.whatsapp.net"); found {
+		return "+" + phone
+	}
+	return jid
+}
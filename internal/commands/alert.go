@@ -0,0 +1,279 @@
+package commands
+
+import (
+	"blockmind/internal/alerts"
+	"blockmind/internal/crypto"
+	"blockmind/internal/i18n"
+	"blockmind/internal/middleware"
+	"blockmind/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxAlertsPerUser limits the number of active alerts a chat can have
+const maxAlertsPerUser = 20
+
+// alertOperatorPattern matches comparison operators, including ones typed
+// without spaces such as "btc>70000"
+var alertOperatorPattern = regexp.MustCompile(`(>=|<=|>|<)`)
+
+// currencyPattern matches a vs currency code such as usd or eur
+var currencyPattern = regexp.MustCompile(`^[a-zA-Z]{3,5}$`)
+
+// AlertCommand manages price alerts of the current chat
+type AlertCommand struct {
+	store    *storage.Store
+	resolver *crypto.Resolver
+}
+
+// NewAlertCommand creates a new alert command
+func NewAlertCommand(store *storage.Store, resolver *crypto.Resolver) *AlertCommand {
+	return &AlertCommand{
+		store:    store,
+		resolver: resolver,
+	}
+}
+
+// Name returns the name of the command
+func (c *AlertCommand) Name() string {
+	return "alert"
+}
+
+// Aliases returns alternative names for the command
+func (c *AlertCommand) Aliases() []string {
+	return []string{"alerts", "alerta", "alertas"}
+}
+
+// Description returns the description of the command
+func (c *AlertCommand) Description() string {
+	return i18n.Translate(i18n.DefaultLocale, "desc.alert")
+}
+
+// Spec returns the arguments the command accepts
+func (c *AlertCommand) Spec() Spec {
+	return Spec{
+		Subcommands: []Subcommand{
+			{
+				Name:    "add",
+				Aliases: []string{"new", "crear", "criar"},
+				Args: []Arg{
+					{Name: "coin", Repeated: true},
+					{Name: "condition", Kind: KindChoice, Values: []string{">", "<"}, Synonyms: map[string]string{
+						">=": ">", "above": ">", "over": ">", "encima": ">", "acima": ">",
+						"<=": "<", "below": "<", "under": "<", "debajo": "<", "abaixo": "<",
+					}},
+					{Name: "price", Kind: KindNumber},
+					{Name: "currency", Kind: KindCurrency, Optional: true},
+					{Name: "mode", Kind: KindChoice, Values: []string{"repeat", "once"}, Optional: true, Synonyms: map[string]string{
+						"repetir": "repeat", "always": "repeat", "siempre": "repeat", "sempre": "repeat",
+					}},
+				},
+			},
+			{Name: "list", Aliases: []string{"lista"}},
+			{Name: "delete", Aliases: []string{"remove", "del", "rm", "borrar", "eliminar", "excluir"}, Args: []Arg{
+				{Name: "id", Kind: KindInteger},
+			}},
+		},
+		Default: "add",
+		Empty:   "list",
+	}
+}
+
+// Help returns a long description of the command in locale
+func (c *AlertCommand) Help(locale string) string {
+	return i18n.Translate(locale, "details.alert")
+}
+
+// Examples returns sample uses of the command, explained in locale
+func (c *AlertCommand) Examples(locale string) []Example {
+	return []Example{
+		{Input: "/alert btc > 70000", Effect: i18n.Translate(locale, "example.alert.above")},
+		{Input: "/alert eth < 3000 eur repeat", Effect: i18n.Translate(locale, "example.alert.repeat")},
+		{Input: "/alert list", Effect: i18n.Translate(locale, "example.alert.list")},
+		{Input: "/alert delete 3", Effect: i18n.Translate(locale, "example.alert.delete")},
+	}
+}
+
+// Execute executes the command with the given arguments
+func (c *AlertCommand) Execute(ctx context.Context, args []string) (string, error) {
+	userJID, ok := middleware.GetUserJID(ctx)
+	if !ok {
+		return "", fmt.Errorf("alert command requires a user JID in the context")
+	}
+
+	// Operators may be typed without spaces, as in "btc>70000"
+	args = strings.Fields(alertOperatorPattern.ReplaceAllString(strings.Join(args, " "), " $1 "))
+
+	parsed, reply := parseArgs(ctx, c, args)
+	if reply != "" {
+		return reply, nil
+	}
+
+	switch parsed.Subcommand {
+	case "list":
+		return c.list(ctx, userJID)
+	case "delete":
+		return c.delete(ctx, userJID, parsed.Integer("id"))
+	}
+	return c.create(ctx, userJID, parsed)
+}
+
+// list shows the active alerts of the user
+func (c *AlertCommand) list(ctx context.Context, userJID string) (string, error) {
+	active, err := c.store.ListAlerts(ctx, userJID)
+	if err != nil {
+		return "", err
+	}
+	if len(active) == 0 {
+		return i18n.T(ctx, "alert.none"), nil
+	}
+
+	locale := i18n.FromContext(ctx)
+	var reply strings.Builder
+	reply.WriteString(i18n.T(ctx, "alert.list.title") + "\n")
+	for _, alert := range active {
+		reply.WriteString(alerts.FormatAlert(alert, locale) + "\n")
+	}
+	reply.WriteString("\n" + i18n.T(ctx, "alert.list.footer"))
+	return reply.String(), nil
+}
+
+// delete removes an alert by id
+func (c *AlertCommand) delete(ctx context.Context, userJID string, id int64) (string, error) {
+	if err := c.store.DeleteAlert(ctx, userJID, id); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return i18n.T(ctx, "alert.not_found", id), nil
+		}
+		return "", err
+	}
+	return i18n.T(ctx, "alert.deleted", id), nil
+}
+
+// create stores the alert of "<coin> <condition> <price> [currency] [mode]"
+func (c *AlertCommand) create(ctx context.Context, userJID string, parsed Parsed) (string, error) {
+	condition := storage.AlertAbove
+	if parsed.String("condition") == "<" {
+		condition = storage.AlertBelow
+	}
+	target := parsed.Number("price")
+	repeat := parsed.String("mode") == "repeat"
+	currency := "usd"
+	if parsed.Has("currency") {
+		currency = parsed.String("currency")
+	}
+
+	count, err := c.store.CountAlerts(ctx, userJID)
+	if err != nil {
+		return "", err
+	}
+	if count >= maxAlertsPerUser {
+		return i18n.T(ctx, "alert.limit", count), nil
+	}
+
+	coin, reply, err := resolveCoin(ctx, c.resolver, parsed.String("coin"), c.Name())
+	if err != nil || reply != "" {
+		return reply, err
+	}
+
+	alert := &storage.Alert{
+		UserJID:    userJID,
+		ChatJID:    deliveryChat(ctx, userJID),
+		CoinID:     coin.ID,
+		CoinSymbol: coin.Symbol,
+		CoinName:   coin.Name,
+		Currency:   currency,
+		Condition:  condition,
+		Target:     target,
+		Repeat:     repeat,
+	}
+	if err := c.store.CreateAlert(ctx, alert); err != nil {
+		return "", err
+	}
+
+	mode := i18n.T(ctx, "alert.mode.once")
+	if repeat {
+		mode = i18n.T(ctx, "alert.mode.repeat")
+	}
+	return i18n.T(ctx, "alert.created", alert.ID, coin, i18n.T(ctx, "alert.condition."+condition),
+		crypto.FormatPrice(target, currency), mode), nil
+}
+
+// parseAmount parses amounts such as "70000", "70,000", "0.5", "0,5",
+// "1.234,56" or "70k"
+func parseAmount(text string) (float64, error) {
+	text, err := decimalText(strings.ToLower(text))
+	if err != nil {
+		return 0, err
+	}
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(text, "k"):
+		multiplier, text = 1e3, strings.TrimSuffix(text, "k")
+	case strings.HasSuffix(text, "m"):
+		multiplier, text = 1e6, strings.TrimSuffix(text, "m")
+	}
+
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, err
+	}
+	value *= multiplier
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, errors.New("amount is not a finite number")
+	}
+	return value, nil
+}
+
+// decimalText rewrites an amount written with thousands separators or a
+// decimal comma, as in Spanish and Portuguese, in the form strconv parses.
+// Commas that are neither clearly decimal nor groups of three are rejected.
+func decimalText(text string) (string, error) {
+	commas := strings.Count(text, ",")
+	if commas == 0 {
+		return text, nil
+	}
+	comma := strings.LastIndex(text, ",")
+	dot := strings.Index(text, ".")
+	decimals := len(strings.TrimRight(text[comma+1:], "km"))
+
+	switch {
+	case dot < 0 && commas == 1 && decimals >= 1 && decimals <= 2:
+		// "0,5": a decimal comma
+		return text[:comma] + "." + text[comma+1:], nil
+	case dot >= 0 && dot < comma:
+		// "1.234,56": thousands dots and a decimal comma
+		if commas == 1 && thousandsGrouped(text[:comma], ".") {
+			return strings.ReplaceAll(text[:comma], ".", "") + "." + text[comma+1:], nil
+		}
+	default:
+		// "70,000" and "1,234.56": thousands commas
+		whole := strings.TrimRight(text, "km")
+		if dot >= 0 {
+			whole = text[:dot]
+		}
+		if thousandsGrouped(whole, ",") {
+			return strings.ReplaceAll(text, ",", ""), nil
+		}
+	}
+	return "", fmt.Errorf("amount %q has ambiguous separators", text)
+}
+
+// thousandsGrouped reports whether every separator in text is followed by
+// exactly three characters, as in "1,234,567"
+func thousandsGrouped(text, separator string) bool {
+	groups := strings.Split(text, separator)
+	if groups[0] == "" {
+		return false
+	}
+	for _, group := range groups[1:] {
+		if len(group) != 3 {
+			return false
+		}
+	}
+	return true
+}
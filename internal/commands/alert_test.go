@@ -0,0 +1,52 @@
+package commands
+
+import "testing"
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		text string
+		want float64
+		ok   bool
+	}{
+		{"70000", 70000, true},
+		{"0.5", 0.5, true},
+		{"70,000", 70000, true},
+		{"1,234,567", 1234567, true},
+		{"1,234.56", 1234.56, true},
+		// Spanish and Portuguese decimal commas
+		{"0,5", 0.5, true},
+		{"12,34", 12.34, true},
+		{"1.234,56", 1234.56, true},
+		{"1.234.567,8", 1234567.8, true},
+		{"70k", 70000, true},
+		{"70K", 70000, true},
+		{"1,5k", 1500, true},
+		{"70,000k", 70000000, true},
+		{"2m", 2000000, true},
+		// Ambiguous separators
+		{"1,2345", 0, false},
+		{"1,23,456", 0, false},
+		{"1,234.567,8", 0, false},
+		{"1.23,4", 0, false},
+		{"0,", 0, false},
+		{",5", 0.5, true},
+		// Not finite
+		{"NaN", 0, false},
+		{"inf", 0, false},
+		{"-Infinity", 0, false},
+		{"1e308k", 0, false},
+		{"1e400", 0, false},
+		{"", 0, false},
+		{"abc", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := parseAmount(tt.text)
+		if (err == nil) != tt.ok {
+			t.Errorf("parseAmount(%q) error = %v, want ok %v", tt.text, err, tt.ok)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseAmount(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,524 @@
+package commands
+
+import (
+	"blockmind/internal/i18n"
+	"context"
+	"strconv"
+	"strings"
+)
+
+// ArgKind is the type of value an argument accepts
+type ArgKind int
+
+const (
+	// KindText accepts any word
+	KindText ArgKind = iota
+	// KindNumber accepts positive amounts such as 70000, 0.5 or 70k
+	KindNumber
+	// KindInteger accepts whole numbers such as ids, optionally written as #3
+	KindInteger
+	// KindCurrency accepts currency codes such as usd or eur
+	KindCurrency
+	// KindTime accepts 24-hour times such as 08:00
+	KindTime
+	// KindChoice accepts only the argument's values and synonyms
+	KindChoice
+)
+
+// Arg declares a positional argument
+type Arg struct {
+	Name string
+	Kind ArgKind
+	// Values are accepted words shown in the usage, such as the choices of a
+	// KindChoice argument or "all" for an amount
+	Values []string
+	// Synonyms map other accepted words to one of Values
+	Synonyms map[string]string
+	// Keywords introduce the argument, as "in" does in "/price btc in eur"
+	Keywords []string
+	Optional bool
+	// Repeated arguments take one or more words
+	Repeated bool
+}
+
+// Flag declares an option written as --name or --name=value anywhere in the
+// arguments
+type Flag struct {
+	Name    string
+	Aliases []string
+	// Value describes the value of the flag; flags without one are switches
+	Value *Arg
+	// Synonyms are plain words that turn a switch on wherever they appear,
+	// as "full" in "/price btc full"
+	Synonyms []string
+}
+
+// Subcommand declares an action of a command with its own arguments, such as
+// "delete" in "/alert delete 3"
+type Subcommand struct {
+	Name    string
+	Aliases []string
+	Args    []Arg
+	Flags   []Flag
+}
+
+// Spec declares the arguments of a command. Commands with subcommands take
+// their arguments from the subcommand; Flags apply to all of them.
+type Spec struct {
+	Args        []Arg
+	Flags       []Flag
+	Subcommands []Subcommand
+	// Default names the subcommand used when the first word names none
+	Default string
+	// Empty names the subcommand used when there are no arguments
+	Empty string
+}
+
+// Specified is implemented by commands that declare their arguments, so they
+// are validated and their usage is generated from the spec
+type Specified interface {
+	Command
+	// Spec returns the arguments the command accepts
+	Spec() Spec
+}
+
+// ArgError describes why arguments do not match a spec
+type ArgError struct {
+	// Key is the catalog key of the message
+	Key string
+	// Word is the offending word, if any
+	Word string
+	// Arg is the argument concerned, if any
+	Arg *Arg
+}
+
+// Message renders the error in locale. The message takes the word first and
+// then the argument, each only when set.
+func (e *ArgError) Message(locale string) string {
+	var args []interface{}
+	if e.Word != "" {
+		args = append(args, e.Word)
+	}
+	if e.Arg != nil {
+		args = append(args, e.Arg.placeholder(locale))
+	}
+	return i18n.Translate(locale, e.Key, args...)
+}
+
+func (e *ArgError) Error() string {
+	return e.Message(i18n.DefaultLocale)
+}
+
+// Parsed holds the arguments matched by a spec. Values are stored as typed:
+// choices by their canonical value and currencies in lower case.
+type Parsed struct {
+	// Subcommand is the name of the selected subcommand, if any
+	Subcommand string
+	values     map[string][]string
+	flags      map[string]string
+}
+
+// Has reports whether an argument or flag was given
+func (p Parsed) Has(name string) bool {
+	if _, ok := p.values[name]; ok {
+		return true
+	}
+	_, ok := p.flags[name]
+	return ok
+}
+
+// String returns the words of an argument joined by spaces
+func (p Parsed) String(name string) string {
+	return strings.Join(p.values[name], " ")
+}
+
+// Strings returns the words of an argument
+func (p Parsed) Strings(name string) []string {
+	return p.values[name]
+}
+
+// Number returns a KindNumber argument, or 0 when it was not given
+func (p Parsed) Number(name string) float64 {
+	value, _ := parseAmount(p.String(name))
+	return value
+}
+
+// Integer returns a KindInteger argument, or 0 when it was not given
+func (p Parsed) Integer(name string) int64 {
+	value, _ := strconv.ParseInt(p.String(name), 10, 64)
+	return value
+}
+
+// Flag returns the value of a flag; switches have an empty value
+func (p Parsed) Flag(name string) (string, bool) {
+	value, ok := p.flags[name]
+	return value, ok
+}
+
+// parseArgs parses the arguments of a command against its spec. When they
+// are invalid, the reply explains why and shows the usage of the command.
+func parseArgs(ctx context.Context, cmd Specified, args []string) (Parsed, string) {
+	parsed, err := cmd.Spec().Parse(args)
+	if err != nil {
+		var reply strings.Builder
+		reply.WriteString(err.Message(i18n.FromContext(ctx)))
+		reply.WriteString("\n\n" + i18n.T(ctx, "args.usage"))
+		for _, line := range cmd.Spec().Usage(cmd.Name(), i18n.FromContext(ctx)) {
+			reply.WriteString("\n" + line)
+		}
+		return Parsed{}, reply.String()
+	}
+	return parsed, ""
+}
+
+// Parse matches args against the spec
+func (s Spec) Parse(args []string) (Parsed, *ArgError) {
+	parsed := Parsed{values: make(map[string][]string), flags: make(map[string]string)}
+
+	positional, flags := s.Args, s.Flags
+	if len(s.Subcommands) > 0 {
+		var sub *Subcommand
+		switch {
+		case len(args) == 0 && s.Empty != "":
+			sub = s.subcommand(s.Empty)
+		case len(args) > 0 && s.subcommand(args[0]) != nil:
+			sub, args = s.subcommand(args[0]), args[1:]
+		case s.Default != "":
+			sub = s.subcommand(s.Default)
+		case len(args) == 0:
+			return parsed, &ArgError{Key: "args.missing_subcommand"}
+		default:
+			return parsed, &ArgError{Key: "args.unknown_subcommand", Word: args[0]}
+		}
+		parsed.Subcommand = sub.Name
+		positional, flags = sub.Args, append(append([]Flag{}, s.Flags...), sub.Flags...)
+	}
+
+	words, err := parseFlags(args, flags, parsed.flags)
+	if err != nil {
+		return parsed, err
+	}
+
+	m := &matcher{words: words, values: parsed.values, reserved: reservedWords(positional), failAt: -1}
+	if !m.match(positional, 0) {
+		return parsed, m.failure
+	}
+	return parsed, nil
+}
+
+// subcommand finds a subcommand by name or alias
+func (s Spec) subcommand(name string) *Subcommand {
+	name = strings.ToLower(name)
+	for i, sub := range s.Subcommands {
+		if sub.Name == name || containsWord(sub.Aliases, name) {
+			return &s.Subcommands[i]
+		}
+	}
+	return nil
+}
+
+// Usage renders one usage line per subcommand, e.g. "/alert delete <id>".
+// Argument names are translated into locale when the catalog has them.
+func (s Spec) Usage(command, locale string) []string {
+	if len(s.Subcommands) == 0 {
+		return []string{usageLine("/"+command, s.Args, s.Flags, locale)}
+	}
+
+	lines := make([]string, len(s.Subcommands))
+	for i, sub := range s.Subcommands {
+		name := sub.Name
+		if sub.Name == s.Default {
+			name = "[" + name + "]"
+		}
+		lines[i] = usageLine("/"+command+" "+name, sub.Args, append(append([]Flag{}, s.Flags...), sub.Flags...), locale)
+	}
+	return lines
+}
+
+// usageLine renders a command followed by its arguments and flags
+func usageLine(prefix string, args []Arg, flags []Flag, locale string) string {
+	parts := []string{prefix}
+	for _, arg := range args {
+		parts = append(parts, arg.usage(locale))
+	}
+	for _, flag := range flags {
+		option := "--" + flag.Name
+		if flag.Value != nil {
+			option += " " + flag.Value.placeholder(locale)
+		}
+		parts = append(parts, "["+option+"]")
+	}
+	return strings.Join(parts, " ")
+}
+
+// usage renders an argument, e.g. "[in <currency>...]"
+func (a Arg) usage(locale string) string {
+	text := a.placeholder(locale)
+	if a.Repeated {
+		text += "..."
+	}
+	if len(a.Keywords) > 0 {
+		text = a.Keywords[0] + " " + text
+	}
+	if a.Optional {
+		text = "[" + text + "]"
+	}
+	return text
+}
+
+// placeholder renders the value of an argument, e.g. "<price>" or "above|below"
+func (a Arg) placeholder(locale string) string {
+	if a.Kind == KindChoice {
+		return strings.Join(a.Values, "|")
+	}
+
+	name := a.Name
+	if key := "arg." + a.Name; i18n.Has(key) {
+		name = i18n.Translate(locale, key)
+	}
+	return strings.Join(append([]string{"<" + name + ">"}, a.Values...), "|")
+}
+
+// convert validates one word for the argument and returns its stored form
+func (a Arg) convert(word string) (string, *ArgError) {
+	lower := strings.ToLower(word)
+	if containsWord(a.Values, lower) {
+		return lower, nil
+	}
+	if value, ok := a.Synonyms[lower]; ok {
+		return value, nil
+	}
+
+	switch a.Kind {
+	case KindNumber:
+		if value, err := parseAmount(word); err != nil || value <= 0 {
+			return "", &ArgError{Key: "args.invalid_number", Word: word, Arg: &a}
+		}
+		return word, nil
+	case KindInteger:
+		if _, err := strconv.ParseInt(strings.TrimPrefix(word, "#"), 10, 64); err != nil {
+			return "", &ArgError{Key: "args.invalid_integer", Word: word, Arg: &a}
+		}
+		return strings.TrimPrefix(word, "#"), nil
+	case KindCurrency:
+		if !currencyPattern.MatchString(word) {
+			return "", &ArgError{Key: "args.invalid_currency", Word: word}
+		}
+		return lower, nil
+	case KindTime:
+		if !timeOfDayPattern.MatchString(word) {
+			return "", &ArgError{Key: "args.invalid_time", Word: word}
+		}
+		return word, nil
+	case KindChoice:
+		return "", &ArgError{Key: "args.invalid_choice", Word: word, Arg: &a}
+	}
+	return word, nil
+}
+
+// parseFlags removes the flags from args, storing their values in parsed.
+// Phones often turn "--" into a dash, so those are accepted too.
+func parseFlags(args []string, flags []Flag, parsed map[string]string) ([]string, *ArgError) {
+	var words []string
+	for i := 0; i < len(args); i++ {
+		name, ok := flagName(args[i])
+		if !ok {
+			if flag := findSwitchSynonym(flags, strings.ToLower(args[i])); flag != nil {
+				parsed[flag.Name] = ""
+			} else {
+				words = append(words, args[i])
+			}
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(name, "=")
+		flag := findFlag(flags, strings.ToLower(name))
+		if flag == nil {
+			return nil, &ArgError{Key: "args.unknown_flag", Word: name}
+		}
+
+		if flag.Value == nil {
+			parsed[flag.Name] = ""
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return nil, &ArgError{Key: "args.flag_value", Word: flag.Name}
+			}
+			i++
+			value = args[i]
+		}
+		converted, err := flag.Value.convert(value)
+		if err != nil {
+			return nil, err
+		}
+		parsed[flag.Name] = converted
+	}
+	return words, nil
+}
+
+// flagName returns the name of a word written as a flag
+func flagName(word string) (string, bool) {
+	for _, prefix := range []string{"--", "—", "–"} {
+		if name := strings.TrimPrefix(word, prefix); name != word && name != "" {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// findFlag finds a flag by name or alias
+func findFlag(flags []Flag, name string) *Flag {
+	for i, flag := range flags {
+		if flag.Name == name || containsWord(flag.Aliases, name) {
+			return &flags[i]
+		}
+	}
+	return nil
+}
+
+// findSwitchSynonym finds the switch a plain word turns on
+func findSwitchSynonym(flags []Flag, word string) *Flag {
+	for i, flag := range flags {
+		if flag.Value == nil && containsWord(flag.Synonyms, word) {
+			return &flags[i]
+		}
+	}
+	return nil
+}
+
+// reservedWords collects the values, synonyms and keywords of args. Other
+// arguments never take them, so in "/alert btc > 70000 once" the word "once"
+// is not mistaken for a currency.
+func reservedWords(args []Arg) map[string]bool {
+	reserved := make(map[string]bool)
+	for _, arg := range args {
+		for _, word := range append(append([]string{}, arg.Values...), arg.Keywords...) {
+			reserved[word] = true
+		}
+		for word := range arg.Synonyms {
+			reserved[word] = true
+		}
+	}
+	return reserved
+}
+
+// matcher assigns words to positional arguments. Optional arguments are
+// taken when possible and repeated ones take as few words as possible, trying
+// the alternatives until every word is assigned.
+type matcher struct {
+	words    []string
+	values   map[string][]string
+	reserved map[string]bool
+	// failure is the error at the furthest word reached, the most useful one
+	// to report
+	failure *ArgError
+	failAt  int
+}
+
+// fail records why matching stopped at word pos
+func (m *matcher) fail(pos int, err *ArgError) {
+	if pos > m.failAt {
+		m.failAt, m.failure = pos, err
+	}
+}
+
+// missing describes an argument that was expected at pos
+func (m *matcher) missing(arg *Arg, pos int) *ArgError {
+	if pos < len(m.words) {
+		return &ArgError{Key: "args.unexpected", Word: m.words[pos]}
+	}
+	return &ArgError{Key: "args.missing", Arg: arg}
+}
+
+// match reports whether words from pos on match args
+func (m *matcher) match(args []Arg, pos int) bool {
+	if len(args) == 0 {
+		if pos < len(m.words) {
+			m.fail(pos, &ArgError{Key: "args.unexpected", Word: m.words[pos]})
+			return false
+		}
+		return true
+	}
+
+	arg := args[0]
+	if m.take(args, pos) {
+		return true
+	}
+	return arg.Optional && m.match(args[1:], pos)
+}
+
+// take tries to match the first of args at pos, then the rest after it
+func (m *matcher) take(args []Arg, pos int) bool {
+	arg := args[0]
+	if len(arg.Keywords) > 0 {
+		if pos == len(m.words) || !containsWord(arg.Keywords, strings.ToLower(m.words[pos])) {
+			if !arg.Optional {
+				m.fail(pos, m.missing(&arg, pos))
+			}
+			return false
+		}
+		pos++
+	}
+	if pos == len(m.words) {
+		m.fail(pos, m.missing(&arg, pos))
+		return false
+	}
+
+	count := 1
+	if arg.Repeated {
+		count = len(m.words) - pos
+	}
+
+	var taken []string
+	for n := 0; n < count; n++ {
+		word := m.words[pos+n]
+		if m.reserved[strings.ToLower(word)] && !arg.accepts(word) {
+			m.fail(pos+n, &ArgError{Key: "args.unexpected", Word: word})
+			break
+		}
+
+		// Repeated values other than text may also be separated by commas
+		items := []string{word}
+		if arg.Repeated && arg.Kind != KindText {
+			items = splitList(word)
+		}
+		failed := false
+		for _, item := range items {
+			value, err := arg.convert(item)
+			if err != nil {
+				m.fail(pos+n, err)
+				failed = true
+				break
+			}
+			taken = append(taken, value)
+		}
+		if failed {
+			break
+		}
+
+		m.values[arg.Name] = taken
+		if m.match(args[1:], pos+n+1) {
+			return true
+		}
+	}
+	delete(m.values, arg.Name)
+	return false
+}
+
+// accepts reports whether word is one of the argument's own values or synonyms
+func (a Arg) accepts(word string) bool {
+	word = strings.ToLower(word)
+	_, ok := a.Synonyms[word]
+	return ok || containsWord(a.Values, word)
+}
+
+// containsWord reports whether list contains word
+func containsWord(list []string, word string) bool {
+	for _, item := range list {
+		if item == word {
+			return true
+		}
+	}
+	return false
+}
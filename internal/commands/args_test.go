@@ -0,0 +1,298 @@
+package commands
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// chartFlags are a switch with synonyms and a flag with a value
+var chartFlags = []Flag{
+	fullFlag,
+	{Name: "days", Aliases: []string{"d"}, Value: &Arg{Name: "days", Kind: KindInteger}},
+}
+
+// values returns the parsed arguments and flags of a spec, failing the test
+// when the arguments do not match
+func values(t *testing.T, spec Spec, input string) Parsed {
+	t.Helper()
+	parsed, err := spec.Parse(strings.Fields(input))
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", input, err)
+	}
+	return parsed
+}
+
+// assertArgs checks the words of each named argument; nil means the
+// argument was not given
+func assertArgs(t *testing.T, input string, parsed Parsed, want map[string][]string) {
+	t.Helper()
+	for name, words := range want {
+		if got := parsed.Strings(name); !reflect.DeepEqual(got, words) {
+			t.Errorf("Parse(%q) %s = %q, want %q", input, name, got, words)
+		}
+		if parsed.Has(name) != (words != nil) {
+			t.Errorf("Parse(%q) Has(%s) = %v, want %v", input, name, parsed.Has(name), words != nil)
+		}
+	}
+}
+
+func TestParseSubcommands(t *testing.T) {
+	spec := NewAlertCommand(nil, nil).Spec()
+	tests := []struct {
+		input      string
+		subcommand string
+		args       map[string][]string
+	}{
+		// Empty selects the listing, Default the creation
+		{"", "list", nil},
+		{"list", "list", nil},
+		{"LISTA", "list", nil},
+		{"delete 3", "delete", map[string][]string{"id": {"3"}}},
+		{"rm #12", "delete", map[string][]string{"id": {"12"}}},
+		{"btc > 70000", "add", map[string][]string{"coin": {"btc"}, "price": {"70000"}}},
+		{"crear btc > 70000", "add", map[string][]string{"coin": {"btc"}, "price": {"70000"}}},
+	}
+	for _, tt := range tests {
+		parsed, err := spec.Parse(strings.Fields(tt.input))
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.input, err)
+			continue
+		}
+		if parsed.Subcommand != tt.subcommand {
+			t.Errorf("Parse(%q) subcommand = %q, want %q", tt.input, parsed.Subcommand, tt.subcommand)
+		}
+		assertArgs(t, tt.input, parsed, tt.args)
+	}
+
+	// Without Default and Empty a subcommand must be named
+	strict := Spec{Subcommands: spec.Subcommands}
+	if _, err := strict.Parse(nil); err == nil || err.Key != "args.missing_subcommand" {
+		t.Errorf("Parse() without arguments error = %v, want args.missing_subcommand", err)
+	}
+	if _, err := strict.Parse([]string{"btc"}); err == nil || err.Key != "args.unknown_subcommand" || err.Word != "btc" {
+		t.Errorf("Parse(btc) error = %v, want args.unknown_subcommand for btc", err)
+	}
+}
+
+func TestParseKeywordArgs(t *testing.T) {
+	spec := Spec{Args: priceArgs}
+	tests := []struct {
+		input string
+		args  map[string][]string
+	}{
+		{"btc", map[string][]string{"coin": {"btc"}, "currency": nil}},
+		{"btc in eur", map[string][]string{"coin": {"btc"}, "currency": {"eur"}}},
+		{"btc IN EUR", map[string][]string{"coin": {"btc"}, "currency": {"eur"}}},
+		{"btc eth en eur gbp", map[string][]string{"coin": {"btc", "eth"}, "currency": {"eur", "gbp"}}},
+		{"shiba inu vs usd", map[string][]string{"coin": {"shiba", "inu"}, "currency": {"usd"}}},
+		// A coin named like a currency is still a coin without the keyword
+		{"eur", map[string][]string{"coin": {"eur"}, "currency": nil}},
+	}
+	for _, tt := range tests {
+		assertArgs(t, tt.input, values(t, spec, tt.input), tt.args)
+	}
+
+	for _, tt := range []struct{ input, key, word string }{
+		{"", "args.missing", ""},
+		{"btc in", "args.missing", ""},
+		{"in eur", "args.unexpected", "in"},
+		{"btc in e1", "args.invalid_currency", "e1"},
+	} {
+		_, err := spec.Parse(strings.Fields(tt.input))
+		if err == nil || err.Key != tt.key || err.Word != tt.word {
+			t.Errorf("Parse(%q) error = %+v, want %s %q", tt.input, err, tt.key, tt.word)
+		}
+	}
+}
+
+func TestParseBacktracking(t *testing.T) {
+	spec := NewAlertCommand(nil, nil).Spec()
+	tests := []struct {
+		input string
+		args  map[string][]string
+	}{
+		{"shiba inu above 0.00003", map[string][]string{
+			"coin": {"shiba", "inu"}, "condition": {">"}, "price": {"0.00003"}, "currency": nil, "mode": nil,
+		}},
+		{"bitcoin cash below 300 eur repeat", map[string][]string{
+			"coin": {"bitcoin", "cash"}, "condition": {"<"}, "price": {"300"}, "currency": {"eur"}, "mode": {"repeat"},
+		}},
+		{"btc >= 70k siempre", map[string][]string{
+			"coin": {"btc"}, "condition": {">"}, "price": {"70k"}, "currency": nil, "mode": {"repeat"},
+		}},
+		// The condition is taken by the first word that can be one
+		{"usd coin > 1", map[string][]string{
+			"coin": {"usd", "coin"}, "condition": {">"}, "price": {"1"},
+		}},
+	}
+	for _, tt := range tests {
+		assertArgs(t, tt.input, values(t, spec, tt.input), tt.args)
+	}
+
+	// Optional arguments in the middle are skipped when the rest needs the words
+	optional := Spec{Args: []Arg{
+		{Name: "coin", Repeated: true},
+		{Name: "amount", Kind: KindNumber, Values: []string{"all"}, Optional: true},
+		{Name: "price", Kind: KindNumber},
+	}}
+	for _, tt := range []struct {
+		input string
+		args  map[string][]string
+	}{
+		{"btc 2 60000", map[string][]string{"coin": {"btc"}, "amount": {"2"}, "price": {"60000"}}},
+		{"btc 60000", map[string][]string{"coin": {"btc"}, "amount": nil, "price": {"60000"}}},
+		{"matic network all 0.5", map[string][]string{"coin": {"matic", "network"}, "amount": {"all"}, "price": {"0.5"}}},
+		{"the graph 100 0,2", map[string][]string{"coin": {"the", "graph"}, "amount": {"100"}, "price": {"0,2"}}},
+	} {
+		assertArgs(t, tt.input, values(t, optional, tt.input), tt.args)
+	}
+
+	// The error reported is the one at the furthest word reached
+	for _, tt := range []struct{ input, key, word string }{
+		{"btc >", "args.missing", ""},
+		{"btc > cheap", "args.invalid_number", "cheap"},
+		{"btc > 70000 eur twice", "args.invalid_choice", "twice"},
+		// The price is taken as part of the coin when no condition follows
+		{"btc 70000", "args.missing", ""},
+	} {
+		_, err := spec.Parse(strings.Fields(tt.input))
+		if err == nil || err.Key != tt.key || err.Word != tt.word {
+			t.Errorf("Parse(%q) error = %+v, want %s %q", tt.input, err, tt.key, tt.word)
+		}
+	}
+}
+
+func TestParseReservedWords(t *testing.T) {
+	spec := NewAlertCommand(nil, nil).Spec()
+
+	// "once" is a mode, so it is not taken as a currency
+	parsed := values(t, spec, "btc > 70000 once")
+	assertArgs(t, "btc > 70000 once", parsed, map[string][]string{"currency": nil, "mode": {"once"}})
+
+	// Nor is a keyword or a choice taken as part of a coin name
+	if _, err := spec.Parse(strings.Fields("once > 5")); err == nil || err.Word != "once" {
+		t.Errorf("Parse(once > 5) error = %v, want once unexpected", err)
+	}
+	assertArgs(t, "btc eth in eur", values(t, Spec{Args: priceArgs}, "btc eth in eur"),
+		map[string][]string{"coin": {"btc", "eth"}, "currency": {"eur"}})
+}
+
+func TestParseCommaLists(t *testing.T) {
+	spec := Spec{Args: priceArgs}
+	tests := []struct {
+		input string
+		args  map[string][]string
+	}{
+		// Repeated values other than text are split on commas
+		{"btc in eur,gbp", map[string][]string{"currency": {"eur", "gbp"}}},
+		{"btc in eur, gbp ,jpy", map[string][]string{"currency": {"eur", "gbp", "jpy"}}},
+		// Text keeps its commas for the command to split
+		{"btc,eth in usd", map[string][]string{"coin": {"btc,eth"}, "currency": {"usd"}}},
+	}
+	for _, tt := range tests {
+		assertArgs(t, tt.input, values(t, spec, tt.input), tt.args)
+	}
+
+	if _, err := spec.Parse(strings.Fields("btc in eur,1x")); err == nil || err.Key != "args.invalid_currency" {
+		t.Errorf("Parse(btc in eur,1x) error = %v, want args.invalid_currency", err)
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	spec := Spec{Args: priceArgs, Flags: chartFlags}
+	tests := []struct {
+		input string
+		days  string
+		full  bool
+	}{
+		{"btc", "", false},
+		{"btc --days=7", "7", false},
+		{"btc --days 7", "7", false},
+		{"--d 30 btc", "30", false},
+		{"btc --DAYS=#14", "14", false},
+		// Phones turn "--" into an em or en dash
+		{"btc —days 7", "7", false},
+		{"btc –full", "", true},
+		{"btc --full --days=7", "7", true},
+		{"btc --detail", "", true},
+		// Switch synonyms are plain words anywhere in the arguments
+		{"btc full", "", true},
+		{"FULL btc in eur", "", true},
+		{"btc completo in eur", "", true},
+		{"btc in eur detailed", "", true},
+	}
+	for _, tt := range tests {
+		parsed := values(t, spec, tt.input)
+		days, hasDays := parsed.Flag("days")
+		if days != tt.days || hasDays != (tt.days != "") {
+			t.Errorf("Parse(%q) days = %q, %v, want %q", tt.input, days, hasDays, tt.days)
+		}
+		if _, full := parsed.Flag("full"); full != tt.full {
+			t.Errorf("Parse(%q) full = %v, want %v", tt.input, full, tt.full)
+		}
+		if coins := parsed.Strings("coin"); !reflect.DeepEqual(coins, []string{"btc"}) {
+			t.Errorf("Parse(%q) coin = %q, want btc", tt.input, coins)
+		}
+	}
+
+	for _, tt := range []struct{ input, key, word string }{
+		{"btc --bogus", "args.unknown_flag", "bogus"},
+		{"btc --days", "args.flag_value", "days"},
+		{"btc --days=week", "args.invalid_integer", "week"},
+		// Synonyms only turn on switches
+		{"btc days", "", ""},
+	} {
+		_, err := spec.Parse(strings.Fields(tt.input))
+		if tt.key == "" {
+			if err != nil {
+				t.Errorf("Parse(%q) error = %v, want none", tt.input, err)
+			}
+			continue
+		}
+		if err == nil || err.Key != tt.key || err.Word != tt.word {
+			t.Errorf("Parse(%q) error = %+v, want %s %q", tt.input, err, tt.key, tt.word)
+		}
+	}
+}
+
+func TestUsage(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   Spec
+		locale string
+		want   []string
+	}{
+		{"price", Spec{Args: priceArgs, Flags: []Flag{fullFlag}}, "en", []string{
+			"/price <coin>... [in <currency>...] [--full]",
+		}},
+		{"price", Spec{Args: priceArgs, Flags: []Flag{fullFlag}}, "es", []string{
+			"/price <moneda>... [in <divisa>...] [--full]",
+		}},
+		{"chart", Spec{Args: priceArgs[:1], Flags: chartFlags}, "en", []string{
+			"/chart <coin>... [--full] [--days <days>]",
+		}},
+		{"alert", NewAlertCommand(nil, nil).Spec(), "en", []string{
+			"/alert [add] <coin>... >|< <price> [<currency>] [repeat|once]",
+			"/alert list",
+			"/alert delete <id>",
+		}},
+		{"sell", Spec{Args: []Arg{{Name: "amount", Kind: KindNumber, Values: []string{"all"}}}}, "en", []string{
+			"/sell <amount>|all",
+		}},
+	}
+	for _, tt := range tests {
+		if got := tt.spec.Usage(tt.name, tt.locale); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Usage(%s, %s) = %q, want %q", tt.name, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestParseArgsReply(t *testing.T) {
+	_, reply := parseArgs(context.Background(), NewPriceCommand(nil, nil), []string{"btc", "in"})
+	want := "Missing <currency>.\n\n*Usage:*\n/price <coin>... [in <currency>...] [--full]"
+	if reply != want {
+		t.Errorf("parseArgs() reply = %q, want %q", reply, want)
+	}
+}
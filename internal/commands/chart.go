@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"blockmind/internal/chart"
+	"blockmind/internal/crypto"
+	"blockmind/internal/i18n"
+	"context"
+	"fmt"
+)
+
+// chartPeriods maps the accepted period arguments to a number of days
+var chartPeriods = map[string]int{
+	"1d":  1,
+	"7d":  7,
+	"30d": 30,
+	"90d": 90,
+	"1y":  365,
+}
+
+// chartPeriodNames are the periods in the order shown in the usage
+var chartPeriodNames = []string{"1d", "7d", "30d", "90d", "1y"}
+
+// ChartCommand renders the price history of a cryptocurrency as an image
+type ChartCommand struct {
+	provider crypto.MarketDataProvider
+	resolver *crypto.Resolver
+}
+
+// NewChartCommand creates a new chart command
+func NewChartCommand(provider crypto.MarketDataProvider, resolver *crypto.Resolver) *ChartCommand {
+	return &ChartCommand{
+		provider: provider,
+		resolver: resolver,
+	}
+}
+
+// Name returns the name of the command
+func (c *ChartCommand) Name() string {
+	return "chart"
+}
+
+// Aliases returns alternative names for the command
+func (c *ChartCommand) Aliases() []string {
+	return []string{"c", "grafico"}
+}
+
+// Description returns the description of the command
+func (c *ChartCommand) Description() string {
+	return i18n.Translate(i18n.DefaultLocale, "desc.chart")
+}
+
+// Spec returns the arguments the command accepts
+func (c *ChartCommand) Spec() Spec {
+	return Spec{
+		Args: []Arg{
+			{Name: "coin", Repeated: true},
+			{Name: "period", Kind: KindChoice, Values: chartPeriodNames, Optional: true},
+			{Name: "currency", Kind: KindCurrency, Keywords: currencyKeywords, Optional: true},
+		},
+	}
+}
+
+// Help returns a long description of the command in locale
+func (c *ChartCommand) Help(locale string) string {
+	return i18n.Translate(locale, "details.chart")
+}
+
+// Examples returns sample uses of the command, explained in locale
+func (c *ChartCommand) Examples(locale string) []Example {
+	return []Example{
+		{Input: "/chart eth", Effect: i18n.Translate(locale, "example.chart.default")},
+		{Input: "/chart btc 1y in eur", Effect: i18n.Translate(locale, "example.chart.period")},
+	}
+}
+
+// Execute executes the command with the given arguments
+func (c *ChartCommand) Execute(ctx context.Context, args []string) (string, error) {
+	if len(args) == 0 {
+		return i18n.T(ctx, "chart.usage"), nil
+	}
+
+	responder, ok := ResponderFromContext(ctx)
+	if !ok {
+		return "", fmt.Errorf("chart command requires a responder able to send images")
+	}
+
+	parsed, reply := parseArgs(ctx, c, args)
+	if reply != "" {
+		return reply, nil
+	}
+
+	coin, reply, err := resolveCoin(ctx, c.resolver, parsed.String("coin"), c.Name())
+	if err != nil || reply != "" {
+		return reply, err
+	}
+
+	period := "7d"
+	if parsed.Has("period") {
+		period = parsed.String("period")
+	}
+	currency := "usd"
+	if parsed.Has("currency") {
+		currency = parsed.String("currency")
+	}
+
+	history, err := c.provider.History(ctx, coin, currency, chartPeriods[period])
+	if err != nil {
+		return "", err
+	}
+	if len(history) < 2 {
+		return i18n.T(ctx, "chart.not_enough_history", coin), nil
+	}
+
+	points := make([]chart.Point, len(history))
+	for i, point := range history {
+		points[i] = chart.Point{Time: point.Time, Value: point.Price}
+	}
+
+	image, err := chart.RenderLine(points, chart.DefaultOptions())
+	if err != nil {
+		return "", err
+	}
+
+	caption := fmt.Sprintf("*%s* · %s\n%s", coin, period, crypto.SummarizeHistory(history, currency, i18n.FromContext(ctx)))
+	if err := responder.SendImage(ctx, image, "image/png", caption); err != nil {
+		return "", fmt.Errorf("failed to send chart: %w", err)
+	}
+
+	// The chart is the reply
+	return "", nil
+}
@@ -1,7 +1,10 @@
 package commands
 
 import (
+	"blockmind/internal/i18n"
+	"blockmind/internal/intent"
 	"blockmind/internal/security"
+	"blockmind/internal/storage"
 	"context"
 	"strings"
 )
@@ -22,6 +25,8 @@ type Command interface {
 type Manager struct {
 	commands       map[string]Command
 	defaultHandler func(context.Context, string) (string, error)
+	router         *intent.Router
+	autocorrect    bool
 }
 
 // NewManager creates a new command manager
@@ -42,13 +47,30 @@ func (m *Manager) Register(cmd Command) {
 	}
 }
 
+// SetRouter enables routing of free-text messages to commands; messages the
+// router does not recognize still go to the default handler
+func (m *Manager) SetRouter(router *intent.Router) {
+	m.router = router
+}
+
+// SetAutocorrect makes a mistyped command run the command it surely meant,
+// instead of only suggesting it
+func (m *Manager) SetAutocorrect(enabled bool) {
+	m.autocorrect = enabled
+}
+
 // Execute executes a command
 func (m *Manager) Execute(ctx context.Context, input string) (string, error) {
+	// Blocked users get no reply at all
+	if RoleFromContext(ctx) == storage.RoleBlocked {
+		return "", nil
+	}
+
 	// First sanitize the entire input
 	var err_sanitizer string
 	input, err_sanitizer = security.SanitizeInput(input)
 	if err_sanitizer != "" {
-		return err_sanitizer, nil
+		return i18n.T(ctx, err_sanitizer), nil
 	}
 
 	// Split input into command and arguments
@@ -57,7 +79,7 @@ func (m *Manager) Execute(ctx context.Context, input string) (string, error) {
 
 	parts := splitCommandText(input)
 	if len(parts) == 0 {
-		return "Send a command like '/price Bitcoin' or ask a question.", nil
+		return i18n.T(ctx, "manager.empty"), nil
 	}
 
 	command = parts[0]
@@ -75,9 +97,18 @@ func (m *Manager) Execute(ctx context.Context, input string) (string, error) {
 
 		cmd, exists := m.commands[cmdName]
 		if exists {
-			return cmd.Execute(ctx, args)
+			return m.run(ctx, cmd, args)
+		}
+		return m.unknown(ctx, cmdName, args)
+	}
+
+	// Free text may still ask for a command, as in "price of eth in euros"
+	if m.router != nil {
+		if in, ok := m.router.Route(ctx, input); ok {
+			if cmd, exists := m.commands[in.Command]; exists {
+				return m.run(ctx, cmd, in.Args())
+			}
 		}
-		return "Unknown command. Type /help for a list of commands.", nil
 	}
 
 	// If not a command, use the default handler for questions
@@ -85,7 +116,42 @@ func (m *Manager) Execute(ctx context.Context, input string) (string, error) {
 		return m.defaultHandler(ctx, input)
 	}
 
-	return "I don't understand that. Try typing /help for assistance.", nil
+	return i18n.T(ctx, "manager.not_understood"), nil
+}
+
+// run executes cmd if the role in the context allows it
+func (m *Manager) run(ctx context.Context, cmd Command, args []string) (string, error) {
+	if !allowed(RoleFromContext(ctx), cmd) {
+		return i18n.T(ctx, "manager.forbidden", cmd.Name()), nil
+	}
+	return cmd.Execute(ctx, args)
+}
+
+// unknown answers a command name that is not registered with the closest
+// commands, or runs the one it surely meant when autocorrect is on
+func (m *Manager) unknown(ctx context.Context, name string, args []string) (string, error) {
+	suggestions := m.Suggest(ctx, name)
+	if len(suggestions) == 0 {
+		return i18n.T(ctx, "manager.unknown_command"), nil
+	}
+
+	if m.autocorrect && confident(name, suggestions) {
+		reply, err := m.run(ctx, suggestions[0].Command, args)
+		if err != nil || reply == "" {
+			return reply, err
+		}
+		return i18n.T(ctx, "manager.autocorrected", name, suggestions[0].Name) + "\n\n" + reply, nil
+	}
+	return didYouMean(ctx, name, suggestions), nil
+}
+
+// didYouMean tells that a command does not exist and offers the suggestions
+func didYouMean(ctx context.Context, name string, suggestions []Suggestion) string {
+	names := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		names[i] = "/" + suggestion.Name
+	}
+	return i18n.T(ctx, "manager.did_you_mean", name, strings.Join(names, ", "))
 }
 
 // GetCommands returns all registered commands
@@ -1,12 +1,29 @@
 package commands
 
 import (
+	"blockmind/internal/i18n"
 	"context"
 	"fmt"
 	"sort"
 	"strings"
 )
 
+// Documented is implemented by commands that explain themselves in detail,
+// as shown by /help <command>
+type Documented interface {
+	Command
+	// Help returns a long description of the command in locale
+	Help(locale string) string
+	// Examples returns sample uses of the command, explained in locale
+	Examples(locale string) []Example
+}
+
+// Example is a sample use of a command and what it does
+type Example struct {
+	Input  string
+	Effect string
+}
+
 // HelpCommand provides help information about available commands
 type HelpCommand struct {
 	manager *Manager
@@ -24,24 +41,53 @@ func (c *HelpCommand) Name() string {
 
 // Aliases returns alternative names for the command
 func (c *HelpCommand) Aliases() []string {
-	return []string{"h", "ayuda"}
+	return []string{"h", "ayuda", "ajuda"}
 }
 
 // Description returns the description of the command
 func (c *HelpCommand) Description() string {
-	return "Shows available commands and usage information"
+	return i18n.Translate(i18n.DefaultLocale, "desc.help")
+}
+
+// Spec returns the arguments the command accepts
+func (c *HelpCommand) Spec() Spec {
+	return Spec{
+		Args: []Arg{{Name: "command", Optional: true}},
+	}
+}
+
+// Help returns a long description of the command in locale
+func (c *HelpCommand) Help(locale string) string {
+	return i18n.Translate(locale, "details.help")
+}
+
+// Examples returns sample uses of the command, explained in locale
+func (c *HelpCommand) Examples(locale string) []Example {
+	return []Example{
+		{Input: "/help", Effect: i18n.Translate(locale, "example.help.list")},
+		{Input: "/help price", Effect: i18n.Translate(locale, "example.help.command")},
+	}
 }
 
 // Execute executes the command with the given arguments
 func (c *HelpCommand) Execute(ctx context.Context, args []string) (string, error) {
+	parsed, reply := parseArgs(ctx, c, args)
+	if reply != "" {
+		return reply, nil
+	}
+	if parsed.Has("command") {
+		return c.detail(ctx, parsed.String("command"))
+	}
+
 	var helpText strings.Builder
 
-	helpText.WriteString("*Available Commands:*\n\n")
+	helpText.WriteString(i18n.T(ctx, "help.title") + "\n\n")
 
-	// Get all unique commands (ignoring aliases)
+	// Get all unique commands (ignoring aliases) the user may run
+	role := RoleFromContext(ctx)
 	uniqueCommands := make(map[string]Command)
 	for name, cmd := range c.manager.GetCommands() {
-		if name == cmd.Name() {
+		if name == cmd.Name() && allowed(role, cmd) {
 			uniqueCommands[name] = cmd
 		}
 	}
@@ -57,18 +103,73 @@ func (c *HelpCommand) Execute(ctx context.Context, args []string) (string, error
 	for _, name := range commandNames {
 		cmd := uniqueCommands[name]
 
-		// Show command name and description
-		helpText.WriteString(fmt.Sprintf("/%s - %s\n", cmd.Name(), cmd.Description()))
+		// Show command name and description in the user's language
+		helpText.WriteString(fmt.Sprintf("/%s - %s\n", cmd.Name(), describe(ctx, cmd)))
 
 		// Show aliases if any
 		if aliases := cmd.Aliases(); len(aliases) > 0 {
-			helpText.WriteString(fmt.Sprintf("  Aliases: /%s\n", strings.Join(aliases, ", /")))
+			helpText.WriteString("  " + i18n.T(ctx, "help.aliases", "/"+strings.Join(aliases, ", /")) + "\n")
 		}
 
 		helpText.WriteString("\n")
 	}
 
-	helpText.WriteString("You can also ask me questions directly!")
+	helpText.WriteString(i18n.T(ctx, "help.more") + "\n")
+	helpText.WriteString(i18n.T(ctx, "help.footer"))
 
 	return helpText.String(), nil
 }
+
+// detail explains one command: its description, syntax, examples and aliases
+func (c *HelpCommand) detail(ctx context.Context, name string) (string, error) {
+	name = strings.ToLower(strings.TrimPrefix(name, "/"))
+	cmd, exists := c.manager.GetCommands()[name]
+	if !exists {
+		if suggestions := c.manager.Suggest(ctx, name); len(suggestions) > 0 {
+			return didYouMean(ctx, name, suggestions), nil
+		}
+	}
+	if !exists || !allowed(RoleFromContext(ctx), cmd) {
+		return i18n.T(ctx, "help.unknown", name), nil
+	}
+
+	locale := i18n.FromContext(ctx)
+	var helpText strings.Builder
+	helpText.WriteString(fmt.Sprintf("*/%s* - %s\n", cmd.Name(), describe(ctx, cmd)))
+
+	documented, ok := cmd.(Documented)
+	if ok {
+		helpText.WriteString("\n" + documented.Help(locale) + "\n")
+	}
+
+	// Commands without a spec take no arguments
+	usage := []string{"/" + cmd.Name()}
+	if specified, ok := cmd.(Specified); ok {
+		usage = specified.Spec().Usage(cmd.Name(), locale)
+	}
+	helpText.WriteString("\n" + i18n.T(ctx, "args.usage") + "\n")
+	helpText.WriteString(strings.Join(usage, "\n") + "\n")
+
+	if ok {
+		if examples := documented.Examples(locale); len(examples) > 0 {
+			helpText.WriteString("\n" + i18n.T(ctx, "help.examples") + "\n")
+			for _, example := range examples {
+				helpText.WriteString(fmt.Sprintf("%s - %s\n", example.Input, example.Effect))
+			}
+		}
+	}
+
+	if aliases := cmd.Aliases(); len(aliases) > 0 {
+		helpText.WriteString("\n" + i18n.T(ctx, "help.aliases", "/"+strings.Join(aliases, ", /")))
+	}
+	return strings.TrimRight(helpText.String(), "\n"), nil
+}
+
+// describe returns the description of a command in the locale of the
+// context. Commands without a translated description use their own.
+func describe(ctx context.Context, cmd Command) string {
+	if key := "desc." + cmd.Name(); i18n.Has(key) {
+		return i18n.T(ctx, key)
+	}
+	return cmd.Description()
+}
@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"blockmind/internal/i18n"
+	"blockmind/internal/middleware"
+	"blockmind/internal/storage"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// languageNames maps the names users may give a language to its locale
+var languageNames = map[string]string{
+	"english": i18n.English, "ingles": i18n.English, "inglés": i18n.English, "inglês": i18n.English,
+	"spanish": i18n.Spanish, "espanol": i18n.Spanish, "español": i18n.Spanish, "espanhol": i18n.Spanish,
+	"portuguese": i18n.Portuguese, "portugues": i18n.Portuguese, "portugués": i18n.Portuguese, "português": i18n.Portuguese,
+}
+
+// LangCommand shows and changes the language of the replies
+type LangCommand struct {
+	store *storage.Store
+}
+
+// NewLangCommand creates a new lang command
+func NewLangCommand(store *storage.Store) *LangCommand {
+	return &LangCommand{store: store}
+}
+
+// Name returns the name of the command
+func (c *LangCommand) Name() string {
+	return "lang"
+}
+
+// Aliases returns alternative names for the command
+func (c *LangCommand) Aliases() []string {
+	return []string{"language", "idioma", "lingua"}
+}
+
+// Description returns the description of the command
+func (c *LangCommand) Description() string {
+	return i18n.Translate(i18n.DefaultLocale, "desc.lang")
+}
+
+// Spec returns the arguments the command accepts
+func (c *LangCommand) Spec() Spec {
+	return Spec{
+		Args: []Arg{
+			{Name: "language", Kind: KindChoice, Values: append(i18n.Locales(), "auto"), Synonyms: languageNames, Optional: true},
+		},
+	}
+}
+
+// Help returns a long description of the command in locale
+func (c *LangCommand) Help(locale string) string {
+	return i18n.Translate(locale, "details.lang")
+}
+
+// Examples returns sample uses of the command, explained in locale
+func (c *LangCommand) Examples(locale string) []Example {
+	return []Example{
+		{Input: "/lang es", Effect: i18n.Translate(locale, "example.lang.set")},
+		{Input: "/lang auto", Effect: i18n.Translate(locale, "example.lang.auto")},
+	}
+}
+
+// Execute executes the command with the given arguments
+func (c *LangCommand) Execute(ctx context.Context, args []string) (string, error) {
+	userJID, ok := middleware.GetUserJID(ctx)
+	if !ok {
+		return "", fmt.Errorf("lang command requires a user JID in the context")
+	}
+
+	parsed, reply := parseArgs(ctx, c, args)
+	if reply != "" {
+		return reply, nil
+	}
+
+	locale := parsed.String("language")
+	switch locale {
+	case "":
+		return c.show(ctx, userJID)
+	case "auto":
+		if err := c.store.SetLanguage(ctx, userJID, ""); err != nil {
+			return "", err
+		}
+		return i18n.T(ctx, "lang.auto"), nil
+	}
+
+	if err := c.store.SetLanguage(ctx, userJID, locale); err != nil {
+		return "", err
+	}
+	// Confirm in the new language
+	return i18n.Translate(locale, "lang.set", i18n.Name(locale)), nil
+}
+
+// show describes the current language and the available ones
+func (c *LangCommand) show(ctx context.Context, userJID string) (string, error) {
+	settings, err := c.store.GetSettings(ctx, userJID)
+	if err != nil {
+		return "", err
+	}
+
+	var reply strings.Builder
+	if settings.Language == "" {
+		reply.WriteString(i18n.T(ctx, "lang.current.auto", i18n.Name(i18n.FromContext(ctx))))
+	} else {
+		reply.WriteString(i18n.T(ctx, "lang.current", i18n.Name(settings.Language)))
+	}
+
+	reply.WriteString("\n\n" + i18n.T(ctx, "lang.available") + "\n")
+	for _, locale := range i18n.Locales() {
+		reply.WriteString(fmt.Sprintf("• %s — /lang %s\n", i18n.Name(locale), locale))
+	}
+	reply.WriteString("• " + i18n.T(ctx, "lang.auto.option"))
+	return reply.String(), nil
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"blockmind/internal/storage"
+	"context"
+)
+
+// Restricted is implemented by commands that not every user may run
+type Restricted interface {
+	Command
+	// Permission returns the least privileged role allowed to run the command
+	Permission() string
+}
+
+// roleRanks orders roles by privilege
+var roleRanks = map[string]int{
+	storage.RoleBlocked: 0,
+	storage.RoleUser:    1,
+	storage.RoleAdmin:   2,
+	storage.RoleOwner:   3,
+}
+
+// atLeast reports whether role has the privileges of required
+func atLeast(role, required string) bool {
+	return roleRanks[role] >= roleRanks[required]
+}
+
+// allowed reports whether a user with role may run cmd
+func allowed(role string, cmd Command) bool {
+	restricted, ok := cmd.(Restricted)
+	if !ok {
+		return role != storage.RoleBlocked
+	}
+	return atLeast(role, restricted.Permission())
+}
+
+type roleKey struct{}
+
+// WithRole returns a new context carrying the role of the user. The message
+// handler places one in the context of every message.
+func WithRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, roleKey{}, role)
+}
+
+// RoleFromContext returns the role of the user sending the message, the user
+// role when it is unknown
+func RoleFromContext(ctx context.Context) string {
+	if role, ok := ctx.Value(roleKey{}).(string); ok {
+		return role
+	}
+	return storage.RoleUser
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"blockmind/internal/storage"
+	"context"
+	"testing"
+)
+
+func TestAllowed(t *testing.T) {
+	price := NewPriceCommand(nil, nil)
+	admin := NewAdminCommand(nil, nil)
+
+	tests := []struct {
+		role  string
+		price bool
+		admin bool
+	}{
+		{storage.RoleOwner, true, true},
+		{storage.RoleAdmin, true, true},
+		{storage.RoleUser, true, false},
+		{storage.RoleBlocked, false, false},
+		{"unknown", true, false},
+	}
+	for _, tt := range tests {
+		if got := allowed(tt.role, price); got != tt.price {
+			t.Errorf("allowed(%q, price) = %v, want %v", tt.role, got, tt.price)
+		}
+		if got := allowed(tt.role, admin); got != tt.admin {
+			t.Errorf("allowed(%q, admin) = %v, want %v", tt.role, got, tt.admin)
+		}
+	}
+}
+
+func TestExecuteChecksRole(t *testing.T) {
+	manager := NewManager(nil)
+	manager.Register(NewAdminCommand(nil, nil))
+
+	tests := []struct {
+		role string
+		want string
+	}{
+		{storage.RoleUser, "You are not allowed to use /admin."},
+		{storage.RoleBlocked, ""},
+	}
+	for _, tt := range tests {
+		reply, err := manager.Execute(WithRole(context.Background(), tt.role), "/admin stats")
+		if err != nil || reply != tt.want {
+			t.Errorf("Execute() as %s = %q, %v, want %q", tt.role, reply, err, tt.want)
+		}
+	}
+
+	// Without a role in the context the user role applies
+	if reply, _ := manager.Execute(context.Background(), "/admin stats"); reply != "You are not allowed to use /admin." {
+		t.Errorf("Execute() without a role = %q, want the command refused", reply)
+	}
+}
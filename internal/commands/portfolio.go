@@ -0,0 +1,270 @@
+package commands
+
+import (
+	"blockmind/internal/crypto"
+	"blockmind/internal/i18n"
+	"blockmind/internal/middleware"
+	"blockmind/internal/portfolio"
+	"blockmind/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PortfolioCommand tracks the holdings of the current chat and reports their P&L
+type PortfolioCommand struct {
+	store    *storage.Store
+	provider crypto.MarketDataProvider
+	resolver *crypto.Resolver
+}
+
+// NewPortfolioCommand creates a new portfolio command
+func NewPortfolioCommand(store *storage.Store, provider crypto.MarketDataProvider, resolver *crypto.Resolver) *PortfolioCommand {
+	return &PortfolioCommand{
+		store:    store,
+		provider: provider,
+		resolver: resolver,
+	}
+}
+
+// Name returns the name of the command
+func (c *PortfolioCommand) Name() string {
+	return "portfolio"
+}
+
+// Aliases returns alternative names for the command
+func (c *PortfolioCommand) Aliases() []string {
+	return []string{"pf", "portafolio", "cartera"}
+}
+
+// Description returns the description of the command
+func (c *PortfolioCommand) Description() string {
+	return i18n.Translate(i18n.DefaultLocale, "desc.portfolio")
+}
+
+// Spec returns the arguments the command accepts
+func (c *PortfolioCommand) Spec() Spec {
+	return Spec{
+		Subcommands: []Subcommand{
+			// "/portfolio eur" shows the portfolio in another currency once
+			{Name: "show", Aliases: []string{"ver"}, Args: []Arg{
+				{Name: "currency", Kind: KindCurrency, Optional: true},
+			}},
+			{Name: "add", Aliases: []string{"buy", "agregar", "comprar", "adicionar"}, Args: []Arg{
+				{Name: "coin", Repeated: true},
+				{Name: "amount", Kind: KindNumber},
+				{Name: "price", Kind: KindNumber, Keywords: []string{"@"}, Optional: true},
+				{Name: "currency", Kind: KindCurrency, Optional: true},
+			}},
+			{Name: "remove", Aliases: []string{"sell", "del", "rm", "quitar", "vender", "remover"}, Args: []Arg{
+				{Name: "coin", Repeated: true},
+				{Name: "amount", Kind: KindNumber, Values: []string{"all"}, Optional: true, Synonyms: map[string]string{
+					"todo": "all", "tudo": "all",
+				}},
+			}},
+			{Name: "currency", Aliases: []string{"moneda", "moeda"}, Args: []Arg{
+				{Name: "currency", Kind: KindCurrency, Optional: true},
+			}},
+		},
+		Default: "show",
+		Empty:   "show",
+	}
+}
+
+// Help returns a long description of the command in locale
+func (c *PortfolioCommand) Help(locale string) string {
+	return i18n.Translate(locale, "details.portfolio")
+}
+
+// Examples returns sample uses of the command, explained in locale
+func (c *PortfolioCommand) Examples(locale string) []Example {
+	return []Example{
+		{Input: "/portfolio add btc 0.5 @ 42000", Effect: i18n.Translate(locale, "example.portfolio.add")},
+		{Input: "/portfolio remove btc 0.1", Effect: i18n.Translate(locale, "example.portfolio.remove")},
+		{Input: "/portfolio eur", Effect: i18n.Translate(locale, "example.portfolio.show")},
+		{Input: "/portfolio currency eur", Effect: i18n.Translate(locale, "example.portfolio.currency")},
+	}
+}
+
+// Execute executes the command with the given arguments
+func (c *PortfolioCommand) Execute(ctx context.Context, args []string) (string, error) {
+	userJID, ok := middleware.GetUserJID(ctx)
+	if !ok {
+		return "", fmt.Errorf("portfolio command requires a user JID in the context")
+	}
+
+	// Accept "@42000" and "0.5@42000" as well as "@ 42000"
+	args = strings.Fields(strings.ReplaceAll(strings.Join(args, " "), "@", " @ "))
+
+	parsed, reply := parseArgs(ctx, c, args)
+	if reply != "" {
+		return reply, nil
+	}
+
+	switch parsed.Subcommand {
+	case "add":
+		return c.add(ctx, userJID, parsed)
+	case "remove":
+		return c.remove(ctx, userJID, parsed)
+	case "currency":
+		return c.setCurrency(ctx, userJID, parsed.String("currency"))
+	}
+	return c.show(ctx, userJID, parsed.String("currency"))
+}
+
+// show values all holdings in the given or the preferred currency
+func (c *PortfolioCommand) show(ctx context.Context, userJID, currency string) (string, error) {
+	positions, err := c.store.ListPositions(ctx, userJID)
+	if err != nil {
+		return "", err
+	}
+	if len(positions) == 0 {
+		return i18n.T(ctx, "portfolio.empty"), nil
+	}
+
+	if currency == "" {
+		settings, err := c.store.GetSettings(ctx, userJID)
+		if err != nil {
+			return "", err
+		}
+		currency = settings.Currency
+	}
+
+	report, err := portfolio.Evaluate(ctx, c.provider, positions, currency)
+	if err != nil {
+		return "", err
+	}
+	return report.Format(i18n.FromContext(ctx)), nil
+}
+
+// add records the purchase of "<coin> <amount> [@ <price>] [currency]".
+// Without a price the current market price is used.
+func (c *PortfolioCommand) add(ctx context.Context, userJID string, parsed Parsed) (string, error) {
+	amount := parsed.Number("amount")
+	price := parsed.Number("price")
+	currency := parsed.String("currency")
+
+	coin, reply, err := resolveCoin(ctx, c.resolver, parsed.String("coin"), c.Name())
+	if err != nil || reply != "" {
+		return reply, err
+	}
+
+	// Default to the currency of an existing position, then to the preferred one
+	if currency == "" {
+		currency, err = c.positionCurrency(ctx, userJID, coin.ID)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if price == 0 {
+		quotes, err := c.provider.SpotPrices(ctx, []crypto.Coin{coin}, []string{currency})
+		if err != nil {
+			return "", err
+		}
+		quote, ok := quotes.Get(coin.ID, currency)
+		if !ok {
+			return i18n.T(ctx, "portfolio.no_price", strings.ToUpper(currency), coin), nil
+		}
+		price = quote.Price
+	}
+
+	position, err := c.store.AddToPosition(ctx, storage.Position{
+		UserJID:    userJID,
+		CoinID:     coin.ID,
+		CoinSymbol: coin.Symbol,
+		CoinName:   coin.Name,
+		Amount:     amount,
+		Cost:       amount * price,
+		Currency:   currency,
+	})
+	if errors.Is(err, storage.ErrCurrencyMismatch) {
+		return i18n.T(ctx, "portfolio.currency_mismatch",
+			coin, strings.ToUpper(position.Currency), strings.ToUpper(position.Currency)), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return i18n.T(ctx, "portfolio.added",
+		portfolio.FormatAmount(amount), strings.ToUpper(coin.Symbol), crypto.FormatPrice(price, currency),
+		portfolio.FormatAmount(position.Amount), strings.ToUpper(coin.Symbol),
+		crypto.FormatPrice(position.Cost/position.Amount, currency)), nil
+}
+
+// remove reduces the position of "<coin> [amount|all]", or deletes it when no
+// amount is given
+func (c *PortfolioCommand) remove(ctx context.Context, userJID string, parsed Parsed) (string, error) {
+	amount := 0.0
+	if parsed.String("amount") != "all" {
+		amount = parsed.Number("amount")
+	}
+
+	coin, reply, err := resolveCoin(ctx, c.resolver, parsed.String("coin"), c.Name())
+	if err != nil || reply != "" {
+		return reply, err
+	}
+
+	position, err := c.store.ReducePosition(ctx, userJID, coin.ID, amount)
+	if errors.Is(err, storage.ErrNotFound) {
+		return i18n.T(ctx, "portfolio.not_held", coin), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if position.Amount == 0 {
+		return i18n.T(ctx, "portfolio.removed_all", coin), nil
+	}
+	return i18n.T(ctx, "portfolio.removed",
+		portfolio.FormatAmount(amount), strings.ToUpper(coin.Symbol),
+		portfolio.FormatAmount(position.Amount), strings.ToUpper(coin.Symbol)), nil
+}
+
+// setCurrency changes the currency the portfolio is reported in, or shows it
+// when no currency is given
+func (c *PortfolioCommand) setCurrency(ctx context.Context, userJID, currency string) (string, error) {
+	if currency == "" {
+		settings, err := c.store.GetSettings(ctx, userJID)
+		if err != nil {
+			return "", err
+		}
+		return i18n.T(ctx, "portfolio.currency.current", strings.ToUpper(settings.Currency)), nil
+	}
+
+	// Make sure prices can be quoted in the currency before storing it
+	reference := crypto.Coin{ID: "bitcoin", Symbol: "btc", Name: "Bitcoin"}
+	quotes, err := c.provider.SpotPrices(ctx, []crypto.Coin{reference}, []string{currency})
+	if err != nil {
+		return "", err
+	}
+	if _, ok := quotes.Get(reference.ID, currency); !ok {
+		return i18n.T(ctx, "portfolio.currency.unsupported", strings.ToUpper(currency)), nil
+	}
+
+	if err := c.store.SetCurrency(ctx, userJID, currency); err != nil {
+		return "", err
+	}
+	return i18n.T(ctx, "portfolio.currency.set", strings.ToUpper(currency)), nil
+}
+
+// positionCurrency returns the currency of the user's position in a coin, or
+// the preferred currency when there is none
+func (c *PortfolioCommand) positionCurrency(ctx context.Context, userJID, coinID string) (string, error) {
+	positions, err := c.store.ListPositions(ctx, userJID)
+	if err != nil {
+		return "", err
+	}
+	for _, position := range positions {
+		if position.CoinID == coinID {
+			return position.Currency, nil
+		}
+	}
+
+	settings, err := c.store.GetSettings(ctx, userJID)
+	if err != nil {
+		return "", err
+	}
+	return settings.Currency, nil
+}
@@ -1,21 +1,50 @@
 package commands
 
 import (
-	"blockmind/internal/config"
 	"blockmind/internal/crypto"
+	"blockmind/internal/i18n"
 	"context"
 	"strings"
 )
 
+const (
+	// maxPriceCoins limits the number of coins in a single /price query
+	maxPriceCoins = 10
+	// maxPriceCurrencies limits the number of target currencies in a single /price query
+	maxPriceCurrencies = 5
+	// maxCoinTermWords is the most words of a coin name grouped into one term,
+	// as in "wrapped staked ether"
+	maxCoinTermWords = 4
+)
+
+// currencyKeywords introduce the target currencies, as in "/price btc in eur"
+var currencyKeywords = []string{"in", "to", "en", "em", "vs"}
+
+// priceArgs are the arguments of the price lookups: coins, then the target
+// currencies. Commas may separate either.
+var priceArgs = []Arg{
+	{Name: "coin", Repeated: true},
+	{Name: "currency", Kind: KindCurrency, Keywords: currencyKeywords, Optional: true, Repeated: true},
+}
+
+// fullFlag asks the price lookups for detailed quotes
+var fullFlag = Flag{
+	Name:     "full",
+	Aliases:  []string{"detail", "detailed"},
+	Synonyms: []string{"full", "detail", "detailed", "completo"},
+}
+
 // PriceCommand handles price inquiries for cryptocurrencies
 type PriceCommand struct {
-	cfg *config.Config
+	provider crypto.MarketDataProvider
+	resolver *crypto.Resolver
 }
 
 // NewPriceCommand creates a new price command
-func NewPriceCommand(cfg *config.Config) *PriceCommand {
+func NewPriceCommand(provider crypto.MarketDataProvider, resolver *crypto.Resolver) *PriceCommand {
 	return &PriceCommand{
-		cfg: cfg,
+		provider: provider,
+		resolver: resolver,
 	}
 }
 
@@ -31,29 +60,127 @@ func (c *PriceCommand) Aliases() []string {
 
 // Description returns the description of the command
 func (c *PriceCommand) Description() string {
-	return "Get the price of a cryptocurrency"
+	return i18n.Translate(i18n.DefaultLocale, "desc.price")
+}
+
+// Spec returns the arguments the command accepts
+func (c *PriceCommand) Spec() Spec {
+	return Spec{
+		Args:  priceArgs,
+		Flags: []Flag{fullFlag},
+	}
+}
+
+// Help returns a long description of the command in locale
+func (c *PriceCommand) Help(locale string) string {
+	return i18n.Translate(locale, "details.price")
+}
+
+// Examples returns sample uses of the command, explained in locale
+func (c *PriceCommand) Examples(locale string) []Example {
+	return []Example{
+		{Input: "/price btc", Effect: i18n.Translate(locale, "example.price.single")},
+		{Input: "/price btc eth in eur gbp", Effect: i18n.Translate(locale, "example.price.multiple")},
+		{Input: "/price sol --full", Effect: i18n.Translate(locale, "example.price.full")},
+	}
 }
 
 // Execute executes the command with the given arguments
 func (c *PriceCommand) Execute(ctx context.Context, args []string) (string, error) {
 	if len(args) == 0 {
-		return "Please specify a cryptocurrency (e.g., /price Bitcoin)", nil
+		return i18n.T(ctx, "price.usage", c.Name(), c.Name()), nil
+	}
+
+	parsed, reply := parseArgs(ctx, c, args)
+	if reply != "" {
+		return reply, nil
+	}
+	_, full := parsed.Flag("full")
+	return c.execute(ctx, c.Name(), parsed.Strings("coin"), parsed.Strings("currency"), full)
+}
+
+// execute looks up prices of coins in the target currencies, either as a
+// compact list or as detailed quotes. command is used in the suggestions for
+// unresolved coins.
+func (c *PriceCommand) execute(ctx context.Context, command string, coinArgs, targetCurrencies []string, detailed bool) (string, error) {
+	if len(targetCurrencies) > maxPriceCurrencies {
+		return i18n.T(ctx, "price.max_currencies", maxPriceCurrencies), nil
 	}
 
-	var cryptoName, targetCurrency string
+	// Every coin takes at least one word, and a name at most maxCoinTermWords
+	if len(coinArgs) > maxPriceCoins*maxCoinTermWords {
+		return i18n.T(ctx, "price.max_coins", maxPriceCoins), nil
+	}
+	terms := groupCoinTerms(ctx, c.resolver, coinArgs)
+	if len(terms) > maxPriceCoins {
+		return i18n.T(ctx, "price.max_coins", maxPriceCoins), nil
+	}
+
+	// Resolve every coin, collecting replies for the ones we could not resolve
+	var coins []crypto.Coin
+	var notes []string
+	for _, term := range terms {
+		coin, reply, err := resolveCoin(ctx, c.resolver, term, command)
+		if err != nil {
+			return "", err
+		}
+		if reply != "" {
+			notes = append(notes, reply)
+			continue
+		}
+		coins = append(coins, coin)
+	}
+
+	if len(coins) == 0 {
+		return strings.Join(notes, "\n\n"), nil
+	}
 
-	// Check if we have both crypto and currency
-	if len(args) >= 2 && (strings.ToLower(args[len(args)-2]) == "in" || strings.ToLower(args[len(args)-2]) == "to" || strings.ToLower(args[len(args)-2]) == "en") {
-		cryptoName = strings.Join(args[:len(args)-2], " ")
-		targetCurrency = args[len(args)-1]
+	var price string
+	var err error
+	if detailed {
+		price, err = crypto.GetCryptoQuotes(ctx, c.provider, coins, targetCurrencies)
 	} else {
-		cryptoName = strings.Join(args, " ")
-		targetCurrency = ""
+		price, err = crypto.GetCryptoPrices(ctx, c.provider, coins, targetCurrencies)
 	}
-	price, err := crypto.GetCryptoPrice(cryptoName, targetCurrency, c.cfg)
 	if err != nil {
 		return "", err
 	}
 
-	return price, nil
+	return strings.Join(append([]string{price}, notes...), "\n\n"), nil
+}
+
+// groupCoinTerms turns the coin arguments into one search term per coin. Commas
+// separate coins explicitly; otherwise the longest run of words naming a known
+// coin is grouped, so "shiba inu btc" yields "shiba inu" and "btc". Grouping
+// stops once there are more terms than maxPriceCoins.
+func groupCoinTerms(ctx context.Context, resolver *crypto.Resolver, args []string) []string {
+	joined := strings.Join(args, " ")
+	if strings.Contains(joined, ",") {
+		return splitList(joined)
+	}
+
+	var terms []string
+	for i := 0; i < len(args) && len(terms) <= maxPriceCoins; {
+		end := i + 1
+		for j := min(len(args), i+maxCoinTermWords); j > i+1; j-- {
+			if resolver.Known(ctx, strings.Join(args[i:j], " ")) {
+				end = j
+				break
+			}
+		}
+		terms = append(terms, strings.Join(args[i:end], " "))
+		i = end
+	}
+	return terms
+}
+
+// splitList splits a comma separated list, dropping empty entries
+func splitList(text string) []string {
+	var items []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"blockmind/internal/i18n"
+	"context"
+)
+
+// QuoteCommand shows detailed quotes including 24h change, volume and market cap
+type QuoteCommand struct {
+	price *PriceCommand
+}
+
+// NewQuoteCommand creates a new quote command sharing the lookups of the price command
+func NewQuoteCommand(price *PriceCommand) *QuoteCommand {
+	return &QuoteCommand{price: price}
+}
+
+// Name returns the name of the command
+func (c *QuoteCommand) Name() string {
+	return "quote"
+}
+
+// Aliases returns alternative names for the command
+func (c *QuoteCommand) Aliases() []string {
+	return []string{"q", "cotizacion"}
+}
+
+// Description returns the description of the command
+func (c *QuoteCommand) Description() string {
+	return i18n.Translate(i18n.DefaultLocale, "desc.quote")
+}
+
+// Spec returns the arguments the command accepts. Quotes are always
+// detailed; the flag is accepted so "/quote btc full" reads like /price.
+func (c *QuoteCommand) Spec() Spec {
+	return Spec{Args: priceArgs, Flags: []Flag{fullFlag}}
+}
+
+// Help returns a long description of the command in locale
+func (c *QuoteCommand) Help(locale string) string {
+	return i18n.Translate(locale, "details.quote")
+}
+
+// Examples returns sample uses of the command, explained in locale
+func (c *QuoteCommand) Examples(locale string) []Example {
+	return []Example{
+		{Input: "/quote btc", Effect: i18n.Translate(locale, "example.quote.single")},
+		{Input: "/quote eth in eur", Effect: i18n.Translate(locale, "example.quote.currency")},
+	}
+}
+
+// Execute executes the command with the given arguments
+func (c *QuoteCommand) Execute(ctx context.Context, args []string) (string, error) {
+	if len(args) == 0 {
+		return i18n.T(ctx, "price.usage", c.Name(), c.Name()), nil
+	}
+
+	parsed, reply := parseArgs(ctx, c, args)
+	if reply != "" {
+		return reply, nil
+	}
+	return c.price.execute(ctx, c.Name(), parsed.Strings("coin"), parsed.Strings("currency"), true)
+}
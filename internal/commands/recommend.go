@@ -1,19 +1,25 @@
 package commands
 
 import (
-	"blockmind/internal/config"
 	"blockmind/internal/crypto"
+	"blockmind/internal/i18n"
 	"blockmind/internal/ia"
+	"blockmind/internal/middleware"
+	"blockmind/internal/storage"
 	"context"
-	"strings"
+	"errors"
+	"math"
 )
 
 type RecommendCommand struct {
-	cfg *config.Config
+	assistant *ia.Assistant
+	provider  crypto.MarketDataProvider
+	resolver  *crypto.Resolver
+	store     *storage.Store
 }
 
-func NewRecommendCommand(cfg *config.Config) *RecommendCommand {
-	return &RecommendCommand{cfg: cfg}
+func NewRecommendCommand(assistant *ia.Assistant, provider crypto.MarketDataProvider, resolver *crypto.Resolver, store *storage.Store) *RecommendCommand {
+	return &RecommendCommand{assistant: assistant, provider: provider, resolver: resolver, store: store}
 }
 
 func (c *RecommendCommand) Name() string {
@@ -25,28 +31,148 @@ func (c *RecommendCommand) Aliases() []string {
 }
 
 func (c *RecommendCommand) Description() string {
-	return "Get a recommendation for a cryptocurrency"
+	return i18n.Translate(i18n.DefaultLocale, "desc.recommend")
+}
+
+func (c *RecommendCommand) Spec() Spec {
+	return Spec{
+		Args: []Arg{{Name: "coin", Repeated: true, Optional: true}},
+	}
+}
+
+func (c *RecommendCommand) Help(locale string) string {
+	return i18n.Translate(locale, "details.recommend")
+}
+
+func (c *RecommendCommand) Examples(locale string) []Example {
+	return []Example{
+		{Input: "/recommend eth", Effect: i18n.Translate(locale, "example.recommend.coin")},
+		{Input: "/recommend", Effect: i18n.Translate(locale, "example.recommend.watchlist")},
+	}
 }
 
 func (c *RecommendCommand) Execute(ctx context.Context, args []string) (string, error) {
-	if len(args) == 0 {
-		return "Please specify a cryptocurrency (e.g., /recommend Bitcoin)", nil
+	parsed, reply := parseArgs(ctx, c, args)
+	if reply != "" {
+		return reply, nil
+	}
+
+	var coin crypto.Coin
+	var note string
+	var err error
+	if !parsed.Has("coin") {
+		// Without arguments, recommend the watchlist coin that moved most
+		coin, reply, err = c.pickFromWatchlist(ctx)
+		note = i18n.T(ctx, "recommend.watchlist_pick", coin) + "\n\n"
+	} else {
+		coin, reply, err = resolveCoin(ctx, c.resolver, parsed.String("coin"), c.Name())
+	}
+	if err != nil || reply != "" {
+		return reply, err
+	}
+
+	recommendation_data, err := crypto.GetCryptoRecommendation(ctx, c.provider, coin)
+	if err != nil {
+		return "", err
+	}
+
+	// Sentiment is optional: not every market data provider reports it
+	withSentiment, err := crypto.GetSentimentAndHistoricalData(ctx, c.provider, recommendation_data, coin)
+	if err != nil && !errors.Is(err, crypto.ErrNotSupported) {
+		return "", err
+	}
+	if err == nil {
+		recommendation_data = withSentiment
+	}
+
+	recommendation_data, err = crypto.GetTechnicalIndicators(ctx, c.provider, recommendation_data, coin)
+	if err != nil {
+		return "", err
 	}
 
-	cryptoName := strings.Join(args, " ")
-	recommendation_data, err := crypto.GetCryptoRecommendation(cryptoName, c.cfg)
+	recommendation, err := c.assistant.GetInvestmentRecommendation(ctx, coin.Name, recommendation_data)
 	if err != nil {
 		return "", err
 	}
 
-	recommendation_data, err = crypto.GetSentimentAndHistoricalData(recommendation_data, cryptoName, c.cfg)
+	history, err := c.record(ctx, coin, recommendation)
+	if err != nil {
+		return "", err
+	}
+
+	reply = note + recommendation.Format(coin.Name, i18n.FromContext(ctx)) + history + "\n\n" + i18n.T(ctx, "recommend.disclaimer")
+
+	return reply, nil
+}
 
-	recommendation, err := ia.GetInvestmentRecommendation(cryptoName, recommendation_data, c.cfg)
+// record stores the recommendation and describes how it compares to the
+// previous one given to the user for the same coin
+func (c *RecommendCommand) record(ctx context.Context, coin crypto.Coin, rec ia.Recommendation) (string, error) {
+	userJID, ok := middleware.GetUserJID(ctx)
+	if !ok {
+		return "", nil
+	}
+
+	var history string
+	previous, err := c.store.LatestRecommendation(ctx, userJID, coin.ID)
+	switch {
+	case err == nil && !rec.Fallback:
+		history = "\n\n" + rec.FormatChange(ia.Recommendation{
+			Verdict:    previous.Verdict,
+			Confidence: previous.Confidence,
+		}, previous.CreatedAt, i18n.FromContext(ctx))
+	case err != nil && !errors.Is(err, storage.ErrNotFound):
+		return "", err
+	}
+
+	err = c.store.SaveRecommendation(ctx, &storage.Recommendation{
+		UserJID:     userJID,
+		CoinID:      coin.ID,
+		Verdict:     rec.Verdict,
+		Confidence:  rec.Confidence,
+		TimeHorizon: rec.TimeHorizon,
+		KeyRisks:    rec.KeyRisks,
+		Reasoning:   rec.Reasoning,
+		Fallback:    rec.Fallback,
+	})
 	if err != nil {
 		return "", err
 	}
+	return history, nil
+}
 
-	recommendation = recommendation + "\n\n" + "*This is not financial advice. Always do your own research.*"
+// pickFromWatchlist chooses the watchlist coin with the largest absolute 24h
+// price change, as the one most worth a fresh look
+func (c *RecommendCommand) pickFromWatchlist(ctx context.Context) (crypto.Coin, string, error) {
+	usage := i18n.T(ctx, "recommend.usage")
 
-	return recommendation, nil
+	userJID, ok := middleware.GetUserJID(ctx)
+	if !ok {
+		return crypto.Coin{}, usage, nil
+	}
+
+	coins, err := watchlistCoins(ctx, c.store, userJID)
+	if err != nil {
+		return crypto.Coin{}, "", err
+	}
+	if len(coins) == 0 {
+		return crypto.Coin{}, usage, nil
+	}
+	if len(coins) == 1 {
+		return coins[0], "", nil
+	}
+
+	quotes, err := c.provider.SpotPrices(ctx, coins, []string{"usd"})
+	if err != nil {
+		return crypto.Coin{}, "", err
+	}
+
+	picked := coins[0]
+	largest := -1.0
+	for _, coin := range coins {
+		if quote, ok := quotes.Get(coin.ID, "usd"); ok && math.Abs(quote.Change24h) > largest {
+			picked, largest = coin, math.Abs(quote.Change24h)
+		}
+	}
+	return picked, "", nil
 }
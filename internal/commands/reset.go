@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"blockmind/internal/i18n"
+	"blockmind/internal/ia"
+	"blockmind/internal/middleware"
+	"context"
+	"fmt"
+)
+
+// ResetCommand clears the conversation history of the current chat
+type ResetCommand struct {
+	memory *ia.ConversationMemory
+}
+
+// NewResetCommand creates a new reset command
+func NewResetCommand(memory *ia.ConversationMemory) *ResetCommand {
+	return &ResetCommand{memory: memory}
+}
+
+// Name returns the name of the command
+func (c *ResetCommand) Name() string {
+	return "reset"
+}
+
+// Aliases returns alternative names for the command
+func (c *ResetCommand) Aliases() []string {
+	return []string{"clear", "reiniciar", "limpar"}
+}
+
+// Description returns the description of the command
+func (c *ResetCommand) Description() string {
+	return i18n.Translate(i18n.DefaultLocale, "desc.reset")
+}
+
+// Help returns a long description of the command in locale
+func (c *ResetCommand) Help(locale string) string {
+	return i18n.Translate(locale, "details.reset")
+}
+
+// Examples returns sample uses of the command, explained in locale
+func (c *ResetCommand) Examples(locale string) []Example {
+	return []Example{
+		{Input: "/reset", Effect: i18n.Translate(locale, "example.reset.reset")},
+	}
+}
+
+// Execute executes the command with the given arguments
+func (c *ResetCommand) Execute(ctx context.Context, args []string) (string, error) {
+	chatID, ok := middleware.GetChatJID(ctx)
+	if !ok {
+		return "", fmt.Errorf("reset command requires a chat JID in the context")
+	}
+
+	c.memory.Reset(chatID)
+	return i18n.T(ctx, "reset.done"), nil
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"blockmind/internal/crypto"
+	"blockmind/internal/i18n"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// resolveCoin resolves user input to a coin. When the input is ambiguous or
+// unknown it returns a user-facing reply instead of an error.
+func resolveCoin(ctx context.Context, resolver *crypto.Resolver, query, command string) (crypto.Coin, string, error) {
+	coin, err := resolver.Resolve(ctx, query)
+	if err == nil {
+		return coin, "", nil
+	}
+
+	var ambiguous *crypto.AmbiguousCoinError
+	if errors.As(err, &ambiguous) {
+		return crypto.Coin{}, i18n.T(ctx, "resolve.ambiguous",
+			query, formatCoinSuggestions(ambiguous.Candidates, command)), nil
+	}
+
+	var unknown *crypto.UnknownCoinError
+	if errors.As(err, &unknown) {
+		if len(unknown.Suggestions) == 0 {
+			return crypto.Coin{}, i18n.T(ctx, "resolve.unknown", query), nil
+		}
+		return crypto.Coin{}, i18n.T(ctx, "resolve.unknown_suggestions",
+			query, formatCoinSuggestions(unknown.Suggestions, command)), nil
+	}
+
+	return crypto.Coin{}, "", err
+}
+
+// formatCoinSuggestions lists coins together with the command that selects each one
+func formatCoinSuggestions(coins []crypto.Coin, command string) string {
+	var lines []string
+	for _, coin := range coins {
+		lines = append(lines, fmt.Sprintf("• %s → /%s %s", coin, command, coin.ID))
+	}
+	return strings.Join(lines, "\n")
+}
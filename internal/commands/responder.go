@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"blockmind/internal/middleware"
+	"context"
+)
+
+// Responder delivers replies that cannot be returned as plain text, such as
+// images. The message handler places one in the context of every command.
+type Responder interface {
+	// SendImage sends an image with an optional caption to the current chat
+	SendImage(ctx context.Context, image []byte, mimeType, caption string) error
+}
+
+type responderKey struct{}
+
+// WithResponder returns a new context carrying the responder
+func WithResponder(ctx context.Context, responder Responder) context.Context {
+	return context.WithValue(ctx, responderKey{}, responder)
+}
+
+// deliveryChat returns the chat later notifications of a command go to: the
+// chat the message came from, or the user's own chat when it is unknown
+func deliveryChat(ctx context.Context, userJID string) string {
+	if chatJID, ok := middleware.GetChatJID(ctx); ok {
+		return chatJID
+	}
+	return userJID
+}
+
+// ResponderFromContext returns the responder stored in the context, if any
+func ResponderFromContext(ctx context.Context) (Responder, bool) {
+	responder, ok := ctx.Value(responderKey{}).(Responder)
+	return responder, ok
+}
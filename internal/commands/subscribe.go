@@ -0,0 +1,273 @@
+package commands
+
+import (
+	"blockmind/internal/crypto"
+	"blockmind/internal/digest"
+	"blockmind/internal/i18n"
+	"blockmind/internal/middleware"
+	"blockmind/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSubscriptionsPerUser limits the number of digests a chat can subscribe to
+const maxSubscriptionsPerUser = 5
+
+// timeOfDayPattern matches a 24-hour time such as 08:00 or 8:30
+var timeOfDayPattern = regexp.MustCompile(`^([01]?\d|2[0-3]):([0-5]\d)$`)
+
+// weekdays maps English, Spanish and Portuguese day names to weekdays
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday, "dom": time.Sunday, "domingo": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday, "lun": time.Monday, "lunes": time.Monday, "seg": time.Monday, "segunda": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday, "mar": time.Tuesday, "martes": time.Tuesday, "ter": time.Tuesday, "terça": time.Tuesday, "terca": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday, "mie": time.Wednesday, "miercoles": time.Wednesday, "miércoles": time.Wednesday, "qua": time.Wednesday, "quarta": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday, "jue": time.Thursday, "jueves": time.Thursday, "qui": time.Thursday, "quinta": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday, "vie": time.Friday, "viernes": time.Friday, "sex": time.Friday, "sexta": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday, "sab": time.Saturday, "sabado": time.Saturday, "sábado": time.Saturday, "sáb": time.Saturday,
+}
+
+// weekdayNames are the canonical day names shown in the usage
+var weekdayNames = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+// defaultDigestCoins are used when a subscription names no coins and the
+// watchlist is empty
+var defaultDigestCoins = []string{"bitcoin", "ethereum"}
+
+// SubscribeCommand manages scheduled market digests of the current chat
+type SubscribeCommand struct {
+	store    *storage.Store
+	resolver *crypto.Resolver
+	timezone string
+}
+
+// NewSubscribeCommand creates a new subscribe command. Subscriptions that do
+// not name a timezone use timezone.
+func NewSubscribeCommand(store *storage.Store, resolver *crypto.Resolver, timezone string) *SubscribeCommand {
+	return &SubscribeCommand{
+		store:    store,
+		resolver: resolver,
+		timezone: timezone,
+	}
+}
+
+// Name returns the name of the command
+func (c *SubscribeCommand) Name() string {
+	return "subscribe"
+}
+
+// Aliases returns alternative names for the command
+func (c *SubscribeCommand) Aliases() []string {
+	return []string{"subscriptions", "digest", "suscribir", "assinar"}
+}
+
+// Description returns the description of the command
+func (c *SubscribeCommand) Description() string {
+	return i18n.Translate(i18n.DefaultLocale, "desc.subscribe")
+}
+
+// Spec returns the arguments the command accepts
+func (c *SubscribeCommand) Spec() Spec {
+	// Every other day name maps to its canonical one, e.g. "lunes" to "mon"
+	days := make(map[string]string)
+	for name, day := range weekdays {
+		days[name] = strings.ToLower(day.String()[:3])
+	}
+
+	return Spec{
+		Subcommands: []Subcommand{
+			{Name: "digest", Aliases: []string{"resumen", "resumo"}, Args: []Arg{
+				{Name: "time", Kind: KindTime},
+				{Name: "frequency", Kind: KindChoice, Values: []string{"daily", "weekly"}, Optional: true, Synonyms: map[string]string{
+					"diario": "daily", "diário": "daily", "semanal": "weekly",
+				}},
+				{Name: "day", Kind: KindChoice, Values: weekdayNames, Synonyms: days, Optional: true},
+				{Name: "timezone", Keywords: []string{"tz", "timezone"}, Optional: true},
+				{Name: "coin", Repeated: true, Optional: true},
+			}},
+			{Name: "list", Aliases: []string{"lista"}},
+			{Name: "cancel", Aliases: []string{"delete", "remove", "stop", "cancelar", "eliminar"}, Args: []Arg{
+				{Name: "id", Kind: KindInteger},
+			}},
+		},
+		Default: "digest",
+		Empty:   "list",
+	}
+}
+
+// Help returns a long description of the command in locale
+func (c *SubscribeCommand) Help(locale string) string {
+	return i18n.Translate(locale, "details.subscribe")
+}
+
+// Examples returns sample uses of the command, explained in locale
+func (c *SubscribeCommand) Examples(locale string) []Example {
+	return []Example{
+		{Input: "/subscribe digest 08:00 btc eth sol", Effect: i18n.Translate(locale, "example.subscribe.daily")},
+		{Input: "/subscribe 18:30 weekly fri tz America/New_York", Effect: i18n.Translate(locale, "example.subscribe.weekly")},
+		{Input: "/subscribe list", Effect: i18n.Translate(locale, "example.subscribe.list")},
+		{Input: "/subscribe cancel 2", Effect: i18n.Translate(locale, "example.subscribe.cancel")},
+	}
+}
+
+// Execute executes the command with the given arguments
+func (c *SubscribeCommand) Execute(ctx context.Context, args []string) (string, error) {
+	userJID, ok := middleware.GetUserJID(ctx)
+	if !ok {
+		return "", fmt.Errorf("subscribe command requires a user JID in the context")
+	}
+
+	// A timezone may also be given without "tz", as in "Europe/Madrid"
+	var words []string
+	for i, arg := range args {
+		if (strings.Contains(arg, "/") || strings.EqualFold(arg, "utc")) && (i == 0 || !isTimezoneKeyword(args[i-1])) {
+			words = append(words, "tz")
+		}
+		words = append(words, arg)
+	}
+
+	parsed, reply := parseArgs(ctx, c, words)
+	if reply != "" {
+		return reply, nil
+	}
+
+	switch parsed.Subcommand {
+	case "list":
+		return c.list(ctx, userJID)
+	case "cancel":
+		return c.cancel(ctx, userJID, parsed.Integer("id"))
+	}
+	return c.create(ctx, userJID, parsed)
+}
+
+// isTimezoneKeyword reports whether word introduces a timezone
+func isTimezoneKeyword(word string) bool {
+	word = strings.ToLower(word)
+	return word == "tz" || word == "timezone"
+}
+
+// list shows the subscriptions of the user
+func (c *SubscribeCommand) list(ctx context.Context, userJID string) (string, error) {
+	subs, err := c.store.ListSubscriptions(ctx, userJID)
+	if err != nil {
+		return "", err
+	}
+	if len(subs) == 0 {
+		return i18n.T(ctx, "subscribe.none"), nil
+	}
+
+	var reply strings.Builder
+	reply.WriteString(i18n.T(ctx, "subscribe.list.title") + "\n")
+	for _, sub := range subs {
+		symbols := make([]string, len(sub.Coins))
+		for i, coin := range sub.Coins {
+			symbols[i] = strings.ToUpper(coin.Symbol)
+		}
+		reply.WriteString(fmt.Sprintf("#%d %s: %s\n", sub.ID, digest.FormatSchedule(sub, i18n.FromContext(ctx)), strings.Join(symbols, ", ")))
+	}
+	reply.WriteString("\n" + i18n.T(ctx, "subscribe.list.footer"))
+	return reply.String(), nil
+}
+
+// cancel removes a subscription by id
+func (c *SubscribeCommand) cancel(ctx context.Context, userJID string, id int64) (string, error) {
+	if err := c.store.DeleteSubscription(ctx, userJID, id); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return i18n.T(ctx, "subscribe.not_found", id), nil
+		}
+		return "", err
+	}
+	return i18n.T(ctx, "subscribe.cancelled", id), nil
+}
+
+// create stores the subscription of "<HH:MM> [daily|weekly] [day]
+// [tz <zone>] [coins...]"
+func (c *SubscribeCommand) create(ctx context.Context, userJID string, parsed Parsed) (string, error) {
+	sub := storage.Subscription{
+		UserJID:   userJID,
+		ChatJID:   deliveryChat(ctx, userJID),
+		Kind:      storage.SubscriptionDigest,
+		Frequency: storage.FrequencyDaily,
+		Timezone:  c.timezone,
+	}
+
+	match := timeOfDayPattern.FindStringSubmatch(parsed.String("time"))
+	hour, _ := strconv.Atoi(match[1])
+	minute, _ := strconv.Atoi(match[2])
+	sub.Minute = hour*60 + minute
+
+	// A day alone makes the digest weekly; weekly alone runs on Mondays
+	if parsed.String("frequency") == "weekly" || parsed.Has("day") {
+		sub.Frequency, sub.Weekday = storage.FrequencyWeekly, time.Monday
+	}
+	if parsed.Has("day") {
+		sub.Weekday = weekdays[parsed.String("day")]
+	}
+	if parsed.Has("timezone") {
+		sub.Timezone = parsed.String("timezone")
+	}
+	coinArgs := parsed.Strings("coin")
+	if strings.EqualFold(sub.Timezone, "utc") {
+		sub.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(sub.Timezone)
+	if err != nil {
+		return i18n.T(ctx, "subscribe.invalid_timezone", sub.Timezone), nil
+	}
+
+	count, err := c.store.CountSubscriptions(ctx, userJID)
+	if err != nil {
+		return "", err
+	}
+	if count >= maxSubscriptionsPerUser {
+		return i18n.T(ctx, "subscribe.limit", count), nil
+	}
+
+	// Without coins the digest follows the watchlist as it is now
+	if len(coinArgs) == 0 {
+		watched, err := c.store.Watchlist(ctx, userJID)
+		if err != nil {
+			return "", err
+		}
+		for _, coin := range watched {
+			sub.Coins = append(sub.Coins, storage.SubscribedCoin{ID: coin.ID, Symbol: coin.Symbol, Name: coin.Name})
+		}
+	}
+
+	var terms []string
+	switch {
+	case len(coinArgs) > 0:
+		terms = groupCoinTerms(ctx, c.resolver, coinArgs)
+	case len(sub.Coins) == 0:
+		terms = defaultDigestCoins
+	}
+	if len(terms) > maxPriceCoins {
+		return i18n.T(ctx, "subscribe.max_coins", maxPriceCoins), nil
+	}
+
+	for _, term := range terms {
+		coin, reply, err := resolveCoin(ctx, c.resolver, term, c.Name())
+		if err != nil || reply != "" {
+			return reply, err
+		}
+		sub.Coins = append(sub.Coins, storage.SubscribedCoin{ID: coin.ID, Symbol: coin.Symbol, Name: coin.Name})
+	}
+
+	sub.NextRunAt, err = digest.NextRun(sub, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if err := c.store.CreateSubscription(ctx, &sub); err != nil {
+		return "", err
+	}
+
+	locale := i18n.FromContext(ctx)
+	first := sub.NextRunAt.In(loc)
+	return i18n.T(ctx, "subscribe.created", sub.ID, digest.FormatSchedule(sub, locale),
+		i18n.Weekday(locale, first.Weekday())+" "+first.Format("2006-01-02 15:04")), nil
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"context"
+	"sort"
+	"unicode/utf8"
+)
+
+// maxSuggestions is the most commands offered for a mistyped name
+const maxSuggestions = 3
+
+// Suggestion is a registered command close to a mistyped name
+type Suggestion struct {
+	// Name is the closest of the command's name and aliases, so a typo of a
+	// Spanish alias is answered with that alias
+	Name     string
+	Command  Command
+	Distance int
+}
+
+// Suggest returns the commands the user may run whose name or an alias is
+// within typing distance of name, closest first. Each command appears once.
+func (m *Manager) Suggest(ctx context.Context, name string) []Suggestion {
+	limit := maxTypos(name)
+	if limit == 0 {
+		return nil
+	}
+
+	role := RoleFromContext(ctx)
+	best := make(map[Command]Suggestion)
+	for key, cmd := range m.commands {
+		if !allowed(role, cmd) {
+			continue
+		}
+		distance := editDistance(name, key)
+		if distance > limit || distance >= utf8.RuneCountInString(key) {
+			continue
+		}
+		current, seen := best[cmd]
+		if !seen || distance < current.Distance || (distance == current.Distance && key < current.Name) {
+			best[cmd] = Suggestion{Name: key, Command: cmd, Distance: distance}
+		}
+	}
+
+	suggestions := make([]Suggestion, 0, len(best))
+	for _, suggestion := range best {
+		suggestions = append(suggestions, suggestion)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Distance != suggestions[j].Distance {
+			return suggestions[i].Distance < suggestions[j].Distance
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions
+}
+
+// confident reports whether the closest suggestion is surely what was meant:
+// a single typo in a name long enough for it to stand out, with no other
+// command as close
+func confident(name string, suggestions []Suggestion) bool {
+	if len(suggestions) == 0 || suggestions[0].Distance != 1 || utf8.RuneCountInString(name) < 4 {
+		return false
+	}
+	return len(suggestions) == 1 || suggestions[1].Distance > 1
+}
+
+// maxTypos returns how many edits a name of its length may contain and still
+// be recognized; names too short to tell apart get no suggestions
+func maxTypos(name string) int {
+	switch length := utf8.RuneCountInString(name); {
+	case length < 3:
+		return 0
+	case length < 5:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance returns the number of insertions, deletions, substitutions and
+// swaps of adjacent characters that turn a into b, so "prcie" is one edit
+// from "price"
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	// Three rows are enough: swaps look two characters back
+	prev2 := make([]int, len(t)+1)
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(t)]
+}
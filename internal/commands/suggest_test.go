@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"blockmind/internal/storage"
+	"context"
+	"testing"
+)
+
+// newSuggestManager registers commands with Spanish aliases, some sharing
+// a stem, and the admin-only /admin
+func newSuggestManager() *Manager {
+	manager := NewManager(nil)
+	price := NewPriceCommand(nil, nil)
+	manager.Register(price)
+	manager.Register(NewQuoteCommand(price))
+	manager.Register(NewAlertCommand(nil, nil))
+	manager.Register(NewRecommendCommand(nil, nil, nil, nil))
+	manager.Register(NewWatchCommand(nil, nil, nil))
+	manager.Register(NewAdminCommand(nil, nil))
+	manager.Register(NewHelpCommand(manager))
+	return manager
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"price", "price", 0},
+		{"prcie", "price", 1},
+		{"pric", "price", 1},
+		{"prices", "price", 1},
+		{"prxce", "price", 1},
+		{"precoi", "precio", 1},
+		{"cotizacion", "cotización", 1},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		// A swap is not followed by an edit of the swapped characters
+		{"ca", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := editDistance(tt.b, tt.a); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestMaxTypos(t *testing.T) {
+	tests := []struct {
+		name string
+		want int
+	}{
+		{"", 0},
+		{"pr", 0},
+		{"añ", 0},
+		{"pri", 1},
+		{"año", 1},
+		{"pric", 1},
+		{"price", 2},
+		{"recomendar", 2},
+	}
+	for _, tt := range tests {
+		if got := maxTypos(tt.name); got != tt.want {
+			t.Errorf("maxTypos(%q) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	manager := newSuggestManager()
+	tests := []struct {
+		typed    string
+		name     string
+		distance int
+	}{
+		{"prcie", "price", 1},
+		{"qoute", "quote", 1},
+		// Typos of a Spanish alias are answered with the alias
+		{"precoi", "precio", 1},
+		{"recomedar", "recomendar", 1},
+		{"cotisacion", "cotizacion", 1},
+		{"wacth", "watch", 1},
+	}
+	for _, tt := range tests {
+		suggestions := manager.Suggest(context.Background(), tt.typed)
+		if len(suggestions) == 0 {
+			t.Errorf("Suggest(%q) = nothing, want %q", tt.typed, tt.name)
+			continue
+		}
+		if got := suggestions[0]; got.Name != tt.name || got.Distance != tt.distance {
+			t.Errorf("Suggest(%q)[0] = %q at %d, want %q at %d", tt.typed, got.Name, got.Distance, tt.name, tt.distance)
+		}
+	}
+
+	for _, typed := range []string{"pp", "xyzzy", "qq"} {
+		if suggestions := manager.Suggest(context.Background(), typed); len(suggestions) != 0 {
+			t.Errorf("Suggest(%q) = %v, want nothing", typed, suggestions)
+		}
+	}
+}
+
+func TestSuggestListsEachCommandOnce(t *testing.T) {
+	// "alrts" is within two edits of alert, alerts, alerta and alertas
+	suggestions := newSuggestManager().Suggest(context.Background(), "alrts")
+	if len(suggestions) != 1 {
+		t.Fatalf("Suggest(alrts) = %d suggestions, want 1", len(suggestions))
+	}
+	if got := suggestions[0]; got.Name != "alerts" || got.Distance != 1 {
+		t.Errorf("Suggest(alrts) = %q at %d, want the closest name alerts at 1", got.Name, got.Distance)
+	}
+}
+
+func TestSuggestFiltersByRole(t *testing.T) {
+	manager := newSuggestManager()
+	tests := []struct {
+		role string
+		want bool
+	}{
+		{storage.RoleBlocked, false},
+		{storage.RoleUser, false},
+		{storage.RoleAdmin, true},
+		{storage.RoleOwner, true},
+	}
+	for _, tt := range tests {
+		suggestions := manager.Suggest(WithRole(context.Background(), tt.role), "admn")
+		got := len(suggestions) > 0 && suggestions[0].Name == "admin"
+		if got != tt.want {
+			t.Errorf("Suggest(admn) as %s = %v, want admin suggested %v", tt.role, suggestions, tt.want)
+		}
+	}
+
+	if suggestions := manager.Suggest(WithRole(context.Background(), storage.RoleBlocked), "prcie"); len(suggestions) != 0 {
+		t.Errorf("Suggest(prcie) as blocked = %v, want nothing", suggestions)
+	}
+}
+
+func TestConfident(t *testing.T) {
+	price := NewPriceCommand(nil, nil)
+	quote := NewQuoteCommand(price)
+	tests := []struct {
+		name        string
+		typed       string
+		suggestions []Suggestion
+		want        bool
+	}{
+		{"single typo", "prcie", []Suggestion{{Name: "price", Command: price, Distance: 1}}, true},
+		{"next is farther", "prcie", []Suggestion{{Name: "price", Command: price, Distance: 1}, {Name: "quote", Command: quote, Distance: 2}}, true},
+		{"no suggestions", "prcie", nil, false},
+		{"two typos", "pricxx", []Suggestion{{Name: "price", Command: price, Distance: 2}}, false},
+		{"name too short", "qot", []Suggestion{{Name: "quote", Command: quote, Distance: 1}}, false},
+		{"ambiguous", "prcie", []Suggestion{{Name: "price", Command: price, Distance: 1}, {Name: "quote", Command: quote, Distance: 1}}, false},
+	}
+	for _, tt := range tests {
+		if got := confident(tt.typed, tt.suggestions); got != tt.want {
+			t.Errorf("%s: confident(%q) = %v, want %v", tt.name, tt.typed, got, tt.want)
+		}
+	}
+}
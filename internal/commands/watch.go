@@ -0,0 +1,208 @@
+package commands
+
+import (
+	"blockmind/internal/crypto"
+	"blockmind/internal/i18n"
+	"blockmind/internal/middleware"
+	"blockmind/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxWatchlistCoins limits the size of a watchlist so /watch fits one price request
+const maxWatchlistCoins = 25
+
+// WatchCommand manages the watchlist of the current chat
+type WatchCommand struct {
+	store    *storage.Store
+	provider crypto.MarketDataProvider
+	resolver *crypto.Resolver
+}
+
+// NewWatchCommand creates a new watch command
+func NewWatchCommand(store *storage.Store, provider crypto.MarketDataProvider, resolver *crypto.Resolver) *WatchCommand {
+	return &WatchCommand{
+		store:    store,
+		provider: provider,
+		resolver: resolver,
+	}
+}
+
+// Name returns the name of the command
+func (c *WatchCommand) Name() string {
+	return "watch"
+}
+
+// Aliases returns alternative names for the command
+func (c *WatchCommand) Aliases() []string {
+	return []string{"w", "watchlist", "seguir", "acompanhar"}
+}
+
+// Description returns the description of the command
+func (c *WatchCommand) Description() string {
+	return i18n.Translate(i18n.DefaultLocale, "desc.watch")
+}
+
+// Spec returns the arguments the command accepts
+func (c *WatchCommand) Spec() Spec {
+	coins := []Arg{{Name: "coin", Repeated: true}}
+	return Spec{
+		Subcommands: []Subcommand{
+			{Name: "list", Aliases: []string{"lista", "prices", "precios", "preços", "precos"}},
+			{Name: "add", Aliases: []string{"agregar", "adicionar"}, Args: coins},
+			{Name: "remove", Aliases: []string{"delete", "del", "rm", "quitar", "eliminar", "remover"}, Args: coins},
+		},
+		Empty: "list",
+	}
+}
+
+// Help returns a long description of the command in locale
+func (c *WatchCommand) Help(locale string) string {
+	return i18n.Translate(locale, "details.watch")
+}
+
+// Examples returns sample uses of the command, explained in locale
+func (c *WatchCommand) Examples(locale string) []Example {
+	return []Example{
+		{Input: "/watch add sol eth", Effect: i18n.Translate(locale, "example.watch.add")},
+		{Input: "/watch", Effect: i18n.Translate(locale, "example.watch.show")},
+		{Input: "/watch remove sol", Effect: i18n.Translate(locale, "example.watch.remove")},
+	}
+}
+
+// Execute executes the command with the given arguments
+func (c *WatchCommand) Execute(ctx context.Context, args []string) (string, error) {
+	userJID, ok := middleware.GetUserJID(ctx)
+	if !ok {
+		return "", fmt.Errorf("watch command requires a user JID in the context")
+	}
+
+	parsed, reply := parseArgs(ctx, c, args)
+	if reply != "" {
+		return reply, nil
+	}
+
+	switch parsed.Subcommand {
+	case "add":
+		return c.add(ctx, userJID, parsed.Strings("coin"))
+	case "remove":
+		return c.remove(ctx, userJID, parsed.Strings("coin"))
+	}
+	return c.prices(ctx, userJID)
+}
+
+// prices shows the prices of the whole watchlist in the preferred currency
+func (c *WatchCommand) prices(ctx context.Context, userJID string) (string, error) {
+	coins, err := watchlistCoins(ctx, c.store, userJID)
+	if err != nil {
+		return "", err
+	}
+	if len(coins) == 0 {
+		return i18n.T(ctx, "watch.empty"), nil
+	}
+
+	settings, err := c.store.GetSettings(ctx, userJID)
+	if err != nil {
+		return "", err
+	}
+	prices, err := crypto.GetCryptoPriceChanges(ctx, c.provider, coins, settings.Currency)
+	if err != nil {
+		return "", err
+	}
+	return i18n.T(ctx, "watch.title") + "\n" + prices, nil
+}
+
+// add resolves one or more coins and adds them to the watchlist
+func (c *WatchCommand) add(ctx context.Context, userJID string, args []string) (string, error) {
+	watched, err := c.store.Watchlist(ctx, userJID)
+	if err != nil {
+		return "", err
+	}
+
+	var added, present, notes []string
+	for _, term := range groupCoinTerms(ctx, c.resolver, args) {
+		coin, reply, err := resolveCoin(ctx, c.resolver, term, c.Name()+" add")
+		if err != nil {
+			return "", err
+		}
+		if reply != "" {
+			notes = append(notes, reply)
+			continue
+		}
+
+		if len(watched)+len(added) >= maxWatchlistCoins {
+			notes = append(notes, i18n.T(ctx, "watch.full", maxWatchlistCoins))
+			break
+		}
+
+		ok, err := c.store.AddToWatchlist(ctx, userJID, storage.WatchedCoin{ID: coin.ID, Symbol: coin.Symbol, Name: coin.Name})
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			added = append(added, coin.String())
+		} else {
+			present = append(present, coin.String())
+		}
+	}
+
+	var lines []string
+	if len(added) > 0 {
+		lines = append(lines, i18n.T(ctx, "watch.added", strings.Join(added, ", ")))
+	}
+	if len(present) > 0 {
+		lines = append(lines, i18n.T(ctx, "watch.present", strings.Join(present, ", ")))
+	}
+	return strings.Join(append(lines, notes...), "\n\n"), nil
+}
+
+// remove resolves one or more coins and removes them from the watchlist
+func (c *WatchCommand) remove(ctx context.Context, userJID string, args []string) (string, error) {
+	var removed, missing, notes []string
+	for _, term := range groupCoinTerms(ctx, c.resolver, args) {
+		coin, reply, err := resolveCoin(ctx, c.resolver, term, c.Name()+" remove")
+		if err != nil {
+			return "", err
+		}
+		if reply != "" {
+			notes = append(notes, reply)
+			continue
+		}
+
+		err = c.store.RemoveFromWatchlist(ctx, userJID, coin.ID)
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			missing = append(missing, coin.String())
+		case err != nil:
+			return "", err
+		default:
+			removed = append(removed, coin.String())
+		}
+	}
+
+	var lines []string
+	if len(removed) > 0 {
+		lines = append(lines, i18n.T(ctx, "watch.removed", strings.Join(removed, ", ")))
+	}
+	if len(missing) > 0 {
+		lines = append(lines, i18n.T(ctx, "watch.missing", strings.Join(missing, ", ")))
+	}
+	return strings.Join(append(lines, notes...), "\n\n"), nil
+}
+
+// watchlistCoins returns the user's watchlist as coins, for commands that
+// default to it when called without arguments
+func watchlistCoins(ctx context.Context, store *storage.Store, userJID string) ([]crypto.Coin, error) {
+	watched, err := store.Watchlist(ctx, userJID)
+	if err != nil {
+		return nil, err
+	}
+
+	coins := make([]crypto.Coin, len(watched))
+	for i, coin := range watched {
+		coins[i] = crypto.Coin{ID: coin.ID, Symbol: coin.Symbol, Name: coin.Name}
+	}
+	return coins, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -19,14 +20,74 @@ type Config struct {
 	AIMaxTokens       int
 	AITemperature     float64
 
+	// Reference documents for Q&A and the number of passages sent per question
+	KnowledgeDir      string
+	KnowledgePassages int
+
+	// Calls the model may make to live data tools while answering a question
+	AIMaxToolCalls int
+
+	// Routing of free-text messages to commands, by rules and optionally by
+	// the model, above a minimum confidence
+	IntentRouting   bool
+	IntentLLM       bool
+	IntentThreshold float64
+
+	// Mistyped commands run the command they surely meant instead of only
+	// suggesting it
+	CommandAutocorrect bool
+
+	// Streamed answers are edited into the chat at most once per interval
+	AIStreaming          bool
+	AIStreamEditInterval time.Duration
+
+	// LLM backend: huggingface, openai (any OpenAI-compatible server) or ollama.
+	// LLMModel is the default model; Q&A and recommendations may use their own.
+	LLMBackend        string
+	LLMBaseURL        string
+	LLMAPIKey         string
+	LLMModel          string
+	LLMQAModel        string
+	LLMRecommendModel string
+
+	// Conversation memory for Q&A
+	AIMemoryTurns  int
+	AIMemoryTokens int
+	AIMemoryTTL    time.Duration
+
 	// Coingecko
-	CoingeckoAPIKey  string
-	CoingeckoBaseURL string
+	CoingeckoAPIKey   string
+	CoingeckoBaseURL  string
+	CoinListCachePath string
+
+	// Market data providers, tried in order
+	MarketDataProviders []string
+	BinanceBaseURL      string
+
+	// Response cache for CoinGecko requests
+	CacheEnabled    bool
+	CachePriceTTL   time.Duration
+	CacheMarketsTTL time.Duration
+	CacheCoinTTL    time.Duration
+	CacheMaxStale   time.Duration
+
+	// Bot data storage
+	DatabasePath string
+
+	// Price alerts
+	AlertCheckInterval time.Duration
+
+	// Market digests
+	DigestTimezone     string
+	DigestAICommentary bool
 
 	// WhatsApp
 	WhatsAppDBPath   string
 	WhatsAppLogLevel string
 
+	// Administration: the WhatsApp account allowed to run admin commands
+	OwnerJID string
+
 	// Rate Limiting
 	RateLimit       int
 	RateLimitPeriod time.Duration
@@ -43,17 +104,39 @@ func Load() (*Config, error) {
 
 	config := &Config{
 		// Default values
-		AITimeout:         20 * time.Second,
-		AIMaxTokens:       250,
-		AITemperature:     0.0,
-		HuggingFaceAPIURL: "https://router.huggingface.co/hf-inference/models/",
-		CoingeckoBaseURL:  "https://api.coingecko.com/api/v3",
-		WhatsAppDBPath:    "file:whatsapp.db?_foreign_keys=on",
-		WhatsAppLogLevel:  "INFO",
-		RateLimit:         5,
-		RateLimitPeriod:   time.Minute,
-		CommandTimeout:    25 * time.Second,
-		Debug:             false,
+		AITimeout:            20 * time.Second,
+		AIMaxTokens:          250,
+		AITemperature:        0.0,
+		AIMaxToolCalls:       3,
+		IntentRouting:        true,
+		IntentThreshold:      0.7,
+		AIStreaming:          true,
+		AIStreamEditInterval: 2 * time.Second,
+		KnowledgeDir:         "knowledge",
+		KnowledgePassages:    3,
+		AIMemoryTurns:        6,
+		AIMemoryTokens:       1500,
+		AIMemoryTTL:          30 * time.Minute,
+		HuggingFaceAPIURL:    "https://router.huggingface.co/hf-inference/models/",
+		LLMBackend:           "huggingface",
+		CoingeckoBaseURL:     "https://api.coingecko.com/api/v3",
+		CoinListCachePath:    "coin_list.json",
+		MarketDataProviders:  []string{"coingecko"},
+		BinanceBaseURL:       "https://api.binance.com",
+		CacheEnabled:         true,
+		CachePriceTTL:        time.Minute,
+		CacheMarketsTTL:      2 * time.Minute,
+		CacheCoinTTL:         5 * time.Minute,
+		CacheMaxStale:        time.Hour,
+		DatabasePath:         "file:blockmind.db?_foreign_keys=on&_busy_timeout=5000",
+		AlertCheckInterval:   time.Minute,
+		DigestTimezone:       "UTC",
+		WhatsAppDBPath:       "file:whatsapp.db?_foreign_keys=on",
+		WhatsAppLogLevel:     "INFO",
+		RateLimit:            5,
+		RateLimitPeriod:      time.Minute,
+		CommandTimeout:       25 * time.Second,
+		Debug:                false,
 	}
 
 	// Required values
@@ -62,6 +145,30 @@ func Load() (*Config, error) {
 	config.CoingeckoAPIKey = os.Getenv("COINGECKO_API_KEY")
 
 	// Optional values with overrides
+	if val := os.Getenv("LLM_BACKEND"); val != "" {
+		config.LLMBackend = strings.ToLower(val)
+	}
+
+	config.LLMBaseURL = os.Getenv("LLM_BASE_URL")
+	if config.LLMBaseURL == "" && config.LLMBackend == "ollama" {
+		config.LLMBaseURL = "http://localhost:11434"
+	}
+	config.LLMAPIKey = os.Getenv("LLM_API_KEY")
+
+	// Models fall back from the feature to LLM_MODEL to HUGGINGFACE_MODEL
+	config.LLMModel = os.Getenv("LLM_MODEL")
+	if config.LLMModel == "" {
+		config.LLMModel = config.HuggingFaceModel
+	}
+	config.LLMQAModel = os.Getenv("LLM_QA_MODEL")
+	if config.LLMQAModel == "" {
+		config.LLMQAModel = config.LLMModel
+	}
+	config.LLMRecommendModel = os.Getenv("LLM_RECOMMEND_MODEL")
+	if config.LLMRecommendModel == "" {
+		config.LLMRecommendModel = config.LLMModel
+	}
+
 	if val := os.Getenv("AI_TIMEOUT"); val != "" {
 		if seconds, err := strconv.Atoi(val); err == nil {
 			config.AITimeout = time.Duration(seconds) * time.Second
@@ -80,6 +187,86 @@ func Load() (*Config, error) {
 		}
 	}
 
+	if val, ok := os.LookupEnv("KNOWLEDGE_DIR"); ok {
+		config.KnowledgeDir = val
+	}
+
+	if val := os.Getenv("KNOWLEDGE_PASSAGES"); val != "" {
+		if passages, err := strconv.Atoi(val); err == nil && passages >= 0 {
+			config.KnowledgePassages = passages
+		}
+	}
+
+	if val := os.Getenv("AI_MAX_TOOL_CALLS"); val != "" {
+		if calls, err := strconv.Atoi(val); err == nil && calls >= 0 {
+			config.AIMaxToolCalls = calls
+		}
+	}
+
+	if val := os.Getenv("INTENT_ROUTING"); val == "false" {
+		config.IntentRouting = false
+	}
+
+	if val := os.Getenv("INTENT_LLM"); val == "true" {
+		config.IntentLLM = true
+	}
+
+	if val := os.Getenv("INTENT_THRESHOLD"); val != "" {
+		if threshold, err := strconv.ParseFloat(val, 64); err == nil && threshold >= 0 && threshold <= 1 {
+			config.IntentThreshold = threshold
+		}
+	}
+
+	if val := os.Getenv("COMMAND_AUTOCORRECT"); val == "true" {
+		config.CommandAutocorrect = true
+	}
+
+	if val := os.Getenv("AI_STREAMING"); val == "false" {
+		config.AIStreaming = false
+	}
+
+	if val := os.Getenv("AI_STREAM_EDIT_INTERVAL"); val != "" {
+		if seconds, err := strconv.Atoi(val); err == nil && seconds > 0 {
+			config.AIStreamEditInterval = time.Duration(seconds) * time.Second
+		}
+	}
+
+	if val := os.Getenv("AI_MEMORY_TURNS"); val != "" {
+		if turns, err := strconv.Atoi(val); err == nil && turns >= 0 {
+			config.AIMemoryTurns = turns
+		}
+	}
+
+	if val := os.Getenv("AI_MEMORY_TOKENS"); val != "" {
+		if tokens, err := strconv.Atoi(val); err == nil && tokens > 0 {
+			config.AIMemoryTokens = tokens
+		}
+	}
+
+	if val := os.Getenv("AI_MEMORY_TTL"); val != "" {
+		if seconds, err := strconv.Atoi(val); err == nil && seconds > 0 {
+			config.AIMemoryTTL = time.Duration(seconds) * time.Second
+		}
+	}
+
+	if val := os.Getenv("DATABASE_PATH"); val != "" {
+		config.DatabasePath = val
+	}
+
+	if val := os.Getenv("ALERT_CHECK_INTERVAL"); val != "" {
+		if seconds, err := strconv.Atoi(val); err == nil && seconds > 0 {
+			config.AlertCheckInterval = time.Duration(seconds) * time.Second
+		}
+	}
+
+	if val := os.Getenv("DIGEST_TIMEZONE"); val != "" {
+		config.DigestTimezone = val
+	}
+
+	if val := os.Getenv("DIGEST_AI_COMMENTARY"); val == "true" {
+		config.DigestAICommentary = true
+	}
+
 	if val := os.Getenv("WHATSAPP_DB_PATH"); val != "" {
 		config.WhatsAppDBPath = val
 	}
@@ -110,10 +297,63 @@ func Load() (*Config, error) {
 		config.CoingeckoBaseURL = val
 	}
 
+	if val := os.Getenv("COIN_LIST_CACHE_PATH"); val != "" {
+		config.CoinListCachePath = val
+	}
+
+	if val := os.Getenv("MARKET_DATA_PROVIDERS"); val != "" {
+		config.MarketDataProviders = nil
+		for _, name := range strings.Split(val, ",") {
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+				config.MarketDataProviders = append(config.MarketDataProviders, name)
+			}
+		}
+	}
+
+	if val := os.Getenv("BINANCE_API_URL"); val != "" {
+		config.BinanceBaseURL = val
+	}
+
+	if val := os.Getenv("CACHE_ENABLED"); val == "false" {
+		config.CacheEnabled = false
+	}
+
+	if val := os.Getenv("CACHE_PRICE_TTL"); val != "" {
+		if seconds, err := strconv.Atoi(val); err == nil {
+			config.CachePriceTTL = time.Duration(seconds) * time.Second
+		}
+	}
+
+	if val := os.Getenv("CACHE_MARKETS_TTL"); val != "" {
+		if seconds, err := strconv.Atoi(val); err == nil {
+			config.CacheMarketsTTL = time.Duration(seconds) * time.Second
+		}
+	}
+
+	if val := os.Getenv("CACHE_COIN_TTL"); val != "" {
+		if seconds, err := strconv.Atoi(val); err == nil {
+			config.CacheCoinTTL = time.Duration(seconds) * time.Second
+		}
+	}
+
+	if val := os.Getenv("CACHE_MAX_STALE"); val != "" {
+		if seconds, err := strconv.Atoi(val); err == nil {
+			config.CacheMaxStale = time.Duration(seconds) * time.Second
+		}
+	}
+
 	if val := os.Getenv("HUGGINGFACE_BASE_URL"); val != "" {
 		config.HuggingFaceAPIURL = val
 	}
 
+	// A bare phone number is taken as a WhatsApp user
+	if val := os.Getenv("OWNER_JID"); val != "" {
+		config.OwnerJID = strings.TrimPrefix(val, "+")
+		if !strings.Contains(config.OwnerJID, "@") {
+			config.OwnerJID += "@s.whatsapp.net"
+		}
+	}
+
 	if val := os.Getenv("DEBUG"); val == "true" {
 		config.Debug = true
 	}
@@ -128,20 +368,37 @@ func Load() (*Config, error) {
 
 // validate ensures all required configuration values are set
 func (c *Config) validate() error {
-	if c.HuggingFaceAPIKey == "" {
-		return fmt.Errorf("missing required environment variable: HUGGINGFACE_API_KEY")
+	switch c.LLMBackend {
+	case "huggingface":
+		if c.HuggingFaceAPIKey == "" {
+			return fmt.Errorf("missing required environment variable: HUGGINGFACE_API_KEY")
+		}
+	case "openai", "ollama":
+		if c.LLMBaseURL == "" {
+			return fmt.Errorf("missing required environment variable for LLM_BACKEND=%s: LLM_BASE_URL", c.LLMBackend)
+		}
+	default:
+		return fmt.Errorf("unknown LLM_BACKEND: %s (use huggingface, openai or ollama)", c.LLMBackend)
 	}
-	if c.HuggingFaceModel == "" {
-		return fmt.Errorf("missing required environment variable: HUGGINGFACE_MODEL")
+	if c.LLMModel == "" {
+		return fmt.Errorf("missing required environment variable: LLM_MODEL or HUGGINGFACE_MODEL")
 	}
 
-	if c.CoingeckoAPIKey == "" {
-		return fmt.Errorf("missing required environment variable: COINGECKO_API_KEY")
+	if _, err := time.LoadLocation(c.DigestTimezone); err != nil {
+		return fmt.Errorf("invalid DIGEST_TIMEZONE %q: %w", c.DigestTimezone, err)
 	}
-	return nil
-}
 
-// GetHuggingFaceAPIURL returns the constructed API URL for the HuggingFace model
-func (c *Config) GetHuggingFaceAPIURL() string {
-	return c.HuggingFaceAPIURL + c.HuggingFaceModel + "/v1/chat/completions"
+	if len(c.MarketDataProviders) == 0 {
+		return fmt.Errorf("MARKET_DATA_PROVIDERS must name at least one provider")
+	}
+	for _, name := range c.MarketDataProviders {
+		if name != "coingecko" && name != "binance" {
+			return fmt.Errorf("unknown market data provider in MARKET_DATA_PROVIDERS: %s", name)
+		}
+		// Only CoinGecko market data needs the key
+		if name == "coingecko" && c.CoingeckoAPIKey == "" {
+			return fmt.Errorf("missing required environment variable: COINGECKO_API_KEY")
+		}
+	}
+	return nil
 }
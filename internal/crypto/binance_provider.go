@@ -0,0 +1,298 @@
+package crypto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBinanceBaseURL is the public Binance spot REST endpoint
+const DefaultBinanceBaseURL = "https://api.binance.com"
+
+// binanceInvalidSymbol is the error code of a pair Binance does not list
+const binanceInvalidSymbol = -1121
+
+// unlistedRetry is how long a pair Binance does not list is left out of
+// batches before it is tried again, in case it was listed since
+const unlistedRetry = 6 * time.Hour
+
+// BinanceProvider is a MarketDataProvider backed by the public Binance spot
+// API. Coins are matched by symbol and USD is quoted through USDT pairs.
+// Binance reports no market caps or coin metadata.
+type BinanceProvider struct {
+	baseURL    string
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	unlisted map[string]time.Time
+}
+
+// NewBinanceProvider creates a Binance provider. An empty baseURL falls back to
+// DefaultBinanceBaseURL and a nil httpClient to one with a 15 second timeout.
+func NewBinanceProvider(baseURL string, httpClient *http.Client) *BinanceProvider {
+	if baseURL == "" {
+		baseURL = DefaultBinanceBaseURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 15 * time.Second}
+	}
+	return &BinanceProvider{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+		unlisted:   make(map[string]time.Time),
+	}
+}
+
+// binanceTicker is an entry of /api/v3/ticker/24hr
+type binanceTicker struct {
+	Symbol             string `json:"symbol"`
+	PriceChange        string `json:"priceChange"`
+	PriceChangePercent string `json:"priceChangePercent"`
+	LastPrice          string `json:"lastPrice"`
+	HighPrice          string `json:"highPrice"`
+	LowPrice           string `json:"lowPrice"`
+	QuoteVolume        string `json:"quoteVolume"`
+	CloseTime          int64  `json:"closeTime"`
+}
+
+// binanceError is the error body returned by the Binance API
+type binanceError struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+
+	path   string
+	status int
+}
+
+// Error implements the error interface
+func (e *binanceError) Error() string {
+	if e.Msg == "" {
+		return fmt.Sprintf("binance %s: status %d", e.path, e.status)
+	}
+	return fmt.Sprintf("binance %s: status %d: %s", e.path, e.status, e.Msg)
+}
+
+// isInvalidSymbol reports whether err says a pair is not listed
+func isInvalidSymbol(err error) bool {
+	var apiErr *binanceError
+	return errors.As(err, &apiErr) && apiErr.Code == binanceInvalidSymbol
+}
+
+// Name returns the name of the provider
+func (p *BinanceProvider) Name() string {
+	return "binance"
+}
+
+// SpotPrices fetches 24h tickers for all coin/currency pairs in one request.
+// When some pair is not listed the pairs are fetched one by one to find it,
+// and it is left out of later batches.
+func (p *BinanceProvider) SpotPrices(ctx context.Context, coins []Coin, vsCurrencies []string) (Quotes, error) {
+	type pair struct {
+		id string
+		vs string
+	}
+	pairs := make(map[string]pair)
+	var symbols []string
+	for _, coin := range coins {
+		for _, vs := range vsCurrencies {
+			symbol := binanceSymbol(coin, vs)
+			pairs[symbol] = pair{id: coin.ID, vs: vs}
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	symbols = p.listed(symbols)
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("binance spot prices: no pair is listed: %w", ErrNotSupported)
+	}
+
+	encoded, err := json.Marshal(symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	var tickers []binanceTicker
+	err = p.get(ctx, "/api/v3/ticker/24hr", url.Values{"symbols": {string(encoded)}}, &tickers)
+	if err != nil {
+		if !isInvalidSymbol(err) {
+			return nil, err
+		}
+
+		// An unlisted pair fails the whole batch, so retry the pairs one by one
+		tickers = nil
+		for _, symbol := range symbols {
+			var ticker binanceTicker
+			switch err := p.get(ctx, "/api/v3/ticker/24hr", url.Values{"symbol": {symbol}}, &ticker); {
+			case err == nil:
+				tickers = append(tickers, ticker)
+			case isInvalidSymbol(err):
+				p.markUnlisted(symbol)
+			}
+		}
+		if len(tickers) == 0 {
+			return nil, err
+		}
+	}
+
+	quotes := make(Quotes)
+	for _, ticker := range tickers {
+		if pair, ok := pairs[ticker.Symbol]; ok {
+			quotes.set(pair.id, pair.vs, Quote{
+				Price:         parseFloat(ticker.LastPrice),
+				Volume24h:     parseFloat(ticker.QuoteVolume),
+				Change24h:     parseFloat(ticker.PriceChangePercent),
+				LastUpdatedAt: time.UnixMilli(ticker.CloseTime),
+			})
+		}
+	}
+	return quotes, nil
+}
+
+// listed returns the symbols not known to be unlisted
+func (p *BinanceProvider) listed(symbols []string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var listed []string
+	for _, symbol := range symbols {
+		if since, ok := p.unlisted[symbol]; ok && time.Since(since) < unlistedRetry {
+			continue
+		}
+		listed = append(listed, symbol)
+	}
+	return listed
+}
+
+// markUnlisted leaves symbol out of batches for a while
+func (p *BinanceProvider) markUnlisted(symbol string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.unlisted[symbol] = time.Now()
+}
+
+// MarketSnapshot fetches the 24h ticker of a coin
+func (p *BinanceProvider) MarketSnapshot(ctx context.Context, coin Coin, vsCurrency string) (*Snapshot, error) {
+	var ticker binanceTicker
+	query := url.Values{"symbol": {binanceSymbol(coin, vsCurrency)}}
+	if err := p.get(ctx, "/api/v3/ticker/24hr", query, &ticker); err != nil {
+		return nil, err
+	}
+
+	return &Snapshot{
+		Coin:              coin,
+		Currency:          vsCurrency,
+		Price:             parseFloat(ticker.LastPrice),
+		Volume24h:         parseFloat(ticker.QuoteVolume),
+		High24h:           parseFloat(ticker.HighPrice),
+		Low24h:            parseFloat(ticker.LowPrice),
+		PriceChange24h:    parseFloat(ticker.PriceChange),
+		PriceChangePct24h: parseFloat(ticker.PriceChangePercent),
+		LastUpdated:       time.UnixMilli(ticker.CloseTime),
+	}, nil
+}
+
+// History fetches klines covering the last days, using the closing price of
+// each candle. The interval is chosen to keep a few hundred points.
+func (p *BinanceProvider) History(ctx context.Context, coin Coin, vsCurrency string, days int) ([]PricePoint, error) {
+	interval, limit := "1d", days
+	switch {
+	case days <= 1:
+		interval, limit = "5m", 288
+	case days <= 7:
+		interval, limit = "1h", days*24
+	case days <= 90:
+		interval, limit = "4h", days*6
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	query := url.Values{
+		"symbol":   {binanceSymbol(coin, vsCurrency)},
+		"interval": {interval},
+		"limit":    {strconv.Itoa(limit)},
+	}
+
+	var klines [][]json.RawMessage
+	if err := p.get(ctx, "/api/v3/klines", query, &klines); err != nil {
+		return nil, err
+	}
+
+	points := make([]PricePoint, 0, len(klines))
+	for _, kline := range klines {
+		// [open time, open, high, low, close, volume, close time, ...]
+		if len(kline) < 7 {
+			continue
+		}
+		var closePrice string
+		var closeTime int64
+		if json.Unmarshal(kline[4], &closePrice) != nil || json.Unmarshal(kline[6], &closeTime) != nil {
+			continue
+		}
+		points = append(points, PricePoint{Time: time.UnixMilli(closeTime), Price: parseFloat(closePrice)})
+	}
+	return points, nil
+}
+
+// CoinInfo is not available from Binance
+func (p *BinanceProvider) CoinInfo(ctx context.Context, coin Coin) (*CoinInfo, error) {
+	return nil, fmt.Errorf("binance coin info: %w", ErrNotSupported)
+}
+
+// get performs a GET request against the API and decodes the JSON body into out
+func (p *BinanceProvider) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &binanceError{path: path, status: resp.StatusCode}
+		if json.Unmarshal(body, apiErr) != nil {
+			apiErr.Code, apiErr.Msg = 0, ""
+		}
+		return apiErr
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response from %s: %w", path, err)
+	}
+	return nil
+}
+
+// binanceSymbol returns the Binance trading pair of a coin, e.g. BTCUSDT
+func binanceSymbol(coin Coin, vsCurrency string) string {
+	quote := strings.ToUpper(vsCurrency)
+	if quote == "USD" {
+		quote = "USDT"
+	}
+	return strings.ToUpper(coin.Symbol) + quote
+}
+
+// parseFloat parses the decimal strings used by Binance, returning 0 on failure
+func parseFloat(value string) float64 {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
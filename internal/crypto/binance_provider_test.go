@@ -0,0 +1,180 @@
+package crypto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tickerServer is a test /api/v3/ticker/24hr endpoint listing only the pairs
+// in prices. It records the symbols asked for in each request.
+type tickerServer struct {
+	prices map[string]string
+	status int
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func (s *tickerServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	s.mu.Lock()
+	s.requests = append(s.requests, query.Get("symbol")+query.Get("symbols"))
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+		w.Write([]byte(`{"code": -1003, "msg": "Too many requests."}`))
+		return
+	}
+
+	var symbols []string
+	if symbol := query.Get("symbol"); symbol != "" {
+		symbols = []string{symbol}
+	} else if err := json.Unmarshal([]byte(query.Get("symbols")), &symbols); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var tickers []binanceTicker
+	for _, symbol := range symbols {
+		price, ok := s.prices[symbol]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code": -1121, "msg": "Invalid symbol."}`))
+			return
+		}
+		tickers = append(tickers, binanceTicker{Symbol: symbol, LastPrice: price})
+	}
+	if query.Get("symbol") != "" {
+		json.NewEncoder(w).Encode(tickers[0])
+		return
+	}
+	json.NewEncoder(w).Encode(tickers)
+}
+
+// takeRequests returns the requests made since the last call
+func (s *tickerServer) takeRequests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	requests := s.requests
+	s.requests = nil
+	return requests
+}
+
+// newTestBinance returns a provider talking to server
+func newTestBinance(t *testing.T, server *tickerServer) *BinanceProvider {
+	t.Helper()
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	return NewBinanceProvider(httpServer.URL, httpServer.Client())
+}
+
+var unlistedCoin = Coin{ID: "unlisted", Symbol: "nope", Name: "Unlisted"}
+
+func TestBinanceSpotPrices(t *testing.T) {
+	server := &tickerServer{prices: map[string]string{"BTCUSDT": "65000.5", "BTCEUR": "60000"}}
+	provider := newTestBinance(t, server)
+
+	quotes, err := provider.SpotPrices(context.Background(), []Coin{bitcoin}, []string{"usd", "eur"})
+	if err != nil {
+		t.Fatalf("SpotPrices() error = %v", err)
+	}
+	if quote, _ := quotes.Get("bitcoin", "usd"); quote.Price != 65000.5 {
+		t.Errorf("Get(bitcoin, usd) = %+v, want 65000.5 from BTCUSDT", quote)
+	}
+	if quote, _ := quotes.Get("bitcoin", "eur"); quote.Price != 60000 {
+		t.Errorf("Get(bitcoin, eur) = %+v, want 60000", quote)
+	}
+	if requests := server.takeRequests(); len(requests) != 1 {
+		t.Errorf("requests = %q, want one batch", requests)
+	}
+}
+
+func TestBinanceSpotPricesRemembersUnlistedPairs(t *testing.T) {
+	server := &tickerServer{prices: map[string]string{"BTCUSDT": "65000"}}
+	provider := newTestBinance(t, server)
+	coins := []Coin{bitcoin, unlistedCoin}
+
+	// The first batch fails and the pairs are tried one by one
+	quotes, err := provider.SpotPrices(context.Background(), coins, []string{"usd"})
+	if err != nil {
+		t.Fatalf("SpotPrices() error = %v", err)
+	}
+	if quote, _ := quotes.Get("bitcoin", "usd"); quote.Price != 65000 {
+		t.Errorf("Get(bitcoin, usd) = %+v, want 65000", quote)
+	}
+	if _, ok := quotes.Get("unlisted", "usd"); ok {
+		t.Error("Get(unlisted, usd) found, want missing")
+	}
+	if requests := server.takeRequests(); len(requests) != 3 {
+		t.Errorf("first call requests = %q, want the batch and one per pair", requests)
+	}
+
+	// Later batches leave the unlisted pair out
+	for i := 0; i < 3; i++ {
+		if _, err := provider.SpotPrices(context.Background(), coins, []string{"usd"}); err != nil {
+			t.Fatalf("SpotPrices() error = %v", err)
+		}
+	}
+	if requests := server.takeRequests(); len(requests) != 3 || requests[0] != `["BTCUSDT"]` {
+		t.Errorf("later requests = %q, want one batch of BTCUSDT per call", requests)
+	}
+
+	// The pair is tried again after a while, in case it was listed since
+	provider.mu.Lock()
+	provider.unlisted["NOPEUSDT"] = time.Now().Add(-unlistedRetry)
+	provider.mu.Unlock()
+	server.prices["NOPEUSDT"] = "1"
+	quotes, err = provider.SpotPrices(context.Background(), coins, []string{"usd"})
+	if err != nil {
+		t.Fatalf("SpotPrices() error = %v", err)
+	}
+	if quote, _ := quotes.Get("unlisted", "usd"); quote.Price != 1 {
+		t.Errorf("Get(unlisted, usd) = %+v, want the new listing", quote)
+	}
+	if requests := server.takeRequests(); len(requests) != 1 {
+		t.Errorf("requests after the retry period = %q, want one batch", requests)
+	}
+}
+
+func TestBinanceSpotPricesNothingListed(t *testing.T) {
+	server := &tickerServer{}
+	provider := newTestBinance(t, server)
+
+	if _, err := provider.SpotPrices(context.Background(), []Coin{unlistedCoin}, []string{"usd"}); err == nil {
+		t.Fatal("SpotPrices() of an unlisted pair error = nil, want an error")
+	}
+	server.takeRequests()
+
+	_, err := provider.SpotPrices(context.Background(), []Coin{unlistedCoin}, []string{"usd"})
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("SpotPrices() error = %v, want ErrNotSupported", err)
+	}
+	if requests := server.takeRequests(); len(requests) != 0 {
+		t.Errorf("requests = %q, want none for a known unlisted pair", requests)
+	}
+}
+
+func TestBinanceSpotPricesDoesNotFanOutOnOtherErrors(t *testing.T) {
+	server := &tickerServer{prices: map[string]string{"BTCUSDT": "65000"}, status: http.StatusTooManyRequests}
+	provider := newTestBinance(t, server)
+
+	_, err := provider.SpotPrices(context.Background(), []Coin{bitcoin, unlistedCoin}, []string{"usd"})
+	if err == nil || !strings.Contains(err.Error(), "status 429: Too many requests.") {
+		t.Errorf("SpotPrices() error = %v, want the rate limit", err)
+	}
+	if requests := server.takeRequests(); len(requests) != 1 {
+		t.Errorf("requests = %q, want only the batch", requests)
+	}
+	if isInvalidSymbol(err) {
+		t.Error("rate limit taken for an unlisted pair")
+	}
+}
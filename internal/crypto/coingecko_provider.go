@@ -0,0 +1,161 @@
+package crypto
+
+import (
+	"blockmind/internal/coingecko"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CoinGeckoProvider is a MarketDataProvider backed by the CoinGecko API
+type CoinGeckoProvider struct {
+	client *coingecko.Client
+}
+
+// NewCoinGeckoProvider creates a provider using the given CoinGecko client
+func NewCoinGeckoProvider(client *coingecko.Client) *CoinGeckoProvider {
+	return &CoinGeckoProvider{client: client}
+}
+
+// Name returns the name of the provider
+func (p *CoinGeckoProvider) Name() string {
+	return "coingecko"
+}
+
+// SpotPrices fetches quotes for all coins and currencies in one request
+func (p *CoinGeckoProvider) SpotPrices(ctx context.Context, coins []Coin, vsCurrencies []string) (Quotes, error) {
+	ids := make([]string, len(coins))
+	for i, coin := range coins {
+		ids[i] = coin.ID
+	}
+
+	prices, err := p.client.SimplePrice(ctx, ids, vsCurrencies, coingecko.SimplePriceOptions{
+		IncludeMarketCap:     true,
+		Include24hrVol:       true,
+		Include24hrChange:    true,
+		IncludeLastUpdatedAt: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := make(Quotes)
+	for _, id := range ids {
+		for _, vs := range vsCurrencies {
+			if quote, ok := prices.Quote(id, vs); ok {
+				quotes.set(id, vs, Quote{
+					Price:         quote.Price,
+					MarketCap:     quote.MarketCap,
+					Volume24h:     quote.Volume24h,
+					Change24h:     quote.Change24h,
+					LastUpdatedAt: quote.LastUpdatedAt,
+				})
+			}
+		}
+	}
+	return quotes, nil
+}
+
+// MarketSnapshot fetches the market state of a coin from /coins/markets
+func (p *CoinGeckoProvider) MarketSnapshot(ctx context.Context, coin Coin, vsCurrency string) (*Snapshot, error) {
+	markets, err := p.client.Markets(ctx, vsCurrency, []string{coin.ID})
+	if err != nil {
+		return nil, err
+	}
+	if len(markets) == 0 {
+		return nil, fmt.Errorf("no data found for cryptocurrency: %s", coin.ID)
+	}
+
+	market := markets[0]
+	circulating := market.CirculatingSupply
+	return &Snapshot{
+		Coin:                  Coin{ID: market.ID, Symbol: market.Symbol, Name: market.Name},
+		Currency:              vsCurrency,
+		Price:                 market.CurrentPrice,
+		MarketCap:             market.MarketCap,
+		MarketCapRank:         market.MarketCapRank,
+		MarketCapChangePct24h: market.MarketCapChangePercentage24h,
+		FullyDilutedValuation: market.FullyDilutedValuation,
+		Volume24h:             market.TotalVolume,
+		High24h:               market.High24h,
+		Low24h:                market.Low24h,
+		PriceChange24h:        market.PriceChange24h,
+		PriceChangePct24h:     market.PriceChangePercentage24h,
+		CirculatingSupply:     &circulating,
+		TotalSupply:           market.TotalSupply,
+		MaxSupply:             market.MaxSupply,
+		ATH:                   market.ATH,
+		ATHChangePct:          market.ATHChangePercentage,
+		ATHDate:               parseTime(market.ATHDate),
+		ATL:                   market.ATL,
+		ATLChangePct:          market.ATLChangePercentage,
+		ATLDate:               parseTime(market.ATLDate),
+		LastUpdated:           parseTime(market.LastUpdated),
+	}, nil
+}
+
+// History fetches the price history of a coin from /coins/{id}/market_chart
+func (p *CoinGeckoProvider) History(ctx context.Context, coin Coin, vsCurrency string, days int) ([]PricePoint, error) {
+	chart, err := p.client.MarketChart(ctx, coin.ID, vsCurrency, strconv.Itoa(days))
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]PricePoint, len(chart.Prices))
+	for i, price := range chart.Prices {
+		points[i] = PricePoint{Time: price.Time, Price: price.Value}
+	}
+	return points, nil
+}
+
+// CoinInfo fetches metadata and sentiment of a coin from /coins/{id}
+func (p *CoinGeckoProvider) CoinInfo(ctx context.Context, coin Coin) (*CoinInfo, error) {
+	detail, err := p.client.Coin(ctx, coin.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &CoinInfo{
+		Coin:             Coin{ID: detail.ID, Symbol: detail.Symbol, Name: detail.Name},
+		Description:      strings.TrimSpace(detail.Description["en"]),
+		Categories:       detail.Categories,
+		GenesisDate:      detail.GenesisDate,
+		HashingAlgorithm: detail.HashingAlgorithm,
+		SentimentUpPct:   detail.SentimentVotesUpPercentage,
+		SentimentDownPct: detail.SentimentVotesDownPercentage,
+		PriceChangePct:   make(map[string]float64),
+	}
+	if len(detail.Links.Homepage) > 0 {
+		info.Homepage = detail.Links.Homepage[0]
+	}
+
+	if data := detail.MarketData; data != nil {
+		changes := map[string]*float64{
+			"24h":  data.PriceChangePercentage24h,
+			"7d":   data.PriceChangePercentage7d,
+			"14d":  data.PriceChangePercentage14d,
+			"30d":  data.PriceChangePercentage30d,
+			"60d":  data.PriceChangePercentage60d,
+			"200d": data.PriceChangePercentage200d,
+			"1y":   data.PriceChangePercentage1y,
+		}
+		for period, value := range changes {
+			if value != nil {
+				info.PriceChangePct[period] = *value
+			}
+		}
+	}
+
+	return info, nil
+}
+
+// parseTime parses an RFC 3339 timestamp, returning the zero time on failure
+func parseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -1,66 +1,169 @@
 package crypto
 
 import (
-	"blockmind/internal/config"
+	"blockmind/internal/i18n"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
 	"strings"
+	"time"
 )
 
-func GetCryptoPrice(crypto string, target string, cfg *config.Config) (string, error) {
-	// Get the price of a cryptocurrency
+// GetCryptoPrices returns the prices of the given coins in all target currencies
+// using a single batched request. A single coin/currency pair is rendered as one
+// line, anything larger as a table.
+func GetCryptoPrices(ctx context.Context, provider MarketDataProvider, coins []Coin, targets []string) (string, error) {
+	if len(coins) == 0 {
+		return "", fmt.Errorf("no coins requested")
+	}
 
-	// Create a context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.AITimeout)
-	defer cancel()
+	targets = vsCurrencies(targets)
 
-	// To lowercase
-	crypto = strings.ToLower(crypto)
-	target = strings.ToLower(target)
+	prices, err := provider.SpotPrices(ctx, coins, targets)
+	if err != nil {
+		return "", err
+	}
 
-	// Set Default target currency to USD
-	if target == "" {
-		target = "usd"
+	if len(coins) == 1 && len(targets) == 1 {
+		if quote, ok := prices.Get(coins[0].ID, targets[0]); ok {
+			return fmt.Sprintf("%s -> %s", coins[0].ID, FormatPrice(quote.Price, targets[0])), nil
+		}
+		return "", fmt.Errorf("price data not found for %s in %s", coins[0].ID, targets[0])
 	}
 
-	url := fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=%s&include_market_cap=false&include_24hr_vol=false&include_24hr_change=false&include_last_updated_at=false&precision=full", cfg.CoingeckoBaseURL, url.QueryEscape(crypto), url.QueryEscape(target))
+	return formatPriceTable(prices, coins, targets, i18n.FromContext(ctx))
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+// GetCryptoQuotes returns detailed quotes of the given coins in all target
+// currencies, including 24h change, volume, market cap and the data timestamp
+func GetCryptoQuotes(ctx context.Context, provider MarketDataProvider, coins []Coin, targets []string) (string, error) {
+	if len(coins) == 0 {
+		return "", fmt.Errorf("no coins requested")
 	}
 
-	// Setup headers
-	req.Header.Set("accept", "application/json")
-	req.Header.Set("x-cg-demo-api-key", cfg.CoingeckoAPIKey)
+	targets = vsCurrencies(targets)
 
-	// Send request
-	resp, err := http.DefaultClient.Do(req)
+	prices, err := provider.SpotPrices(ctx, coins, targets)
 	if err != nil {
-		return "", fmt.Errorf("API request failed: %w", err)
+		return "", err
+	}
+
+	var blocks []string
+	for _, coin := range coins {
+		var block strings.Builder
+		block.WriteString(fmt.Sprintf("*%s*\n", coin))
+
+		found := false
+		var updatedAt time.Time
+		for _, target := range targets {
+			quote, ok := prices.Get(coin.ID, target)
+			if !ok {
+				continue
+			}
+			found = true
+			updatedAt = quote.LastUpdatedAt
+
+			code := strings.ToUpper(target)
+			if len(targets) > 1 {
+				block.WriteString(fmt.Sprintf("_%s_\n", code))
+			}
+			block.WriteString(i18n.T(ctx, "quote.price", FormatPrice(quote.Price, target)) + "\n")
+			block.WriteString(i18n.T(ctx, "quote.change", FormatChange(quote.Change24h)) + "\n")
+			block.WriteString(i18n.T(ctx, "quote.volume", formatLargeNumber(quote.Volume24h), code) + "\n")
+			if quote.MarketCap > 0 {
+				block.WriteString(i18n.T(ctx, "quote.market_cap", formatLargeNumber(quote.MarketCap), code) + "\n")
+			} else {
+				block.WriteString(i18n.T(ctx, "quote.market_cap_na") + "\n")
+			}
+		}
+
+		if !found {
+			block.WriteString(i18n.T(ctx, "quote.no_data") + "\n")
+		} else if !updatedAt.IsZero() {
+			block.WriteString(i18n.T(ctx, "quote.updated", updatedAt.UTC().Format("2006-01-02 15:04 UTC")))
+		}
+
+		blocks = append(blocks, strings.TrimRight(block.String(), "\n"))
+	}
+
+	return strings.Join(blocks, "\n\n"), nil
+}
+
+// GetCryptoPriceChanges returns the prices and 24h changes of the given coins
+// in one currency as a table, using a single batched request
+func GetCryptoPriceChanges(ctx context.Context, provider MarketDataProvider, coins []Coin, target string) (string, error) {
+	if len(coins) == 0 {
+		return "", fmt.Errorf("no coins requested")
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	target = vsCurrencies([]string{target})[0]
+
+	prices, err := provider.SpotPrices(ctx, coins, []string{target})
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return "", err
+	}
+
+	na := i18n.T(ctx, "table.na")
+	rows := [][]string{{i18n.T(ctx, "table.coin"), strings.ToUpper(target), "24H"}}
+	found := false
+	for _, coin := range coins {
+		quote, ok := prices.Get(coin.ID, target)
+		if !ok {
+			rows = append(rows, []string{strings.ToUpper(coin.Symbol), na, na})
+			continue
+		}
+		found = true
+		rows = append(rows, []string{
+			strings.ToUpper(coin.Symbol),
+			formatNumber(quote.Price, priceDecimals(quote.Price, target)),
+			fmt.Sprintf("%+.2f%%", quote.Change24h),
+		})
+	}
+
+	if !found {
+		return "", fmt.Errorf("price data not found for %d coins in %s", len(coins), target)
 	}
 
-	// Parse JSON to extract the price
-	var priceData map[string]map[string]float64
-	if err := json.Unmarshal(body, &priceData); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+	return "```\n" + RenderTable(rows) + "```", nil
+}
+
+// vsCurrencies lowercases the target currencies, with USD as the default
+func vsCurrencies(targets []string) []string {
+	currencies := make([]string, 0, len(targets))
+	for _, target := range targets {
+		currencies = append(currencies, strings.ToLower(target))
+	}
+	if len(currencies) == 0 {
+		currencies = []string{"usd"}
+	}
+	return currencies
+}
+
+// formatPriceTable renders prices as a monospaced table with one row per coin
+// and one column per currency
+func formatPriceTable(prices Quotes, coins []Coin, targets []string, locale string) (string, error) {
+	header := []string{i18n.Translate(locale, "table.coin")}
+	for _, target := range targets {
+		header = append(header, strings.ToUpper(target))
 	}
 
-	if priceInfo, ok := priceData[crypto]; ok {
-		if price, ok := priceInfo[target]; ok {
-			return fmt.Sprintf("%s -> %.4f %s", crypto, price, strings.ToUpper(target)), nil
+	rows := [][]string{header}
+	found := false
+	for _, coin := range coins {
+		row := []string{strings.ToUpper(coin.Symbol)}
+		for _, target := range targets {
+			if quote, ok := prices.Get(coin.ID, target); ok {
+				row = append(row, formatNumber(quote.Price, priceDecimals(quote.Price, target)))
+				found = true
+			} else {
+				row = append(row, i18n.Translate(locale, "table.na"))
+			}
 		}
+		rows = append(rows, row)
+	}
+
+	if !found {
+		return "", fmt.Errorf("price data not found for %d coins in %s", len(coins), strings.Join(targets, ", "))
 	}
 
-	return "", fmt.Errorf("price data not found for %s in %s", crypto, target)
+	return "```\n" + RenderTable(rows) + "```", nil
 }
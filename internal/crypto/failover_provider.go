@@ -0,0 +1,86 @@
+package crypto
+
+import (
+	"blockmind/internal/logger"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FailoverProvider tries a chain of providers in order until one succeeds
+type FailoverProvider struct {
+	providers []MarketDataProvider
+}
+
+// NewFailoverProvider creates a provider that falls back to the next provider
+// in the chain whenever one fails
+func NewFailoverProvider(providers ...MarketDataProvider) *FailoverProvider {
+	return &FailoverProvider{providers: providers}
+}
+
+// Name returns the names of the chained providers
+func (p *FailoverProvider) Name() string {
+	names := make([]string, len(p.providers))
+	for i, provider := range p.providers {
+		names[i] = provider.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+// SpotPrices returns the quotes of the first provider that succeeds
+func (p *FailoverProvider) SpotPrices(ctx context.Context, coins []Coin, vsCurrencies []string) (Quotes, error) {
+	return failover(ctx, p.providers, "spot prices", func(provider MarketDataProvider) (Quotes, error) {
+		return provider.SpotPrices(ctx, coins, vsCurrencies)
+	})
+}
+
+// MarketSnapshot returns the snapshot of the first provider that succeeds
+func (p *FailoverProvider) MarketSnapshot(ctx context.Context, coin Coin, vsCurrency string) (*Snapshot, error) {
+	return failover(ctx, p.providers, "market snapshot", func(provider MarketDataProvider) (*Snapshot, error) {
+		return provider.MarketSnapshot(ctx, coin, vsCurrency)
+	})
+}
+
+// History returns the price history of the first provider that succeeds
+func (p *FailoverProvider) History(ctx context.Context, coin Coin, vsCurrency string, days int) ([]PricePoint, error) {
+	return failover(ctx, p.providers, "history", func(provider MarketDataProvider) ([]PricePoint, error) {
+		return provider.History(ctx, coin, vsCurrency, days)
+	})
+}
+
+// CoinInfo returns the coin info of the first provider that succeeds
+func (p *FailoverProvider) CoinInfo(ctx context.Context, coin Coin) (*CoinInfo, error) {
+	return failover(ctx, p.providers, "coin info", func(provider MarketDataProvider) (*CoinInfo, error) {
+		return provider.CoinInfo(ctx, coin)
+	})
+}
+
+// failover calls fn for each provider until one succeeds, joining all errors
+// when none does. It stops early once the context is done.
+func failover[T any](ctx context.Context, providers []MarketDataProvider, operation string, fn func(MarketDataProvider) (T, error)) (T, error) {
+	var zero T
+	var errs []error
+	for _, provider := range providers {
+		result, err := fn(provider)
+		if err == nil {
+			return result, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+
+		if ctx.Err() != nil {
+			break
+		}
+		if !errors.Is(err, ErrNotSupported) {
+			logger.Warn("Market data provider failed",
+				logger.Field{Key: "provider", Value: provider.Name()},
+				logger.Field{Key: "operation", Value: operation},
+				logger.Field{Key: "error", Value: err.Error()})
+		}
+	}
+
+	if len(errs) == 0 {
+		return zero, fmt.Errorf("%s: no market data providers configured", operation)
+	}
+	return zero, errors.Join(errs...)
+}
@@ -0,0 +1,136 @@
+package crypto
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeProvider answers every request with the same quotes or error and counts
+// how often it was asked
+type fakeProvider struct {
+	name   string
+	quotes Quotes
+	err    error
+	calls  int
+}
+
+func (p *fakeProvider) Name() string {
+	return p.name
+}
+
+func (p *fakeProvider) SpotPrices(ctx context.Context, coins []Coin, vsCurrencies []string) (Quotes, error) {
+	p.calls++
+	return p.quotes, p.err
+}
+
+func (p *fakeProvider) MarketSnapshot(ctx context.Context, coin Coin, vsCurrency string) (*Snapshot, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &Snapshot{Coin: coin, Currency: vsCurrency, Price: p.quotes[coin.ID][vsCurrency].Price}, nil
+}
+
+func (p *fakeProvider) History(ctx context.Context, coin Coin, vsCurrency string, days int) ([]PricePoint, error) {
+	p.calls++
+	return nil, p.err
+}
+
+func (p *fakeProvider) CoinInfo(ctx context.Context, coin Coin) (*CoinInfo, error) {
+	p.calls++
+	return nil, p.err
+}
+
+var bitcoin = Coin{ID: "bitcoin", Symbol: "btc", Name: "Bitcoin"}
+
+func bitcoinQuotes(price float64) Quotes {
+	quotes := Quotes{}
+	quotes.set("bitcoin", "usd", Quote{Price: price})
+	return quotes
+}
+
+func TestFailoverUsesSecondaryWhenPrimaryFails(t *testing.T) {
+	primary := &fakeProvider{name: "primary", err: errors.New("rate limited")}
+	secondary := &fakeProvider{name: "secondary", quotes: bitcoinQuotes(65000)}
+	provider := NewFailoverProvider(primary, secondary)
+
+	quotes, err := provider.SpotPrices(context.Background(), []Coin{bitcoin}, []string{"usd"})
+	if err != nil {
+		t.Fatalf("SpotPrices() error = %v", err)
+	}
+	if quote, ok := quotes.Get("bitcoin", "usd"); !ok || quote.Price != 65000 {
+		t.Errorf("SpotPrices() = %v, want the secondary's price 65000", quotes)
+	}
+	if primary.calls != 1 || secondary.calls != 1 {
+		t.Errorf("calls = %d, %d, want 1, 1", primary.calls, secondary.calls)
+	}
+
+	snapshot, err := provider.MarketSnapshot(context.Background(), bitcoin, "usd")
+	if err != nil {
+		t.Fatalf("MarketSnapshot() error = %v", err)
+	}
+	if snapshot.Price != 65000 {
+		t.Errorf("MarketSnapshot().Price = %v, want 65000", snapshot.Price)
+	}
+}
+
+func TestFailoverSkipsSecondaryWhenPrimaryAnswers(t *testing.T) {
+	primary := &fakeProvider{name: "primary", quotes: bitcoinQuotes(64000)}
+	secondary := &fakeProvider{name: "secondary", quotes: bitcoinQuotes(65000)}
+	provider := NewFailoverProvider(primary, secondary)
+
+	quotes, err := provider.SpotPrices(context.Background(), []Coin{bitcoin}, []string{"usd"})
+	if err != nil {
+		t.Fatalf("SpotPrices() error = %v", err)
+	}
+	if quote, _ := quotes.Get("bitcoin", "usd"); quote.Price != 64000 {
+		t.Errorf("SpotPrices() price = %v, want the primary's price 64000", quote.Price)
+	}
+	if secondary.calls != 0 {
+		t.Errorf("secondary called %d times, want 0", secondary.calls)
+	}
+}
+
+func TestFailoverReturnsErrorWhenAllFail(t *testing.T) {
+	errPrimary := errors.New("rate limited")
+	errSecondary := errors.New("connection refused")
+	provider := NewFailoverProvider(
+		&fakeProvider{name: "primary", err: errPrimary},
+		&fakeProvider{name: "secondary", err: errSecondary},
+	)
+
+	_, err := provider.SpotPrices(context.Background(), []Coin{bitcoin}, []string{"usd"})
+	if err == nil {
+		t.Fatal("SpotPrices() error = nil, want an error")
+	}
+	if !errors.Is(err, errPrimary) || !errors.Is(err, errSecondary) {
+		t.Errorf("SpotPrices() error = %v, want both providers' errors", err)
+	}
+
+	_, err = provider.History(context.Background(), bitcoin, "usd", 7)
+	if !errors.Is(err, errPrimary) || !errors.Is(err, errSecondary) {
+		t.Errorf("History() error = %v, want both providers' errors", err)
+	}
+}
+
+func TestFailoverStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	primary := &fakeProvider{name: "primary", err: context.Canceled}
+	secondary := &fakeProvider{name: "secondary", quotes: bitcoinQuotes(65000)}
+
+	_, err := NewFailoverProvider(primary, secondary).SpotPrices(ctx, []Coin{bitcoin}, []string{"usd"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("SpotPrices() error = %v, want context.Canceled", err)
+	}
+	if secondary.calls != 0 {
+		t.Errorf("secondary called %d times after cancellation, want 0", secondary.calls)
+	}
+}
+
+func TestFailoverWithoutProviders(t *testing.T) {
+	if _, err := NewFailoverProvider().CoinInfo(context.Background(), bitcoin); err == nil {
+		t.Error("CoinInfo() error = nil, want an error without providers")
+	}
+}
@@ -0,0 +1,137 @@
+package crypto
+
+import (
+	"blockmind/internal/i18n"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// cryptoCurrencies are vs currencies quoted by CoinGecko that are themselves
+// coins and therefore need more decimals than fiat currencies
+var cryptoCurrencies = map[string]bool{
+	"btc": true, "eth": true, "ltc": true, "bch": true, "bnb": true, "eos": true,
+	"xrp": true, "xlm": true, "link": true, "dot": true, "yfi": true, "sol": true,
+	"bits": true, "sats": true,
+}
+
+// zeroDecimalCurrencies are fiat currencies that are not quoted with cents
+var zeroDecimalCurrencies = map[string]bool{
+	"jpy": true, "krw": true, "vnd": true, "idr": true, "clp": true, "huf": true,
+}
+
+// FormatPrice formats a price with a precision suited to its magnitude and
+// currency, e.g. "65,000.00 USD", "0.00001234 USD" or "0.05230 BTC"
+func FormatPrice(value float64, currency string) string {
+	currency = strings.ToLower(currency)
+	return formatNumber(value, priceDecimals(value, currency)) + " " + strings.ToUpper(currency)
+}
+
+// priceDecimals returns the number of decimals used to display a price
+func priceDecimals(value float64, currency string) int {
+	abs := math.Abs(value)
+	switch {
+	case abs == 0:
+		return 2
+	case abs >= 1:
+		if cryptoCurrencies[currency] {
+			return 4
+		}
+		if zeroDecimalCurrencies[currency] && abs >= 100 {
+			return 0
+		}
+		return 2
+	}
+
+	// Keep four significant digits for sub-unit prices
+	decimals := 3 - int(math.Floor(math.Log10(abs)))
+	if decimals > 12 {
+		decimals = 12
+	}
+	return decimals
+}
+
+// formatNumber formats a number with the given decimals and thousands separators
+func formatNumber(value float64, decimals int) string {
+	formatted := fmt.Sprintf("%.*f", decimals, math.Abs(value))
+
+	integer, fraction := formatted, ""
+	if idx := strings.IndexByte(formatted, '.'); idx >= 0 {
+		integer, fraction = formatted[:idx], formatted[idx:]
+	}
+
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	sign := ""
+	if value < 0 {
+		sign = "-"
+	}
+	return sign + grouped.String() + fraction
+}
+
+// FormatChange formats a percentage change with a direction arrow
+func FormatChange(percentage float64) string {
+	switch {
+	case percentage > 0.005:
+		return fmt.Sprintf("▲ +%.2f%%", percentage)
+	case percentage < -0.005:
+		return fmt.Sprintf("▼ %.2f%%", percentage)
+	}
+	return fmt.Sprintf("▬ %.2f%%", math.Abs(percentage))
+}
+
+// SummarizeHistory describes a price history with its last price, change over
+// the period and the high and low reached
+func SummarizeHistory(history []PricePoint, currency, locale string) string {
+	if len(history) == 0 {
+		return ""
+	}
+
+	first, last := history[0].Price, history[len(history)-1].Price
+	high, low := first, first
+	for _, point := range history {
+		high = math.Max(high, point.Price)
+		low = math.Min(low, point.Price)
+	}
+
+	change := 0.0
+	if first != 0 {
+		change = (last - first) / first * 100
+	}
+
+	return i18n.Translate(locale, "chart.summary",
+		FormatPrice(last, currency), FormatChange(change),
+		FormatPrice(high, currency), FormatPrice(low, currency))
+}
+
+// RenderTable renders rows as a plain-text table for monospace blocks. The
+// first column is left-aligned and the others right-aligned.
+func RenderTable(rows [][]string) string {
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+
+	var table strings.Builder
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == 0 {
+				table.WriteString(fmt.Sprintf("%-*s", widths[i], cell))
+			} else {
+				table.WriteString(fmt.Sprintf("  %*s", widths[i], cell))
+			}
+		}
+		table.WriteString("\n")
+	}
+	return table.String()
+}
@@ -0,0 +1,99 @@
+package crypto
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotSupported is returned by providers that do not offer the requested data
+var ErrNotSupported = errors.New("not supported by market data provider")
+
+// MarketDataProvider is a source of market data for coins identified by their
+// canonical (CoinGecko) id and symbol
+type MarketDataProvider interface {
+	// Name returns a short identifier of the provider
+	Name() string
+	// SpotPrices returns the current quotes of the coins in every vs currency
+	SpotPrices(ctx context.Context, coins []Coin, vsCurrencies []string) (Quotes, error)
+	// MarketSnapshot returns the current market state of a coin
+	MarketSnapshot(ctx context.Context, coin Coin, vsCurrency string) (*Snapshot, error)
+	// History returns the price history of a coin over the last days
+	History(ctx context.Context, coin Coin, vsCurrency string, days int) ([]PricePoint, error)
+	// CoinInfo returns metadata, sentiment and long-term performance of a coin
+	CoinInfo(ctx context.Context, coin Coin) (*CoinInfo, error)
+}
+
+// Quote is the spot price of a coin in one currency. Fields other than Price
+// are zero when the provider does not report them.
+type Quote struct {
+	Price         float64
+	MarketCap     float64
+	Volume24h     float64
+	Change24h     float64
+	LastUpdatedAt time.Time
+}
+
+// Quotes maps coin ids to currency-keyed quotes
+type Quotes map[string]map[string]Quote
+
+// Get returns the quote of a coin in the given currency
+func (q Quotes) Get(id, vsCurrency string) (Quote, bool) {
+	quote, ok := q[id][vsCurrency]
+	return quote, ok
+}
+
+// set stores a quote, creating the inner map when needed
+func (q Quotes) set(id, vsCurrency string, quote Quote) {
+	if q[id] == nil {
+		q[id] = make(map[string]Quote)
+	}
+	q[id][vsCurrency] = quote
+}
+
+// Snapshot is the market state of a coin in one currency. Optional values the
+// provider does not report are nil or zero.
+type Snapshot struct {
+	Coin                  Coin
+	Currency              string
+	Price                 float64
+	MarketCap             float64
+	MarketCapRank         *int
+	MarketCapChangePct24h float64
+	FullyDilutedValuation *float64
+	Volume24h             float64
+	High24h               float64
+	Low24h                float64
+	PriceChange24h        float64
+	PriceChangePct24h     float64
+	CirculatingSupply     *float64
+	TotalSupply           *float64
+	MaxSupply             *float64
+	ATH                   float64
+	ATHChangePct          float64
+	ATHDate               time.Time
+	ATL                   float64
+	ATLChangePct          float64
+	ATLDate               time.Time
+	LastUpdated           time.Time
+}
+
+// PricePoint is a single price sample of a coin's history
+type PricePoint struct {
+	Time  time.Time
+	Price float64
+}
+
+// CoinInfo holds metadata and community sentiment of a coin. PriceChangePct is
+// keyed by period: "24h", "7d", "14d", "30d", "60d", "200d" and "1y".
+type CoinInfo struct {
+	Coin             Coin
+	Description      string
+	Homepage         string
+	Categories       []string
+	GenesisDate      string
+	HashingAlgorithm string
+	SentimentUpPct   *float64
+	SentimentDownPct *float64
+	PriceChangePct   map[string]float64
+}
@@ -1,174 +1,95 @@
 package crypto
 
 import (
-	"blockmind/internal/config"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
 	"strings"
 	"time"
 )
 
-func GetCryptoRecommendation(cryptoName string, cfg *config.Config) (string, error) {
-
-	// Create a context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.AITimeout)
-	defer cancel()
-
-	// To lowercase
-	cryptoName = strings.ToLower(cryptoName)
-
-	url := fmt.Sprintf("%s/coins/markets?vs_currency=usd&ids=%s&order=market_cap_desc&locale=en&precision=full", cfg.CoingeckoBaseURL, url.QueryEscape(cryptoName))
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Setup headers
-	req.Header.Set("accept", "application/json")
-	req.Header.Set("x-cg-demo-api-key", cfg.CoingeckoAPIKey)
-
-	// Send request
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("API request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+// GetCryptoRecommendation returns a formatted market overview of a cryptocurrency
+func GetCryptoRecommendation(ctx context.Context, provider MarketDataProvider, coin Coin) (string, error) {
+	data, err := provider.MarketSnapshot(ctx, coin, "usd")
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Parse JSON to extract the price
-	var marketData []map[string]interface{}
-	if err := json.Unmarshal(body, &marketData); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	// Check if we got any data
-	if len(marketData) == 0 {
-		return "", fmt.Errorf("no data found for cryptocurrency: %s", cryptoName)
+		return "", err
 	}
 
-	// Get the first item in the array
-	data := marketData[0]
-
 	// Format a nice response with relevant information
-	recommendation := fmt.Sprintf("*%s (%s)*\n\n",
-		data["name"],
-		strings.ToUpper(data["symbol"].(string)))
-
-	// Display all available data fields
-	for key, value := range data {
-		if value == nil {
-			recommendation += fmt.Sprintf("• %s: N/A\n", formatKeyName(key))
-			continue
-		}
-
-		switch v := value.(type) {
-		case float64:
-			// Format price-related fields with dollar signs and 2 decimal places
-			if strings.Contains(key, "percentage") || strings.Contains(key, "change_percentage") {
-				// Format percentage fields
-				recommendation += fmt.Sprintf("• %s: %.2f%%\n", formatKeyName(key), v)
-			} else if strings.Contains(key, "market_cap") || strings.Contains(key, "volume") || strings.Contains(key, "valuation") {
-				// Format large numbers with commas
-				recommendation += fmt.Sprintf("• %s: %s\n", formatKeyName(key), formatLargeNumber(v))
-			} else if strings.Contains(key, "price") || strings.Contains(key, "ath") || strings.Contains(key, "atl") ||
-				strings.Contains(key, "high") || strings.Contains(key, "low") {
-				recommendation += fmt.Sprintf("• %s: $%.2f\n", formatKeyName(key), v)
-			} else {
-				// Format other numbers normally
-				recommendation += fmt.Sprintf("• %s: %.0f\n", formatKeyName(key), v)
-			}
-		case string:
-			if strings.Contains(key, "date") {
-				// Format dates more nicely
-				recommendation += fmt.Sprintf("• %s: %s\n", formatKeyName(key), formatDate(v))
-			} else if strings.Contains(key, "image") {
-				// Skip image URLs to keep output cleaner
-				continue
-			} else {
-				recommendation += fmt.Sprintf("• %s: %s\n", formatKeyName(key), v)
-			}
-		default:
-			// Just convert to string for other types
-			recommendation += fmt.Sprintf("• %s: %v\n", formatKeyName(key), v)
+	var recommendation strings.Builder
+	recommendation.WriteString(fmt.Sprintf("*%s (%s)*\n\n", data.Coin.Name, strings.ToUpper(data.Coin.Symbol)))
+
+	// Providers leave values they do not report at zero or nil
+	writePrice := func(key string, value float64) {
+		if value == 0 {
+			recommendation.WriteString(fmt.Sprintf("• %s: N/A\n", formatKeyName(key)))
+			return
 		}
+		recommendation.WriteString(fmt.Sprintf("• %s: $%.2f\n", formatKeyName(key), value))
 	}
-
-	return recommendation, nil
-}
-
-func GetSentimentAndHistoricalData(data string, cryptoName string, cfg *config.Config) (string, error) {
-
-	// Create a context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.AITimeout)
-	defer cancel()
-
-	// To lowercase
-	cryptoName = strings.ToLower(cryptoName)
-
-	url := fmt.Sprintf("%s/coins/%s?localization=false&tickers=true&market_data=true&community_data=true&developer_data=false", cfg.CoingeckoBaseURL, url.QueryEscape(cryptoName))
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+	writePercentage := func(key string, value float64) {
+		recommendation.WriteString(fmt.Sprintf("• %s: %.2f%%\n", formatKeyName(key), value))
 	}
+	writeLarge := func(key string, value *float64) {
+		if value == nil || *value == 0 {
+			recommendation.WriteString(fmt.Sprintf("• %s: N/A\n", formatKeyName(key)))
+			return
+		}
+		recommendation.WriteString(fmt.Sprintf("• %s: %s\n", formatKeyName(key), formatLargeNumber(*value)))
+	}
+	writeTime := func(key string, value time.Time) {
+		recommendation.WriteString(fmt.Sprintf("• %s: %s\n", formatKeyName(key), formatTime(value)))
+	}
+
+	writePrice("current_price", data.Price)
+	if data.MarketCapRank != nil {
+		recommendation.WriteString(fmt.Sprintf("• %s: %d\n", formatKeyName("market_cap_rank"), *data.MarketCapRank))
+	}
+	writeLarge("market_cap", &data.MarketCap)
+	writePercentage("market_cap_change_percentage_24h", data.MarketCapChangePct24h)
+	writeLarge("fully_diluted_valuation", data.FullyDilutedValuation)
+	writeLarge("total_volume", &data.Volume24h)
+	writePrice("high_24h", data.High24h)
+	writePrice("low_24h", data.Low24h)
+	writePrice("price_change_24h", data.PriceChange24h)
+	writePercentage("price_change_percentage_24h", data.PriceChangePct24h)
+	writeLarge("circulating_supply", data.CirculatingSupply)
+	writeLarge("total_supply", data.TotalSupply)
+	writeLarge("max_supply", data.MaxSupply)
+	writePrice("ath", data.ATH)
+	writePercentage("ath_change_percentage", data.ATHChangePct)
+	writeTime("ath_date", data.ATHDate)
+	writePrice("atl", data.ATL)
+	writePercentage("atl_change_percentage", data.ATLChangePct)
+	writeTime("atl_date", data.ATLDate)
+	writeTime("last_updated", data.LastUpdated)
+
+	return recommendation.String(), nil
+}
 
-	// Setup headers
-	req.Header.Set("accept", "application/json")
-	req.Header.Set("x-cg-demo-api-key", cfg.CoingeckoAPIKey)
-
-	// Send request
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("API request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+// GetSentimentAndHistoricalData appends community sentiment and longer-term
+// price changes of a cryptocurrency to the given data
+func GetSentimentAndHistoricalData(ctx context.Context, provider MarketDataProvider, data string, coin Coin) (string, error) {
+	info, err := provider.CoinInfo(ctx, coin)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Parse JSON to extract the data - using a single map not an array
-	var coinData map[string]interface{}
-	if err := json.Unmarshal(body, &coinData); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+		return "", err
 	}
 
 	// Start with the existing data if provided
 	result := data
 
 	// Extract sentiment data
-	if sentimentUp, ok := coinData["sentiment_votes_up_percentage"].(float64); ok {
-		result += fmt.Sprintf("• %s: %.2f%%\n", formatKeyName("sentiment_votes_up_percentage"), sentimentUp)
+	if info.SentimentUpPct != nil {
+		result += fmt.Sprintf("• %s: %.2f%%\n", formatKeyName("sentiment_votes_up_percentage"), *info.SentimentUpPct)
 	}
 
-	if sentimentDown, ok := coinData["sentiment_votes_down_percentage"].(float64); ok {
-		result += fmt.Sprintf("• %s: %.2f%%\n", formatKeyName("sentiment_votes_down_percentage"), sentimentDown)
+	if info.SentimentDownPct != nil {
+		result += fmt.Sprintf("• %s: %.2f%%\n", formatKeyName("sentiment_votes_down_percentage"), *info.SentimentDownPct)
 	}
 
-	// Extract market data and price change percentages
-	if marketData, ok := coinData["market_data"].(map[string]interface{}); ok {
-		// Extract price change percentages
-		priceChangeKeys := []string{
-			"price_change_percentage_7d",
-			"price_change_percentage_14d",
-			"price_change_percentage_30d",
-			"price_change_percentage_60d",
-		}
-
-		for _, key := range priceChangeKeys {
-			if val, ok := marketData[key].(float64); ok {
-				result += fmt.Sprintf("• %s: %.2f%%\n", formatKeyName(key), val)
-			}
+	// Extract price change percentages
+	for _, period := range []string{"7d", "14d", "30d", "60d"} {
+		if value, ok := info.PriceChangePct[period]; ok {
+			result += fmt.Sprintf("• %s: %.2f%%\n", formatKeyName("price_change_percentage_"+period), value)
 		}
 	}
 
@@ -187,21 +108,19 @@ func formatKeyName(key string) string {
 	return strings.Join(words, " ")
 }
 
-// Helper function to format dates more nicely
-func formatDate(dateStr string) string {
-	if len(dateStr) > 19 {
-		dateStr = dateStr[:19]
-	}
-	t, err := time.Parse("2006-01-02T15:04:05", dateStr)
-	if err != nil {
-		return dateStr
+// Helper function to format timestamps more nicely
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "N/A"
 	}
-	return t.Format("Jan 02, 2006 15:04:05")
+	return t.UTC().Format("Jan 02, 2006 15:04:05")
 }
 
 // Helper function to format large numbers with commas
 func formatLargeNumber(num float64) string {
-	if num >= 1000000000 {
+	if num >= 1000000000000 {
+		return fmt.Sprintf("%.2f trillion", num/1000000000000)
+	} else if num >= 1000000000 {
 		return fmt.Sprintf("%.2f billion", num/1000000000)
 	} else if num >= 1000000 {
 		return fmt.Sprintf("%.2f million", num/1000000)
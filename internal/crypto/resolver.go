@@ -0,0 +1,404 @@
+package crypto
+
+import (
+	"blockmind/internal/coingecko"
+	"blockmind/internal/logger"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// coinListTTL is how long the coin list is used before it is refreshed
+	coinListTTL = 24 * time.Hour
+	// coinListRetry is how long to wait after a failed download of the coin
+	// list before trying again
+	coinListRetry = time.Minute
+	// maxCandidates limits the number of coins offered in a "did you mean" reply
+	maxCandidates = 5
+	// dominantRankFactor is how much better the best market cap rank of a
+	// symbol must be than the runner-up to resolve it without asking
+	dominantRankFactor = 10
+)
+
+// Coin identifies a coin by its canonical id, ticker symbol and display name
+type Coin struct {
+	ID            string `json:"id"`
+	Symbol        string `json:"symbol"`
+	Name          string `json:"name"`
+	MarketCapRank int    `json:"-"`
+}
+
+// String returns the coin formatted as "Name (SYMBOL)"
+func (c Coin) String() string {
+	return fmt.Sprintf("%s (%s)", c.Name, strings.ToUpper(c.Symbol))
+}
+
+// AmbiguousCoinError is returned when the input matches several coins
+type AmbiguousCoinError struct {
+	Query      string
+	Candidates []Coin
+}
+
+// Error implements the error interface
+func (e *AmbiguousCoinError) Error() string {
+	return fmt.Sprintf("%q matches %d coins", e.Query, len(e.Candidates))
+}
+
+// UnknownCoinError is returned when the input does not match any coin
+type UnknownCoinError struct {
+	Query       string
+	Suggestions []Coin
+}
+
+// Error implements the error interface
+func (e *UnknownCoinError) Error() string {
+	return fmt.Sprintf("unknown coin %q", e.Query)
+}
+
+// coinListCache is the on-disk representation of the coin list
+type coinListCache struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Coins     []Coin    `json:"coins"`
+}
+
+// Resolver maps symbols, names and ids typed by users to canonical coin ids
+type Resolver struct {
+	client    *coingecko.Client
+	cachePath string
+
+	mu       sync.RWMutex
+	loadedAt time.Time
+	byID     map[string]Coin
+	bySymbol map[string][]Coin
+	byName   map[string][]Coin
+
+	// The load in progress, shared by concurrent callers, and the outcome of
+	// the last download that failed
+	loading  *coinListLoad
+	failedAt time.Time
+	loadErr  error
+}
+
+// coinListLoad is a load of the coin list; done is closed when it finishes
+type coinListLoad struct {
+	done chan struct{}
+	err  error
+}
+
+// NewResolver creates a resolver backed by the CoinGecko coin list. The list is
+// cached at cachePath; an empty path disables the on-disk cache.
+func NewResolver(client *coingecko.Client, cachePath string) *Resolver {
+	return &Resolver{
+		client:    client,
+		cachePath: cachePath,
+	}
+}
+
+// Resolve maps the user input to a single coin
+func (r *Resolver) Resolve(ctx context.Context, query string) (Coin, error) {
+	normalized := normalizeQuery(query)
+	if normalized == "" {
+		return Coin{}, &UnknownCoinError{Query: query}
+	}
+
+	if err := r.ensureLoaded(ctx); err != nil {
+		// Without the coin list we can still try the search endpoint
+		logger.Warn("Coin list unavailable, falling back to search",
+			logger.Field{Key: "error", Value: err.Error()})
+	}
+
+	r.mu.RLock()
+	coin, byID := r.byID[strings.ReplaceAll(normalized, " ", "-")]
+	symbolMatches := r.bySymbol[strings.ReplaceAll(normalized, " ", "")]
+	nameMatches := r.byName[normalized]
+	r.mu.RUnlock()
+
+	if byID {
+		return coin, nil
+	}
+	if len(nameMatches) == 1 && len(symbolMatches) == 0 {
+		return nameMatches[0], nil
+	}
+
+	candidates := mergeCoins(symbolMatches, nameMatches)
+	switch len(candidates) {
+	case 0:
+		return r.resolveBySearch(ctx, query, normalized)
+	case 1:
+		return candidates[0], nil
+	}
+
+	// Several coins share the symbol or name: rank them by market cap
+	ranked, err := r.rankCandidates(ctx, normalized, candidates)
+	if err != nil {
+		logger.Warn("Failed to rank coin candidates",
+			logger.Field{Key: "query", Value: normalized},
+			logger.Field{Key: "error", Value: err.Error()})
+		ranked = candidates
+	}
+	if isDominant(ranked) {
+		return ranked[0], nil
+	}
+	if len(ranked) > maxCandidates {
+		ranked = ranked[:maxCandidates]
+	}
+	return Coin{}, &AmbiguousCoinError{Query: query, Candidates: ranked}
+}
+
+// Known reports whether the input matches a coin id, symbol or name in the
+// coin list, without falling back to the search endpoint
+func (r *Resolver) Known(ctx context.Context, query string) bool {
+	normalized := normalizeQuery(query)
+	if normalized == "" {
+		return false
+	}
+	if err := r.ensureLoaded(ctx); err != nil {
+		return false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, byID := r.byID[strings.ReplaceAll(normalized, " ", "-")]
+	return byID || len(r.bySymbol[strings.ReplaceAll(normalized, " ", "")]) > 0 || len(r.byName[normalized]) > 0
+}
+
+// Refresh forces a reload of the coin list from CoinGecko
+func (r *Resolver) Refresh(ctx context.Context) error {
+	coins, err := r.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	r.index(coins, time.Now())
+	return nil
+}
+
+// ensureLoaded loads the coin list from memory, disk or CoinGecko, in that
+// order. Concurrent callers share one load, and after a failed download the
+// list is not requested again for coinListRetry.
+func (r *Resolver) ensureLoaded(ctx context.Context) error {
+	r.mu.Lock()
+	if time.Since(r.loadedAt) < coinListTTL {
+		r.mu.Unlock()
+		return nil
+	}
+	if !r.failedAt.IsZero() && time.Since(r.failedAt) < coinListRetry {
+		err := r.loadErr
+		r.mu.Unlock()
+		return err
+	}
+	load := r.loading
+	if load == nil {
+		load = &coinListLoad{done: make(chan struct{})}
+		r.loading = load
+		// The shared load must not be cancelled by whichever caller started it
+		go r.load(context.WithoutCancel(ctx), load)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-load.done:
+		return load.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// load reads the coin list from disk or CoinGecko and records the outcome
+func (r *Resolver) load(ctx context.Context, load *coinListLoad) {
+	var fetchErr error
+	cached, cacheErr := r.readCache()
+	if cacheErr == nil && time.Since(cached.FetchedAt) < coinListTTL {
+		r.index(cached.Coins, cached.FetchedAt)
+	} else if coins, err := r.fetch(ctx); err == nil {
+		r.index(coins, time.Now())
+	} else {
+		fetchErr = err
+		// A stale list is better than none
+		if cacheErr == nil && len(cached.Coins) > 0 {
+			r.index(cached.Coins, cached.FetchedAt)
+		} else {
+			load.err = err
+		}
+	}
+
+	r.mu.Lock()
+	r.loading = nil
+	r.failedAt = time.Time{}
+	if fetchErr != nil {
+		r.failedAt = time.Now()
+		r.loadErr = load.err
+	}
+	r.mu.Unlock()
+	close(load.done)
+}
+
+// fetch downloads the coin list and persists it to the on-disk cache
+func (r *Resolver) fetch(ctx context.Context) ([]Coin, error) {
+	entries, err := r.client.CoinsList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch coin list: %w", err)
+	}
+
+	coins := make([]Coin, 0, len(entries))
+	for _, entry := range entries {
+		coins = append(coins, Coin{ID: entry.ID, Symbol: entry.Symbol, Name: entry.Name})
+	}
+
+	if err := r.writeCache(coinListCache{FetchedAt: time.Now(), Coins: coins}); err != nil {
+		logger.Warn("Failed to write coin list cache",
+			logger.Field{Key: "path", Value: r.cachePath},
+			logger.Field{Key: "error", Value: err.Error()})
+	}
+
+	return coins, nil
+}
+
+// index rebuilds the lookup maps from the given coin list
+func (r *Resolver) index(coins []Coin, loadedAt time.Time) {
+	byID := make(map[string]Coin, len(coins))
+	bySymbol := make(map[string][]Coin)
+	byName := make(map[string][]Coin)
+
+	for _, coin := range coins {
+		byID[coin.ID] = coin
+		symbol := strings.ToLower(coin.Symbol)
+		bySymbol[symbol] = append(bySymbol[symbol], coin)
+		name := normalizeQuery(coin.Name)
+		byName[name] = append(byName[name], coin)
+	}
+
+	r.mu.Lock()
+	r.byID = byID
+	r.bySymbol = bySymbol
+	r.byName = byName
+	r.loadedAt = loadedAt
+	r.mu.Unlock()
+}
+
+// rankCandidates orders candidates by market cap rank using the search endpoint.
+// Unranked coins are kept at the end in their original order.
+func (r *Resolver) rankCandidates(ctx context.Context, normalized string, candidates []Coin) ([]Coin, error) {
+	result, err := r.client.Search(ctx, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank coins for %q: %w", normalized, err)
+	}
+
+	ranks := make(map[string]int, len(result.Coins))
+	for _, coin := range result.Coins {
+		if coin.MarketCapRank != nil {
+			ranks[coin.ID] = *coin.MarketCapRank
+		}
+	}
+
+	ranked := make([]Coin, len(candidates))
+	for i, coin := range candidates {
+		coin.MarketCapRank = ranks[coin.ID]
+		ranked[i] = coin
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i].MarketCapRank, ranked[j].MarketCapRank
+		if a == 0 || b == 0 {
+			return a != 0
+		}
+		return a < b
+	})
+
+	return ranked, nil
+}
+
+// resolveBySearch uses the search endpoint for inputs that are not in the coin list
+func (r *Resolver) resolveBySearch(ctx context.Context, query, normalized string) (Coin, error) {
+	result, err := r.client.Search(ctx, normalized)
+	if err != nil {
+		return Coin{}, fmt.Errorf("failed to search for %q: %w", normalized, err)
+	}
+
+	var suggestions []Coin
+	for _, found := range result.Coins {
+		coin := Coin{ID: found.ID, Symbol: found.Symbol, Name: found.Name}
+		if found.MarketCapRank != nil {
+			coin.MarketCapRank = *found.MarketCapRank
+		}
+
+		if coin.ID == normalized || strings.EqualFold(coin.Symbol, normalized) || normalizeQuery(coin.Name) == normalized {
+			return coin, nil
+		}
+		if len(suggestions) < maxCandidates {
+			suggestions = append(suggestions, coin)
+		}
+	}
+
+	return Coin{}, &UnknownCoinError{Query: query, Suggestions: suggestions}
+}
+
+func (r *Resolver) readCache() (coinListCache, error) {
+	var cached coinListCache
+	if r.cachePath == "" {
+		return cached, os.ErrNotExist
+	}
+
+	data, err := os.ReadFile(r.cachePath)
+	if err != nil {
+		return cached, err
+	}
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return cached, fmt.Errorf("invalid coin list cache: %w", err)
+	}
+	return cached, nil
+}
+
+func (r *Resolver) writeCache(cached coinListCache) error {
+	if r.cachePath == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated cache
+	tmp := r.cachePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.cachePath)
+}
+
+// isDominant reports whether the first ranked coin clearly outranks the rest
+func isDominant(ranked []Coin) bool {
+	if len(ranked) == 0 || ranked[0].MarketCapRank == 0 {
+		return false
+	}
+	if len(ranked) == 1 || ranked[1].MarketCapRank == 0 {
+		return true
+	}
+	return ranked[1].MarketCapRank >= ranked[0].MarketCapRank*dominantRankFactor
+}
+
+// mergeCoins concatenates coin lists without duplicates, preserving order
+func mergeCoins(lists ...[]Coin) []Coin {
+	seen := make(map[string]bool)
+	var merged []Coin
+	for _, list := range lists {
+		for _, coin := range list {
+			if !seen[coin.ID] {
+				seen[coin.ID] = true
+				merged = append(merged, coin)
+			}
+		}
+	}
+	return merged
+}
+
+// normalizeQuery lowercases the input and collapses whitespace
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
@@ -0,0 +1,280 @@
+package crypto
+
+import (
+	"blockmind/internal/coingecko"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testCoins is the coin list served by coinListServer. Several coins share
+// the btc, eth and sol symbols.
+const testCoins = `[
+	{"id": "bitcoin", "symbol": "btc", "name": "Bitcoin"},
+	{"id": "batcat", "symbol": "btc", "name": "Batcat"},
+	{"id": "bitcoin-cash", "symbol": "bch", "name": "Bitcoin Cash"},
+	{"id": "ethereum", "symbol": "eth", "name": "Ethereum"},
+	{"id": "ethereum-wormhole", "symbol": "eth", "name": "Ethereum (Wormhole)"},
+	{"id": "shiba-inu", "symbol": "shib", "name": "Shiba Inu"},
+	{"id": "solana", "symbol": "sol", "name": "Solana"},
+	{"id": "wrapped-solana", "symbol": "sol", "name": "Wrapped SOL"},
+	{"id": "solana-fork", "symbol": "sol", "name": "Solana Fork"}
+]`
+
+// testSearches are the /search results by query, with market cap ranks
+var testSearches = map[string]string{
+	"btc": `{"coins": [{"id": "bitcoin", "symbol": "btc", "name": "Bitcoin", "market_cap_rank": 1},
+		{"id": "batcat", "symbol": "btc", "name": "Batcat", "market_cap_rank": 4500}]}`,
+	"eth": `{"coins": [{"id": "ethereum", "symbol": "eth", "name": "Ethereum", "market_cap_rank": 2},
+		{"id": "ethereum-wormhole", "symbol": "eth", "name": "Ethereum (Wormhole)", "market_cap_rank": null}]}`,
+	"sol": `{"coins": [{"id": "wrapped-solana", "symbol": "sol", "name": "Wrapped SOL", "market_cap_rank": 30},
+		{"id": "solana", "symbol": "sol", "name": "Solana", "market_cap_rank": 5}]}`,
+	"pepe": `{"coins": [{"id": "pepe", "symbol": "pepe", "name": "Pepe", "market_cap_rank": 25}]}`,
+	"pep": `{"coins": [{"id": "pepe", "symbol": "pepe", "name": "Pepe", "market_cap_rank": 25},
+		{"id": "pepecoin", "symbol": "pepecoin", "name": "PepeCoin", "market_cap_rank": null}]}`,
+}
+
+// coinListServer is a test CoinGecko API serving testCoins and testSearches.
+// It counts the coin list downloads, fails them with listStatus, and waits
+// for release before answering them when it is set.
+type coinListServer struct {
+	listHits   atomic.Int32
+	listStatus atomic.Int32
+	release    chan struct{}
+}
+
+func (s *coinListServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/coins/list":
+		s.listHits.Add(1)
+		if s.release != nil {
+			<-s.release
+		}
+		if status := int(s.listStatus.Load()); status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(testCoins))
+	case "/search":
+		result, ok := testSearches[r.URL.Query().Get("query")]
+		if !ok {
+			result = `{"coins": []}`
+		}
+		w.Write([]byte(result))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// newTestResolver returns a resolver talking to server and caching the coin
+// list at cachePath
+func newTestResolver(t *testing.T, server *coinListServer, cachePath string) *Resolver {
+	t.Helper()
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	return NewResolver(coingecko.NewClient(httpServer.URL, "", httpServer.Client()), cachePath)
+}
+
+// writeCoinListCache stores coins fetched at fetchedAt as the cache at path
+func writeCoinListCache(t *testing.T, path string, fetchedAt time.Time, coins ...Coin) {
+	t.Helper()
+	data, err := json.Marshal(coinListCache{FetchedAt: fetchedAt, Coins: coins})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	resolver := newTestResolver(t, &coinListServer{}, "")
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"bitcoin", "bitcoin"},
+		{"BTC", "bitcoin"},
+		{"eth", "ethereum"},
+		{"batcat", "batcat"},
+		{"Bitcoin Cash", "bitcoin-cash"},
+		{"bitcoin  cash", "bitcoin-cash"},
+		{"shiba inu", "shiba-inu"},
+		{"SHIBA-INU", "shiba-inu"},
+		{"ethereum (wormhole)", "ethereum-wormhole"},
+		// Not in the coin list, found by search
+		{"pepe", "pepe"},
+	}
+	for _, tt := range tests {
+		coin, err := resolver.Resolve(context.Background(), tt.query)
+		if err != nil {
+			t.Errorf("Resolve(%q) error = %v", tt.query, err)
+			continue
+		}
+		if coin.ID != tt.want {
+			t.Errorf("Resolve(%q) = %s, want %s", tt.query, coin.ID, tt.want)
+		}
+	}
+}
+
+func TestResolveAmbiguousSymbol(t *testing.T) {
+	resolver := newTestResolver(t, &coinListServer{}, "")
+
+	_, err := resolver.Resolve(context.Background(), "sol")
+	var ambiguous *AmbiguousCoinError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Resolve(sol) error = %v, want *AmbiguousCoinError", err)
+	}
+
+	// Ranked by market cap, unranked coins last
+	var ids []string
+	for _, coin := range ambiguous.Candidates {
+		ids = append(ids, coin.ID)
+	}
+	if want := []string{"solana", "wrapped-solana", "solana-fork"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Candidates = %v, want %v", ids, want)
+	}
+	if ambiguous.Query != "sol" || ambiguous.Candidates[0].MarketCapRank != 5 {
+		t.Errorf("AmbiguousCoinError = %+v", ambiguous)
+	}
+}
+
+func TestResolveUnknownCoin(t *testing.T) {
+	resolver := newTestResolver(t, &coinListServer{}, "")
+
+	for query, want := range map[string][]string{
+		"pep":         {"pepe", "pepecoin"},
+		"nothingcoin": nil,
+		" ":           nil,
+	} {
+		_, err := resolver.Resolve(context.Background(), query)
+		var unknown *UnknownCoinError
+		if !errors.As(err, &unknown) {
+			t.Errorf("Resolve(%q) error = %v, want *UnknownCoinError", query, err)
+			continue
+		}
+		var ids []string
+		for _, coin := range unknown.Suggestions {
+			ids = append(ids, coin.ID)
+		}
+		if !reflect.DeepEqual(ids, want) {
+			t.Errorf("Resolve(%q) suggestions = %v, want %v", query, ids, want)
+		}
+	}
+}
+
+func TestResolverUsesDiskCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "coins.json")
+	server := &coinListServer{}
+
+	// A fresh cache is used without downloading the list
+	writeCoinListCache(t, path, time.Now().Add(-time.Hour), Coin{ID: "cached-coin", Symbol: "cc", Name: "Cached Coin"})
+	resolver := newTestResolver(t, server, path)
+	if coin, err := resolver.Resolve(context.Background(), "cc"); err != nil || coin.ID != "cached-coin" {
+		t.Errorf("Resolve(cc) = %v, %v, want the cached coin", coin, err)
+	}
+	if hits := server.listHits.Load(); hits != 0 {
+		t.Errorf("coin list downloaded %d times, want 0 with a fresh cache", hits)
+	}
+
+	// An expired cache is replaced by a download, which is written back
+	writeCoinListCache(t, path, time.Now().Add(-2*coinListTTL), Coin{ID: "cached-coin", Symbol: "cc", Name: "Cached Coin"})
+	resolver = newTestResolver(t, server, path)
+	if !resolver.Known(context.Background(), "shiba inu") || resolver.Known(context.Background(), "cc") {
+		t.Error("Known() answers from the expired cache, want the downloaded list")
+	}
+	cached, err := resolver.readCache()
+	if err != nil || len(cached.Coins) != 9 || time.Since(cached.FetchedAt) > time.Minute {
+		t.Errorf("cache after download = %d coins fetched at %v, %v, want the new list", len(cached.Coins), cached.FetchedAt, err)
+	}
+}
+
+func TestResolverFallsBackToStaleDiskCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "coins.json")
+	writeCoinListCache(t, path, time.Now().Add(-2*coinListTTL), Coin{ID: "ethereum", Symbol: "eth", Name: "Ethereum"})
+	server := &coinListServer{}
+	server.listStatus.Store(http.StatusServiceUnavailable)
+	resolver := newTestResolver(t, server, path)
+
+	coin, err := resolver.Resolve(context.Background(), "eth")
+	if err != nil || coin.ID != "ethereum" {
+		t.Errorf("Resolve(eth) = %v, %v, want ethereum from the stale cache", coin, err)
+	}
+	if hits := server.listHits.Load(); hits != 1 {
+		t.Errorf("coin list downloaded %d times, want 1", hits)
+	}
+}
+
+func TestResolverSharesCoinListLoad(t *testing.T) {
+	server := &coinListServer{release: make(chan struct{})}
+	resolver := newTestResolver(t, server, "")
+
+	const callers = 10
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !resolver.Known(context.Background(), "bitcoin") {
+				t.Error("Known(bitcoin) = false after the shared load")
+			}
+		}()
+	}
+
+	// A caller that gives up does not cancel the load the others wait for
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if resolver.Known(ctx, "bitcoin") {
+		t.Error("Known() with a cancelled context = true, want false")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(server.release)
+	wg.Wait()
+	if hits := server.listHits.Load(); hits != 1 {
+		t.Errorf("coin list downloaded %d times for %d callers, want 1", hits, callers)
+	}
+}
+
+func TestResolverWaitsBeforeRetryingFailedLoad(t *testing.T) {
+	server := &coinListServer{}
+	server.listStatus.Store(http.StatusInternalServerError)
+	resolver := newTestResolver(t, server, "")
+
+	for i := 0; i < 3; i++ {
+		if resolver.Known(context.Background(), "bitcoin") {
+			t.Fatal("Known(bitcoin) = true without a coin list")
+		}
+	}
+	if hits := server.listHits.Load(); hits != 1 {
+		t.Errorf("coin list downloaded %d times, want 1 within the retry delay", hits)
+	}
+
+	// Without the list, the search endpoint still resolves coins
+	if coin, err := resolver.Resolve(context.Background(), "pepe"); err != nil || coin.ID != "pepe" {
+		t.Errorf("Resolve(pepe) = %v, %v, want pepe from search", coin, err)
+	}
+
+	// After the delay the list is downloaded again
+	server.listStatus.Store(0)
+	resolver.mu.Lock()
+	resolver.failedAt = resolver.failedAt.Add(-coinListRetry)
+	resolver.mu.Unlock()
+	if !resolver.Known(context.Background(), "bitcoin") {
+		t.Error("Known(bitcoin) = false after the retry delay")
+	}
+	if hits := server.listHits.Load(); hits != 2 {
+		t.Errorf("coin list downloaded %d times, want 2", hits)
+	}
+}
@@ -0,0 +1,79 @@
+package crypto
+
+import (
+	"blockmind/internal/indicators"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// technicalHistoryDays is the history fetched for indicators; more than 90 days
+// makes CoinGecko return one price per day
+const technicalHistoryDays = 200
+
+// GetTechnicalIndicators appends RSI, MACD, moving averages, Bollinger Bands and
+// volatility computed from the daily price history of a coin to the given data.
+// Indicators that need more history than is available are skipped.
+func GetTechnicalIndicators(ctx context.Context, provider MarketDataProvider, data string, coin Coin) (string, error) {
+	history, err := provider.History(ctx, coin, "usd", technicalHistoryDays)
+	if err != nil {
+		return "", err
+	}
+
+	prices := make([]float64, len(history))
+	for i, point := range history {
+		prices[i] = point.Price
+	}
+
+	var lines []string
+	add := func(err error, line string) error {
+		if err != nil {
+			if errors.Is(err, indicators.ErrInsufficientData) {
+				return nil
+			}
+			return err
+		}
+		lines = append(lines, line)
+		return nil
+	}
+
+	rsi, err := indicators.RSI(prices, 14)
+	if err := add(err, fmt.Sprintf("• RSI (14d): %.2f", rsi)); err != nil {
+		return "", err
+	}
+
+	macd, err := indicators.MACD(prices, 12, 26, 9)
+	if err := add(err, fmt.Sprintf("• MACD (12, 26, 9): %.4f, Signal: %.4f, Histogram: %.4f", macd.MACD, macd.Signal, macd.Histogram)); err != nil {
+		return "", err
+	}
+
+	for _, period := range []int{20, 50, 200} {
+		sma, err := indicators.SMA(prices, period)
+		if err := add(err, fmt.Sprintf("• SMA (%dd): $%.2f", period, sma)); err != nil {
+			return "", err
+		}
+	}
+
+	ema, err := indicators.EMA(prices, 20)
+	if err := add(err, fmt.Sprintf("• EMA (20d): $%.2f", ema)); err != nil {
+		return "", err
+	}
+
+	bands, err := indicators.Bollinger(prices, 20, 2)
+	if err := add(err, fmt.Sprintf("• Bollinger Bands (20d, 2σ): upper $%.2f, middle $%.2f, lower $%.2f", bands.Upper, bands.Middle, bands.Lower)); err != nil {
+		return "", err
+	}
+
+	if len(prices) > 30 {
+		volatility, err := indicators.Volatility(prices[len(prices)-31:], 365)
+		if err := add(err, fmt.Sprintf("• Volatility (30d, annualized): %.2f%%", volatility*100)); err != nil {
+			return "", err
+		}
+	}
+
+	if len(lines) == 0 {
+		return data, nil
+	}
+	return data + "*Technical Indicators (computed from daily closes)*\n" + strings.Join(lines, "\n") + "\n", nil
+}
@@ -0,0 +1,70 @@
+package digest
+
+import (
+	"blockmind/internal/crypto"
+	"blockmind/internal/i18n"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxMovers is the number of top gainers and losers listed in a digest
+const maxMovers = 2
+
+// entry is a coin together with its current quote
+type entry struct {
+	coin  crypto.Coin
+	quote crypto.Quote
+}
+
+// Build renders the market digest of coins from their quotes in currency,
+// with its labels in locale
+func Build(coins []crypto.Coin, quotes crypto.Quotes, currency, locale string) (string, error) {
+	var entries []entry
+	for _, coin := range coins {
+		if quote, ok := quotes.Get(coin.ID, currency); ok {
+			entries = append(entries, entry{coin: coin, quote: quote})
+		}
+	}
+	if len(entries) == 0 {
+		return "", fmt.Errorf("no prices available for the digest")
+	}
+
+	var digest strings.Builder
+	digest.WriteString(i18n.Translate(locale, "digest.title") + "\n\n")
+	for _, e := range entries {
+		digest.WriteString(fmt.Sprintf("*%s*: %s (%s)\n", strings.ToUpper(e.coin.Symbol),
+			crypto.FormatPrice(e.quote.Price, currency), crypto.FormatChange(e.quote.Change24h)))
+	}
+
+	// Movers only make sense when there is something to compare
+	if len(entries) > 2 {
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].quote.Change24h > entries[j].quote.Change24h
+		})
+
+		movers := maxMovers
+		if len(entries) < 2*movers {
+			movers = len(entries) / 2
+		}
+
+		losers := make([]entry, 0, movers)
+		for i := len(entries) - 1; i >= len(entries)-movers; i-- {
+			losers = append(losers, entries[i])
+		}
+
+		digest.WriteString("\n" + i18n.Translate(locale, "digest.gainers") + " " + formatMovers(entries[:movers]))
+		digest.WriteString("\n" + i18n.Translate(locale, "digest.losers") + " " + formatMovers(losers))
+	}
+
+	return strings.TrimRight(digest.String(), "\n"), nil
+}
+
+// formatMovers lists coins with their 24h change, e.g. "SOL +5.20%, ETH +1.10%"
+func formatMovers(entries []entry) string {
+	parts := make([]string, len(entries))
+	for i, e := range entries {
+		parts[i] = fmt.Sprintf("%s %+.2f%%", strings.ToUpper(e.coin.Symbol), e.quote.Change24h)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,41 @@
+package digest
+
+import (
+	"blockmind/internal/i18n"
+	"blockmind/internal/storage"
+	"fmt"
+	"time"
+)
+
+// NextRun returns the first time strictly after after at which the
+// subscription is due, in the subscription's timezone. Days on which the
+// local time does not exist because of a DST change run at the normalized time.
+func NextRun(sub storage.Subscription, after time.Time) (time.Time, error) {
+	loc, err := time.LoadLocation(sub.Timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timezone %q: %w", sub.Timezone, err)
+	}
+
+	local := after.In(loc)
+	for days := 0; days <= 7; days++ {
+		candidate := time.Date(local.Year(), local.Month(), local.Day()+days, sub.Minute/60, sub.Minute%60, 0, 0, loc)
+		if !candidate.After(after) {
+			continue
+		}
+		if sub.Frequency == storage.FrequencyWeekly && candidate.Weekday() != sub.Weekday {
+			continue
+		}
+		return candidate, nil
+	}
+	return time.Time{}, fmt.Errorf("no run found for subscription %d", sub.ID)
+}
+
+// FormatSchedule describes when a subscription runs in locale, e.g. "daily
+// at 08:00 (Europe/Madrid)"
+func FormatSchedule(sub storage.Subscription, locale string) string {
+	at := fmt.Sprintf("%02d:%02d (%s)", sub.Minute/60, sub.Minute%60, sub.Timezone)
+	if sub.Frequency == storage.FrequencyWeekly {
+		return i18n.Translate(locale, "schedule.weekly", i18n.Weekday(locale, sub.Weekday), at)
+	}
+	return i18n.Translate(locale, "schedule.daily", at)
+}
@@ -0,0 +1,64 @@
+package digest
+
+import (
+	"blockmind/internal/storage"
+	"testing"
+	"time"
+)
+
+// utc returns the time of day on a date in UTC
+func utc(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestNextRun(t *testing.T) {
+	daily := func(timezone string, hour, minute int) storage.Subscription {
+		return storage.Subscription{Frequency: storage.FrequencyDaily, Minute: hour*60 + minute, Timezone: timezone}
+	}
+	weekly := func(weekday time.Weekday, hour int) storage.Subscription {
+		return storage.Subscription{Frequency: storage.FrequencyWeekly, Weekday: weekday, Minute: hour * 60, Timezone: "UTC"}
+	}
+
+	tests := []struct {
+		name  string
+		sub   storage.Subscription
+		after time.Time
+		want  time.Time
+	}{
+		{"later today", daily("Europe/Madrid", 8, 0), utc(2026, 10, 16, 5, 0), utc(2026, 10, 16, 6, 0)},
+		{"due now runs tomorrow", daily("Europe/Madrid", 8, 0), utc(2026, 10, 16, 6, 0), utc(2026, 10, 17, 6, 0)},
+		{"past today", daily("Europe/Madrid", 8, 0), utc(2026, 10, 16, 7, 0), utc(2026, 10, 17, 6, 0)},
+		{"after the clocks go back", daily("Europe/Madrid", 8, 0), utc(2026, 10, 24, 7, 0), utc(2026, 10, 25, 7, 0)},
+		{"after the clocks go forward", daily("Europe/Madrid", 8, 0), utc(2026, 3, 28, 8, 0), utc(2026, 3, 29, 6, 0)},
+		// 02:30 does not exist on that day in Madrid
+		{"skipped local time", daily("Europe/Madrid", 2, 30), utc(2026, 3, 28, 12, 0), utc(2026, 3, 29, 1, 30)},
+		{"local date behind UTC", daily("America/New_York", 23, 30), utc(2026, 10, 16, 3, 0), utc(2026, 10, 16, 3, 30)},
+		{"weekly later this week", weekly(time.Monday, 9), utc(2026, 10, 16, 12, 0), utc(2026, 10, 19, 9, 0)},
+		{"weekly later today", weekly(time.Monday, 9), utc(2026, 10, 19, 8, 0), utc(2026, 10, 19, 9, 0)},
+		{"weekly due now runs next week", weekly(time.Monday, 9), utc(2026, 10, 19, 9, 0), utc(2026, 10, 26, 9, 0)},
+	}
+	for _, tt := range tests {
+		got, err := NextRun(tt.sub, tt.after)
+		if err != nil {
+			t.Errorf("%s: NextRun() error = %v", tt.name, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: NextRun(%v) = %v, want %v", tt.name, tt.after, got.UTC(), tt.want)
+		}
+		if got.Location().String() != tt.sub.Timezone {
+			t.Errorf("%s: NextRun() in %v, want %s", tt.name, got.Location(), tt.sub.Timezone)
+		}
+	}
+
+	if _, err := NextRun(daily("Mars/Olympus_Mons", 8, 0), time.Now()); err == nil {
+		t.Error("NextRun() with an unknown timezone error = nil, want an error")
+	}
+}
+
+func TestFormatSchedule(t *testing.T) {
+	sub := storage.Subscription{Frequency: storage.FrequencyDaily, Minute: 8*60 + 5, Timezone: "Europe/Madrid"}
+	if got := FormatSchedule(sub, "en"); got != "daily at 08:05 (Europe/Madrid)" {
+		t.Errorf("FormatSchedule() = %q", got)
+	}
+}
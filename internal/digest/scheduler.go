@@ -0,0 +1,139 @@
+package digest
+
+import (
+	"blockmind/internal/crypto"
+	"blockmind/internal/i18n"
+	"blockmind/internal/logger"
+	"blockmind/internal/storage"
+	"context"
+	"fmt"
+	"time"
+)
+
+// CheckInterval is how often the scheduler looks for due subscriptions
+const CheckInterval = 30 * time.Second
+
+// maxLateness is how late a digest may still be delivered, e.g. after a
+// restart. Older runs are skipped instead of flooding chats with stale digests.
+const maxLateness = 2 * time.Hour
+
+// Notifier sends proactive messages to a chat
+type Notifier interface {
+	Notify(ctx context.Context, jid string, text string) error
+}
+
+// Commentator writes a short commentary on a digest. It is optional.
+type Commentator func(ctx context.Context, digest string) (string, error)
+
+// Scheduler delivers due digest subscriptions
+type Scheduler struct {
+	store       *storage.Store
+	provider    crypto.MarketDataProvider
+	notifier    Notifier
+	commentator Commentator
+	interval    time.Duration
+}
+
+// NewScheduler creates a new digest scheduler checking for due subscriptions
+// every interval. Commentator may be nil to send digests without commentary.
+func NewScheduler(store *storage.Store, provider crypto.MarketDataProvider, notifier Notifier,
+	commentator Commentator, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		store:       store,
+		provider:    provider,
+		notifier:    notifier,
+		commentator: commentator,
+		interval:    interval,
+	}
+}
+
+// Run delivers due digests every interval until the context is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	logger.Info("Digest scheduler started", logger.Field{Key: "interval", Value: s.interval.String()})
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Digest scheduler stopped")
+			return
+		case <-ticker.C:
+			if err := s.Check(ctx, time.Now()); err != nil {
+				logger.Error("Failed to deliver digests", err)
+			}
+		}
+	}
+}
+
+// Check delivers every subscription due at now and schedules its next run
+func (s *Scheduler) Check(ctx context.Context, now time.Time) error {
+	due, err := s.store.DueSubscriptions(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range due {
+		if now.Sub(sub.NextRunAt) <= maxLateness {
+			if err := s.deliver(ctx, sub); err != nil {
+				logger.Error("Failed to deliver digest", err,
+					logger.Field{Key: "subscription_id", Value: sub.ID})
+			}
+		} else {
+			logger.Warn("Skipping late digest",
+				logger.Field{Key: "subscription_id", Value: sub.ID},
+				logger.Field{Key: "due_at", Value: sub.NextRunAt.String()})
+		}
+
+		// Schedule the next run even after a failure so one bad chat does not retry every tick
+		next, err := NextRun(sub, now)
+		if err != nil {
+			logger.Error("Failed to schedule digest", err, logger.Field{Key: "subscription_id", Value: sub.ID})
+			continue
+		}
+		if err := s.store.ScheduleSubscription(ctx, sub.ID, next); err != nil {
+			logger.Error("Failed to schedule digest", err, logger.Field{Key: "subscription_id", Value: sub.ID})
+		}
+	}
+	return nil
+}
+
+// deliver builds and sends the digest of one subscription to its chat, in
+// the preferred currency and language of its owner
+func (s *Scheduler) deliver(ctx context.Context, sub storage.Subscription) error {
+	settings, err := s.store.GetSettings(ctx, sub.UserJID)
+	if err != nil {
+		return err
+	}
+	locale := i18n.Resolve(settings.Language, "", sub.UserJID)
+	ctx = i18n.WithLocale(ctx, locale)
+
+	coins := make([]crypto.Coin, len(sub.Coins))
+	for i, coin := range sub.Coins {
+		coins[i] = crypto.Coin{ID: coin.ID, Symbol: coin.Symbol, Name: coin.Name}
+	}
+
+	quotes, err := s.provider.SpotPrices(ctx, coins, []string{settings.Currency})
+	if err != nil {
+		return fmt.Errorf("failed to fetch digest prices: %w", err)
+	}
+
+	message, err := Build(coins, quotes, settings.Currency, locale)
+	if err != nil {
+		return err
+	}
+
+	// Commentary is a bonus; the digest is still sent without it
+	if s.commentator != nil {
+		commentary, err := s.commentator(ctx, message)
+		if err != nil {
+			logger.Warn("Failed to generate digest commentary",
+				logger.Field{Key: "subscription_id", Value: sub.ID},
+				logger.Field{Key: "error", Value: err.Error()})
+		} else if commentary != "" {
+			message += "\n\n💬 " + commentary
+		}
+	}
+
+	return s.notifier.Notify(ctx, sub.ChatJID, message)
+}
@@ -0,0 +1,153 @@
+package digest
+
+import (
+	"blockmind/internal/crypto"
+	"blockmind/internal/storage"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeProvider quotes every coin in usd at 100
+type fakeProvider struct{}
+
+func (fakeProvider) Name() string {
+	return "fake"
+}
+
+func (fakeProvider) SpotPrices(ctx context.Context, coins []crypto.Coin, vsCurrencies []string) (crypto.Quotes, error) {
+	quotes := crypto.Quotes{}
+	for _, coin := range coins {
+		quotes[coin.ID] = map[string]crypto.Quote{"usd": {Price: 100}}
+	}
+	return quotes, nil
+}
+
+func (fakeProvider) MarketSnapshot(ctx context.Context, coin crypto.Coin, vsCurrency string) (*crypto.Snapshot, error) {
+	return nil, crypto.ErrNotSupported
+}
+
+func (fakeProvider) History(ctx context.Context, coin crypto.Coin, vsCurrency string, days int) ([]crypto.PricePoint, error) {
+	return nil, crypto.ErrNotSupported
+}
+
+func (fakeProvider) CoinInfo(ctx context.Context, coin crypto.Coin) (*crypto.CoinInfo, error) {
+	return nil, crypto.ErrNotSupported
+}
+
+// fakeNotifier records the messages sent to each chat
+type fakeNotifier struct {
+	sent map[string][]string
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, jid string, text string) error {
+	n.sent[jid] = append(n.sent[jid], text)
+	return nil
+}
+
+// newTestScheduler returns a scheduler over an in-memory store
+func newTestScheduler(t *testing.T, commentator Commentator) (*Scheduler, *storage.Store, *fakeNotifier) {
+	t.Helper()
+	store, err := storage.Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	notifier := &fakeNotifier{sent: make(map[string][]string)}
+	return NewScheduler(store, fakeProvider{}, notifier, commentator, CheckInterval), store, notifier
+}
+
+// subscribe stores a daily 08:00 UTC bitcoin digest of alice, delivered to
+// chat and next due at nextRun
+func subscribe(t *testing.T, store *storage.Store, chat string, nextRun time.Time) storage.Subscription {
+	t.Helper()
+	sub := storage.Subscription{
+		UserJID:   "alice@s.whatsapp.net",
+		ChatJID:   chat,
+		Kind:      storage.SubscriptionDigest,
+		Frequency: storage.FrequencyDaily,
+		Minute:    8 * 60,
+		Timezone:  "UTC",
+		Coins:     []storage.SubscribedCoin{{ID: "bitcoin", Symbol: "btc", Name: "Bitcoin"}},
+		NextRunAt: nextRun,
+	}
+	if err := store.CreateSubscription(context.Background(), &sub); err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+	return sub
+}
+
+// nextRuns returns the next run of alice's subscriptions by chat
+func nextRuns(t *testing.T, store *storage.Store) map[string]time.Time {
+	t.Helper()
+	subs, err := store.ListSubscriptions(context.Background(), "alice@s.whatsapp.net")
+	if err != nil {
+		t.Fatalf("ListSubscriptions() error = %v", err)
+	}
+	runs := make(map[string]time.Time)
+	for _, sub := range subs {
+		runs[sub.ChatJID] = sub.NextRunAt
+	}
+	return runs
+}
+
+func TestCheckDeliversDueDigests(t *testing.T) {
+	scheduler, store, notifier := newTestScheduler(t, nil)
+	now := utc(2026, 10, 16, 8, 1)
+	subscribe(t, store, "friends@g.us", utc(2026, 10, 16, 8, 0))
+	subscribe(t, store, "late@g.us", utc(2026, 10, 16, 5, 0))
+	subscribe(t, store, "tomorrow@g.us", utc(2026, 10, 17, 8, 0))
+
+	if err := scheduler.Check(context.Background(), now); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	// The due digest goes to its chat; the late one is skipped
+	if len(notifier.sent) != 1 || len(notifier.sent["friends@g.us"]) != 1 {
+		t.Fatalf("sent %v, want one digest to friends@g.us", notifier.sent)
+	}
+	if digest := notifier.sent["friends@g.us"][0]; !strings.Contains(digest, "*BTC*") {
+		t.Errorf("digest = %q, want the bitcoin price", digest)
+	}
+
+	// Due and late digests move to their next run
+	tomorrow := utc(2026, 10, 17, 8, 0)
+	for chat, next := range nextRuns(t, store) {
+		if !next.Equal(tomorrow) {
+			t.Errorf("%s next run = %v, want %v", chat, next.UTC(), tomorrow)
+		}
+	}
+
+	// Nothing is due again until then
+	if err := scheduler.Check(context.Background(), now.Add(time.Hour)); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(notifier.sent["friends@g.us"]) != 1 {
+		t.Errorf("sent %d digests, want the digest not repeated", len(notifier.sent["friends@g.us"]))
+	}
+}
+
+func TestCheckAddsCommentary(t *testing.T) {
+	commentary := "Quiet day."
+	scheduler, store, notifier := newTestScheduler(t, func(ctx context.Context, digest string) (string, error) {
+		return commentary, nil
+	})
+	subscribe(t, store, "friends@g.us", utc(2026, 10, 16, 8, 0))
+
+	scheduler.Check(context.Background(), utc(2026, 10, 16, 8, 0))
+	if sent := notifier.sent["friends@g.us"]; len(sent) != 1 || !strings.HasSuffix(sent[0], "💬 Quiet day.") {
+		t.Errorf("sent %q, want the digest with the commentary", sent)
+	}
+
+	// A failing commentator does not hold the digest back
+	scheduler.commentator = func(ctx context.Context, digest string) (string, error) {
+		return "", errors.New("model unavailable")
+	}
+	scheduler.Check(context.Background(), utc(2026, 10, 17, 8, 0))
+	if sent := notifier.sent["friends@g.us"]; len(sent) != 2 || strings.Contains(sent[1], "💬") {
+		t.Errorf("sent %q, want a second digest without commentary", sent)
+	}
+}
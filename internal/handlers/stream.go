@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"blockmind/internal/logger"
+	"context"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// streamingSuffix marks a message that is still being written
+const streamingSuffix = " …"
+
+// progressiveMessage shows a reply while it is being generated: it is sent as
+// soon as the first text arrives and then edited as more arrives, at most once
+// per interval so the chat is not flooded with edits
+type progressiveMessage struct {
+	handler  *WhatsAppHandler
+	chat     types.JID
+	interval time.Duration
+
+	id       types.MessageID
+	shown    string
+	lastEdit time.Time
+}
+
+// newProgressiveMessage creates a progressive message for a chat
+func newProgressiveMessage(handler *WhatsAppHandler, chat types.JID, interval time.Duration) *progressiveMessage {
+	return &progressiveMessage{
+		handler:  handler,
+		chat:     chat,
+		interval: interval,
+	}
+}
+
+// Update shows the text generated so far, unless the last edit was too recent
+func (m *progressiveMessage) Update(ctx context.Context, text string) {
+	if m.id != "" && time.Since(m.lastEdit) < m.interval {
+		return
+	}
+	if err := m.show(ctx, text+streamingSuffix); err != nil {
+		logger.Warn("Failed to update streamed message", logger.Field{Key: "error", Value: err.Error()})
+	}
+}
+
+// Finish shows the final text
+func (m *progressiveMessage) Finish(ctx context.Context, text string) error {
+	return m.show(ctx, text)
+}
+
+// Started reports whether part of the reply has been shown
+func (m *progressiveMessage) Started() bool {
+	return m.id != ""
+}
+
+// Shown returns the text currently shown, without the streaming marker
+func (m *progressiveMessage) Shown() string {
+	if len(m.shown) >= len(streamingSuffix) && m.shown[len(m.shown)-len(streamingSuffix):] == streamingSuffix {
+		return m.shown[:len(m.shown)-len(streamingSuffix)]
+	}
+	return m.shown
+}
+
+// show sends the message the first time and edits it afterwards
+func (m *progressiveMessage) show(ctx context.Context, text string) error {
+	if text == m.shown {
+		return nil
+	}
+
+	content := &waE2E.Message{Conversation: &text}
+	if m.id != "" {
+		content = m.handler.client.BuildEdit(m.chat, m.id, content)
+	}
+
+	resp, err := m.handler.client.SendMessage(ctx, m.chat, content)
+	if err != nil {
+		return err
+	}
+	if m.id == "" {
+		m.id = resp.ID
+	}
+	m.shown = text
+	m.lastEdit = time.Now()
+	return nil
+}
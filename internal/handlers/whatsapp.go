@@ -1,12 +1,23 @@
 package handlers
 
 import (
+	"blockmind/internal/alerts"
+	"blockmind/internal/coingecko"
 	"blockmind/internal/commands"
 	"blockmind/internal/config"
+	"blockmind/internal/crypto"
+	"blockmind/internal/digest"
+	"blockmind/internal/i18n"
 	"blockmind/internal/ia"
+	"blockmind/internal/intent"
+	"blockmind/internal/knowledge"
+	"blockmind/internal/logger"
 	"blockmind/internal/middleware"
+	"blockmind/internal/storage"
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/proto/waE2E"
@@ -19,21 +30,78 @@ type WhatsAppHandler struct {
 	commandManager *commands.Manager
 	config         *config.Config
 	handlerChain   middleware.HandlerFunc
+	assistant      *ia.Assistant
+	store          *storage.Store
+	alertWorker    *alerts.Worker
+	digests        *digest.Scheduler
 }
 
 // NewWhatsAppHandler creates a new WhatsApp handler
-func NewWhatsAppHandler(client *whatsmeow.Client, cfg *config.Config) *WhatsAppHandler {
-	// Create default handler for non-command messages
+func NewWhatsAppHandler(client *whatsmeow.Client, cfg *config.Config, store *storage.Store) *WhatsAppHandler {
+	// Recent Q&A turns per chat, so follow-up questions keep their context
+	memory := ia.NewConversationMemory(cfg.AIMemoryTurns, cfg.AIMemoryTokens, cfg.AIMemoryTTL)
+	kb := newKnowledgeBase(cfg)
+	assistant := ia.NewAssistant(ia.NewLLMClient(cfg), cfg, memory, kb)
+
+	// Create default handler for non-command messages; it needs the handler
+	// to stream answers into the chat
+	var h *WhatsAppHandler
 	defaultHandler := func(ctx context.Context, text string) (string, error) {
-		return ia.AskQuestion(text, cfg)
+		return h.answer(ctx, text)
 	}
 
 	// Create command manager
 	manager := commands.NewManager(defaultHandler)
+	manager.SetAutocorrect(cfg.CommandAutocorrect)
+
+	// The configured owner may run every command and grant roles
+	if cfg.OwnerJID != "" {
+		if err := store.SetOwner(context.Background(), cfg.OwnerJID); err != nil {
+			logger.Error("Failed to store the bot owner", err, logger.Field{Key: "owner_jid", Value: cfg.OwnerJID})
+		}
+	}
+
+	// Shared CoinGecko client for all crypto commands
+	geckoClient := coingecko.NewClient(cfg.CoingeckoBaseURL, cfg.CoingeckoAPIKey, nil)
+	if cfg.CacheEnabled {
+		ttls := coingecko.DefaultCacheTTLs()
+		ttls.Price = cfg.CachePriceTTL
+		ttls.Markets = cfg.CacheMarketsTTL
+		ttls.CoinDetail = cfg.CacheCoinTTL
+		ttls.MarketChart = cfg.CacheCoinTTL
+		ttls.MaxStale = cfg.CacheMaxStale
+		geckoClient.SetCache(coingecko.NewMemoryCache(ttls.CoinList+ttls.MaxStale), ttls)
+	}
+	resolver := crypto.NewResolver(geckoClient, cfg.CoinListCachePath)
+	provider := newMarketDataProvider(cfg, geckoClient)
+
+	// Live market data the model can look up while answering questions
+	for _, tool := range ia.CryptoTools(provider, resolver) {
+		assistant.RegisterTool(tool)
+	}
 
 	// Register commands
-	manager.Register(commands.NewPriceCommand(cfg))
-	manager.Register(commands.NewRecommendCommand(cfg))
+	priceCmd := commands.NewPriceCommand(provider, resolver)
+	manager.Register(priceCmd)
+	manager.Register(commands.NewQuoteCommand(priceCmd))
+	manager.Register(commands.NewRecommendCommand(assistant, provider, resolver, store))
+	manager.Register(commands.NewChartCommand(provider, resolver))
+	manager.Register(commands.NewAlertCommand(store, resolver))
+	manager.Register(commands.NewPortfolioCommand(store, provider, resolver))
+	manager.Register(commands.NewWatchCommand(store, provider, resolver))
+	manager.Register(commands.NewResetCommand(memory))
+	manager.Register(commands.NewSubscribeCommand(store, resolver, cfg.DigestTimezone))
+	manager.Register(commands.NewAdminCommand(kb, store))
+	manager.Register(commands.NewLangCommand(store))
+
+	// Free-text requests for prices, recommendations and help
+	if cfg.IntentRouting {
+		var classifier intent.Classifier
+		if cfg.IntentLLM {
+			classifier = assistant.ClassifyIntent
+		}
+		manager.SetRouter(intent.NewRouter(resolver.Known, classifier, cfg.IntentThreshold))
+	}
 
 	// Help command needs a reference to the manager
 	helpCmd := commands.NewHelpCommand(manager)
@@ -45,16 +113,77 @@ func NewWhatsAppHandler(client *whatsmeow.Client, cfg *config.Config) *WhatsAppH
 	handler = middleware.RateLimiter(cfg.RateLimit, cfg.RateLimitPeriod)(handler)
 	handler = middleware.Timeout(cfg.CommandTimeout)(handler)
 
-	return &WhatsAppHandler{
+	h = &WhatsAppHandler{
 		client:         client,
 		commandManager: manager,
 		config:         cfg,
 		handlerChain:   handler,
+		assistant:      assistant,
+		store:          store,
+	}
+	h.alertWorker = alerts.NewWorker(store, provider, h, cfg.AlertCheckInterval)
+
+	var commentator digest.Commentator
+	if cfg.DigestAICommentary {
+		commentator = assistant.GetMarketCommentary
+	}
+	h.digests = digest.NewScheduler(store, provider, h, commentator, digest.CheckInterval)
+
+	return h
+}
+
+// Start runs the background workers until the context is cancelled
+func (h *WhatsAppHandler) Start(ctx context.Context) {
+	go h.alertWorker.Run(ctx)
+	go h.digests.Run(ctx)
+}
+
+// newKnowledgeBase indexes the knowledge directory, or returns nil when it is
+// disabled. A directory that cannot be read leaves the base empty until it is
+// reindexed.
+func newKnowledgeBase(cfg *config.Config) *knowledge.Base {
+	if cfg.KnowledgeDir == "" || cfg.KnowledgePassages == 0 {
+		return nil
+	}
+
+	kb := knowledge.NewBase(cfg.KnowledgeDir)
+	stats, err := kb.Reindex()
+	if err != nil {
+		logger.Warn("Knowledge base not loaded",
+			logger.Field{Key: "dir", Value: cfg.KnowledgeDir},
+			logger.Field{Key: "error", Value: err.Error()})
+		return kb
 	}
+
+	logger.Info("Knowledge base loaded",
+		logger.Field{Key: "dir", Value: cfg.KnowledgeDir},
+		logger.Field{Key: "documents", Value: stats.Documents},
+		logger.Field{Key: "passages", Value: stats.Passages})
+	return kb
 }
 
-// HandleMessage processes incoming WhatsApp messages
-func (h *WhatsAppHandler) HandleMessage(message *waE2E.Message, chatJID types.JID) {
+// newMarketDataProvider builds the provider chain selected in the configuration
+func newMarketDataProvider(cfg *config.Config, geckoClient *coingecko.Client) crypto.MarketDataProvider {
+	var providers []crypto.MarketDataProvider
+	for _, name := range cfg.MarketDataProviders {
+		switch name {
+		case "coingecko":
+			providers = append(providers, crypto.NewCoinGeckoProvider(geckoClient))
+		case "binance":
+			providers = append(providers, crypto.NewBinanceProvider(cfg.BinanceBaseURL, nil))
+		}
+	}
+
+	if len(providers) == 1 {
+		return providers[0]
+	}
+	return crypto.NewFailoverProvider(providers...)
+}
+
+// HandleMessage processes incoming WhatsApp messages. Replies go to the chat
+// while per-user data and roles belong to the sender, who differs from the
+// chat in groups.
+func (h *WhatsAppHandler) HandleMessage(message *waE2E.Message, chatJID, senderJID types.JID) {
 	// Extract text from message
 	text := message.GetConversation()
 	if text == "" {
@@ -63,21 +192,96 @@ func (h *WhatsAppHandler) HandleMessage(message *waE2E.Message, chatJID types.JI
 
 	// Create context with timeout and user info
 	ctx := context.Background()
-	ctx = context.WithValue(ctx, "user_jid", chatJID.String())
+	ctx = context.WithValue(ctx, "user_jid", senderJID.String())
+	ctx = middleware.WithChatJID(ctx, chatJID.String())
+	ctx = commands.WithResponder(ctx, &chatResponder{handler: h, chat: chatJID})
 	ctx, cancel := context.WithTimeout(ctx, h.config.CommandTimeout)
 	defer cancel()
 
+	// Send response if any
+	if response := h.reply(ctx, senderJID.String(), text); response != "" {
+		h.SendMessage(ctx, chatJID, response)
+	}
+}
+
+// reply runs a message from the sender through the handler chain and returns
+// the text to answer with, empty when there is none
+func (h *WhatsAppHandler) reply(ctx context.Context, senderJID, text string) string {
+	// Reply in the chosen language, or the one the user seems to speak
+	settings, err := h.store.GetSettings(ctx, senderJID)
+	if err != nil {
+		logger.Warn("Failed to read user settings", logger.Field{Key: "error", Value: err.Error()})
+	}
+	ctx = i18n.WithLocale(ctx, i18n.Resolve(settings.Language, text, senderJID))
+
+	// Blocked users are ignored before they count against the rate limit
+	role, err := h.store.GetRole(ctx, senderJID)
+	if err != nil {
+		fmt.Printf("Error reading user role: %v\n", err)
+		return errorReply(ctx, err)
+	}
+	if role == storage.RoleBlocked {
+		return ""
+	}
+	ctx = commands.WithRole(ctx, role)
+
 	// Use the existing handler chain
 	response, err := h.handlerChain(ctx, text)
 	if err != nil {
-		response = "Sorry, I encountered an error while processing your request."
+		response = errorReply(ctx, err)
 		fmt.Printf("Error processing message: %v\n", err)
 	}
+	return response
+}
 
-	// Send response if any
-	if response != "" {
-		h.SendMessage(ctx, chatJID, response)
+// answer replies to a free-form question. With streaming enabled the answer is
+// sent while it is generated and edited as it grows, and no reply is returned.
+func (h *WhatsAppHandler) answer(ctx context.Context, question string) (string, error) {
+	chatID, _ := middleware.GetChatJID(ctx)
+	chat, err := types.ParseJID(chatID)
+	if !h.config.AIStreaming || err != nil {
+		return h.assistant.AskQuestion(ctx, chatID, question)
+	}
+
+	message := newProgressiveMessage(h, chat, h.config.AIStreamEditInterval)
+	answer, err := h.assistant.AskQuestionStream(ctx, chatID, question, func(text string) {
+		message.Update(ctx, text)
+	})
+	if err != nil {
+		// Leave the partial answer readable; the error reply follows it
+		if message.Started() {
+			finishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+			defer cancel()
+			message.Finish(finishCtx, message.Shown()+" …\n\n"+i18n.T(ctx, "answer.interrupted"))
+		}
+		return "", err
 	}
+
+	if err := message.Finish(ctx, answer); err != nil {
+		return "", fmt.Errorf("failed to send answer: %w", err)
+	}
+
+	// The streamed message is the reply
+	return "", nil
+}
+
+// errorReply explains a failed request to the user, with specific advice for
+// AI service failures the user can do something about
+func errorReply(ctx context.Context, err error) string {
+	var apiErr *ia.APIError
+	switch {
+	case errors.Is(err, ia.ErrModelLoading):
+		wait := i18n.T(ctx, "error.model_loading.minute")
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			wait = i18n.T(ctx, "error.model_loading.seconds", int(apiErr.RetryAfter.Seconds()+0.5))
+		}
+		return i18n.T(ctx, "error.model_loading", wait)
+	case errors.Is(err, ia.ErrRateLimited):
+		return i18n.T(ctx, "error.rate_limited")
+	case errors.Is(err, ia.ErrUnavailable):
+		return i18n.T(ctx, "error.unavailable")
+	}
+	return i18n.T(ctx, "error.generic")
 }
 
 // SendMessage sends a message to WhatsApp
@@ -89,3 +293,56 @@ func (h *WhatsAppHandler) SendMessage(ctx context.Context, recipient types.JID,
 		fmt.Printf("Failed to send message: %v\n", err)
 	}
 }
+
+// Notify sends a proactive text message to the given JID
+func (h *WhatsAppHandler) Notify(ctx context.Context, jid string, text string) error {
+	recipient, err := types.ParseJID(jid)
+	if err != nil {
+		return fmt.Errorf("invalid JID %q: %w", jid, err)
+	}
+
+	_, err = h.client.SendMessage(ctx, recipient, &waE2E.Message{
+		Conversation: &text,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// SendImage uploads an image and sends it to WhatsApp with an optional caption
+func (h *WhatsAppHandler) SendImage(ctx context.Context, recipient types.JID, image []byte, mimeType, caption string) error {
+	uploaded, err := h.client.Upload(ctx, image, whatsmeow.MediaImage)
+	if err != nil {
+		return fmt.Errorf("failed to upload image: %w", err)
+	}
+
+	fileLength := uint64(len(image))
+	_, err = h.client.SendMessage(ctx, recipient, &waE2E.Message{
+		ImageMessage: &waE2E.ImageMessage{
+			Caption:       &caption,
+			Mimetype:      &mimeType,
+			URL:           &uploaded.URL,
+			DirectPath:    &uploaded.DirectPath,
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    &fileLength,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send image: %w", err)
+	}
+	return nil
+}
+
+// chatResponder sends non-text replies to the chat a message came from
+type chatResponder struct {
+	handler *WhatsAppHandler
+	chat    types.JID
+}
+
+// SendImage sends an image to the chat
+func (r *chatResponder) SendImage(ctx context.Context, image []byte, mimeType, caption string) error {
+	return r.handler.SendImage(ctx, r.chat, image, mimeType, caption)
+}
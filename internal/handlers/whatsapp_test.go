@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"blockmind/internal/commands"
+	"blockmind/internal/middleware"
+	"blockmind/internal/storage"
+	"context"
+	"testing"
+	"time"
+)
+
+// newRoleTestHandler returns a handler whose chain rate limits each user to
+// one message an hour and then answers with the role it was given. calls
+// counts the messages that reached the chain.
+func newRoleTestHandler(t *testing.T) (handler *WhatsAppHandler, store *storage.Store, calls *int) {
+	t.Helper()
+	store, err := storage.Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	calls = new(int)
+	chain := middleware.RateLimiter(1, time.Hour)(func(ctx context.Context, text string) (string, error) {
+		*calls++
+		return commands.RoleFromContext(ctx), nil
+	})
+	return &WhatsAppHandler{store: store, handlerChain: chain}, store, calls
+}
+
+// senderContext returns the context of a message from senderJID
+func senderContext(senderJID string) context.Context {
+	return middleware.WithUserID(context.Background(), senderJID)
+}
+
+func TestReplyPassesRole(t *testing.T) {
+	handler, store, calls := newRoleTestHandler(t)
+	ctx := context.Background()
+	store.SetOwner(ctx, "owner@s.whatsapp.net")
+	store.SetRole(ctx, "admin@s.whatsapp.net", storage.RoleAdmin)
+
+	tests := []struct {
+		sender string
+		want   string
+	}{
+		{"owner@s.whatsapp.net", storage.RoleOwner},
+		{"admin@s.whatsapp.net", storage.RoleAdmin},
+		{"user@s.whatsapp.net", storage.RoleUser},
+	}
+	for _, tt := range tests {
+		if got := handler.reply(senderContext(tt.sender), tt.sender, "hello"); got != tt.want {
+			t.Errorf("reply() from %s = %q, want the %s role", tt.sender, got, tt.want)
+		}
+	}
+	if *calls != len(tests) {
+		t.Errorf("chain ran %d times, want %d", *calls, len(tests))
+	}
+}
+
+func TestReplyDropsBlockedUsersBeforeRateLimit(t *testing.T) {
+	handler, store, calls := newRoleTestHandler(t)
+	const sender = "spammer@s.whatsapp.net"
+	store.SetRole(context.Background(), sender, storage.RoleBlocked)
+
+	for i := 0; i < 5; i++ {
+		if got := handler.reply(senderContext(sender), sender, "hello"); got != "" {
+			t.Errorf("reply() to a blocked user = %q, want none", got)
+		}
+	}
+	if *calls != 0 {
+		t.Errorf("chain ran %d times for a blocked user, want 0", *calls)
+	}
+
+	// The ignored messages did not use up the limit of one message
+	store.SetRole(context.Background(), sender, storage.RoleUser)
+	if got := handler.reply(senderContext(sender), sender, "hello"); got != storage.RoleUser {
+		t.Errorf("reply() after unblocking = %q, want the message handled", got)
+	}
+	if got := handler.reply(senderContext(sender), sender, "hello"); got == storage.RoleUser {
+		t.Error("reply() past the rate limit was handled, want it limited")
+	}
+}
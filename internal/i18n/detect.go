@@ -0,0 +1,95 @@
+package i18n
+
+import (
+	"strings"
+	"unicode"
+)
+
+// markers are common words of each language. Words shared by Spanish and
+// Portuguese, such as "que" or "para", are left out since they tell nothing;
+// words listed for more than one language are ignored.
+var markers = map[string]map[string]bool{
+	English:    words("the is are what how much of price should can you my and hello hi thanks please does buy sell worth which why when tell give"),
+	Spanish:    words("el la los las del es está cuál cual cuánto cuanto precio hola gracias debería deberia puedes quiero dime dame y con una vale cuesta comprar vender qué cómo ayuda"),
+	Portuguese: words("o os as do da dos das é qual quanto preço preco olá ola obrigado obrigada você voce posso quero não nao isso devo meu minha e com uma custa comprar vender ajuda"),
+}
+
+// countryLocales maps international dialling prefixes to locales. Longer
+// prefixes are matched first.
+var countryLocales = map[string]string{
+	// Spanish speaking countries
+	"34": Spanish, "52": Spanish, "54": Spanish, "56": Spanish, "57": Spanish, "51": Spanish,
+	"58": Spanish, "53": Spanish, "240": Spanish, "591": Spanish, "593": Spanish, "595": Spanish,
+	"598": Spanish, "502": Spanish, "503": Spanish, "504": Spanish, "505": Spanish, "506": Spanish,
+	"507": Spanish, "1809": Spanish, "1829": Spanish, "1849": Spanish,
+	// Portuguese speaking countries
+	"55": Portuguese, "351": Portuguese, "244": Portuguese, "258": Portuguese, "238": Portuguese,
+	"245": Portuguese, "239": Portuguese, "670": Portuguese,
+}
+
+// words builds a set from a space separated list
+func words(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(list) {
+		set[word] = true
+	}
+	return set
+}
+
+// Detect guesses the language of a message from common words. It reports
+// false when the message is too short or mixed to tell.
+func Detect(text string) (string, bool) {
+	tokens := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	// Only words of a single language count; "comprar" could be either
+	scores := make(map[string]int)
+	for _, token := range tokens {
+		var matched []string
+		for locale, set := range markers {
+			if set[token] {
+				matched = append(matched, locale)
+			}
+		}
+		if len(matched) == 1 {
+			scores[matched[0]]++
+		}
+	}
+
+	best, second := "", 0
+	for _, locale := range Locales() {
+		switch score := scores[locale]; {
+		case score > scores[best]:
+			best, second = locale, scores[best]
+		case score > second:
+			second = score
+		}
+	}
+
+	if best == "" || scores[best] < 2 || scores[best] == second {
+		return "", false
+	}
+	return best, true
+}
+
+// FromJID guesses the locale of a WhatsApp user from the country code of the
+// phone number. Groups and unknown countries get the default locale.
+func FromJID(jid string) string {
+	user, server, found := strings.Cut(jid, "@")
+	if !found || server != "s.whatsapp.net" {
+		return DefaultLocale
+	}
+	// Strip the device suffix of multi-device JIDs, as in 346001:12@s.whatsapp.net
+	user, _, _ = strings.Cut(user, ":")
+	user, _, _ = strings.Cut(user, ".")
+
+	for length := 4; length >= 2; length-- {
+		if len(user) > length {
+			if locale, ok := countryLocales[user[:length]]; ok {
+				return locale
+			}
+		}
+	}
+	return DefaultLocale
+}
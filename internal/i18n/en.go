@@ -0,0 +1,258 @@
+package i18n
+
+// en is the English catalog; it defines every message key
+var en = catalog{
+	"admin.blocked":            "🚫 %s is blocked; the bot will ignore their messages.",
+	"admin.invalid_user":       "%s is not a phone number or WhatsApp JID.",
+	"admin.knowledge_disabled": "The knowledge base is disabled. Set KNOWLEDGE_DIR to enable it.",
+	"admin.not_above":          "You cannot change the role of %s, who is %s.",
+	"admin.not_blocked":        "%s is not blocked.",
+	"admin.owner_only":         "Only the bot owner can change roles.",
+	"admin.reindexed":          "✅ Knowledge base reindexed: %d documents, %d passages in %s.",
+	"admin.role":               "%s is %s.",
+	"admin.role_set":           "✅ %s is now %s.",
+	"admin.self":               "You cannot change your own role.",
+	"admin.stats":              "*Bot statistics:*\nUsers: %d (%d admins, %d blocked)\nActive alerts: %d\nDigest subscriptions: %d\nPortfolio positions: %d\nWatched coins: %d\nRecommendations given: %d",
+	"admin.unblocked":          "✅ %s is unblocked.",
+
+	"alert.condition.above":  "above",
+	"alert.condition.below":  "below",
+	"alert.created":          "✅ Alert #%d set: I'll notify you when %s is %s %s (%s).",
+	"alert.deleted":          "Alert #%d deleted.",
+	"alert.limit":            "You already have %d alerts. Delete one with /alert delete <id> first.",
+	"alert.list.footer":      "Delete one with /alert delete <id>",
+	"alert.list.title":       "*Your alerts:*",
+	"alert.mode.once":        "once",
+	"alert.mode.repeat":      "every time the price crosses it",
+	"alert.none":             "You have no active alerts. Create one with /alert btc > 70000",
+	"alert.not_found":        "Alert #%d not found. Use /alerts to list your alerts.",
+	"alert.repeat_tag":       "(repeat)",
+	"alert.triggered":        "🔔 *Alert #%d*: %s is %s %s\nCurrent price: %s",
+	"alert.triggered.repeat": "_This alert repeats each time the price crosses the target._",
+
+	"answer.disclaimer":  "*The AI can have errors, check the information.*",
+	"answer.interrupted": "_(answer interrupted)_",
+	"answer.sources":     "📚 Sources:",
+
+	"arg.amount":   "amount",
+	"arg.coin":     "coin",
+	"arg.command":  "command",
+	"arg.currency": "currency",
+	"arg.id":       "id",
+	"arg.price":    "price",
+	"arg.time":     "HH:MM",
+	"arg.timezone": "zone",
+	"arg.user":     "phone",
+
+	"args.flag_value":         "The option --%s needs a value.",
+	"args.invalid_choice":     "\"%s\" is not valid here. Use %s.",
+	"args.invalid_currency":   "\"%s\" is not a currency code such as usd or eur.",
+	"args.invalid_integer":    "\"%s\" is not a valid whole number for %s.",
+	"args.invalid_number":     "\"%s\" is not a valid number for %s.",
+	"args.invalid_time":       "\"%s\" is not a time such as 08:00.",
+	"args.missing":            "Missing %s.",
+	"args.missing_subcommand": "Please choose an action.",
+	"args.unexpected":         "I didn't expect \"%s\" there.",
+	"args.unknown_flag":       "Unknown option --%s.",
+	"args.unknown_subcommand": "Unknown action \"%s\".",
+	"args.usage":              "*Usage:*",
+
+	"chart.not_enough_history": "Not enough price history for %s to draw a chart.",
+	"chart.summary":            "Last: %s (%s)\nHigh: %s\nLow: %s",
+	"chart.usage":              "Please specify a cryptocurrency (e.g., /chart eth 30d)",
+
+	"desc.admin":     "Bot administration (admins only): /admin reindex, /admin block <phone>, /admin unblock <phone>, /admin role <phone> [admin|user], /admin stats",
+	"desc.alert":     "Price alerts: /alert btc > 70000 [eur] [repeat], /alerts, /alert delete <id>",
+	"desc.chart":     "Get a price chart of a cryptocurrency (1d, 7d, 30d, 90d or 1y)",
+	"desc.help":      "Shows available commands and usage information",
+	"desc.lang":      "Reply language: /lang es, /lang pt, /lang en, /lang auto to detect it",
+	"desc.portfolio": "Track holdings: /portfolio add btc 0.5 @ 42000, /portfolio remove btc [amount], /portfolio currency eur, /portfolio",
+	"desc.price":     "Get the price of one or more cryptocurrencies",
+	"desc.quote":     "Get detailed quotes with 24h change, volume and market cap (same as /price <coin> full)",
+	"desc.recommend": "Get a recommendation for a cryptocurrency",
+	"desc.reset":     "Forget the conversation so far and start a new topic",
+	"desc.subscribe": "Market digests: /subscribe digest 08:00 [weekly mon] [tz Europe/Madrid] btc eth sol, /subscribe list, /subscribe cancel <id>",
+	"desc.watch":     "Watchlist: /watch add sol eth, /watch remove sol, /watch for prices of the whole list",
+
+	"details.admin":     "Maintenance and user management for admins. reindex reloads the knowledge base documents without restarting the bot. block makes the bot ignore a user and unblock lifts it. role shows a user's role; only the owner can make users admins or take the role away. stats counts users, alerts, digests, portfolios and watchlists.",
+	"details.alert":     "Notifies you when a coin's price goes above (>) or below (<) a target. An alert fires once; add repeat to be notified every time the price crosses the target again. Prices are in USD unless you name another currency.",
+	"details.chart":     "Sends a PNG chart of a coin's price over the last day (1d), week (7d), month (30d), quarter (90d) or year (1y). The default period is 7d and the default currency is USD.",
+	"details.help":      "Lists every command, or explains one command in detail with its syntax, examples and aliases.",
+	"details.lang":      "Chooses the language of the replies: English (en), Spanish (es) or Portuguese (pt). With auto, the language is detected from your messages and phone number.",
+	"details.portfolio": "Tracks your holdings. Add purchases with an optional buy price to follow cost basis and unrealized profit and loss; without arguments it shows the value and allocation of the whole portfolio.",
+	"details.price":     "Shows the current price of one or more coins, by symbol, name or id. Name the currencies after \"in\"; the default is USD. --full adds 24h change, volume and market cap.",
+	"details.quote":     "Shows a detailed quote of one or more coins: price, 24h change, volume, market cap and the time of the last update.",
+	"details.recommend": "Asks the AI for a buy, hold or sell verdict with confidence, time horizon, key risks and reasoning, based on market data and technical indicators. Without a coin, it picks the biggest mover of your watchlist. Not financial advice.",
+	"details.reset":     "Forgets the conversation so far, so the next question starts a new topic.",
+	"details.subscribe": "Sends a market digest of your coins every day, or once a week, at a local time. Without coins it covers your watchlist.",
+	"details.watch":     "Keeps a list of coins you follow. Without arguments it shows the price and 24h change of every coin on the list.",
+
+	"digest.gainers": "*Top Gainers:*",
+	"digest.losers":  "*Top Losers:*",
+	"digest.title":   "☀️ *Market Digest*",
+
+	"error.generic":               "Sorry, I encountered an error while processing your request.",
+	"error.model_loading":         "The AI model is starting up. Please try again in %s.",
+	"error.model_loading.minute":  "a minute",
+	"error.model_loading.seconds": "%d seconds",
+	"error.rate_limited":          "The AI service is busy right now. Please try again in a moment.",
+	"error.unavailable":           "The AI service is temporarily unavailable. Please try again later.",
+
+	"example.admin.block":         "ignore the messages of +34 600 111 222",
+	"example.admin.reindex":       "reload the knowledge base",
+	"example.admin.role":          "make +34 600 111 222 an admin (owner only)",
+	"example.admin.stats":         "usage statistics",
+	"example.admin.unblock":       "answer +34 600 111 222 again",
+	"example.alert.above":         "notify me once when bitcoin goes above 70000 USD",
+	"example.alert.delete":        "delete alert number 3",
+	"example.alert.list":          "show my alerts",
+	"example.alert.repeat":        "notify me every time ether drops below 3000 EUR",
+	"example.chart.default":       "ether over the last 7 days",
+	"example.chart.period":        "bitcoin over the last year, in euros",
+	"example.help.command":        "syntax and examples of /price",
+	"example.help.list":           "list every command",
+	"example.lang.auto":           "detect the language again",
+	"example.lang.set":            "reply in Spanish",
+	"example.portfolio.add":       "record 0.5 BTC bought at 42000",
+	"example.portfolio.currency":  "value the portfolio in euros from now on",
+	"example.portfolio.remove":    "remove 0.1 BTC",
+	"example.portfolio.show":      "show the portfolio valued in euros",
+	"example.price.full":          "detailed quote of solana",
+	"example.price.multiple":      "bitcoin and ether in euros and pounds",
+	"example.price.single":        "price of bitcoin in USD",
+	"example.quote.currency":      "detailed quote of ether in euros",
+	"example.quote.single":        "detailed quote of bitcoin",
+	"example.recommend.coin":      "recommendation for ether",
+	"example.recommend.watchlist": "recommendation for the biggest mover of your watchlist",
+	"example.reset.reset":         "start a new conversation",
+	"example.subscribe.cancel":    "cancel digest number 2",
+	"example.subscribe.daily":     "daily digest of BTC, ETH and SOL at 08:00",
+	"example.subscribe.list":      "show my digests",
+	"example.subscribe.weekly":    "watchlist digest every Friday at 18:30 New York time",
+	"example.watch.add":           "add solana and ether to the watchlist",
+	"example.watch.remove":        "remove solana from the watchlist",
+	"example.watch.show":          "prices of the whole watchlist",
+
+	"help.aliases":  "Aliases: %s",
+	"help.examples": "*Examples:*",
+	"help.footer":   "You can also ask me questions directly!",
+	"help.more":     "Send /help <command> for its syntax and examples.",
+	"help.title":    "*Available Commands:*",
+	"help.unknown":  "There is no /%s command. Type /help for the list.",
+
+	"horizon.long":   "long term",
+	"horizon.medium": "medium term",
+	"horizon.short":  "short term",
+
+	"lang.auto":         "✅ I will reply in the language of your messages.",
+	"lang.auto.option":  "Automatic — /lang auto",
+	"lang.available":    "*Available languages:*",
+	"lang.current":      "Your language is %s.",
+	"lang.current.auto": "Your language is detected from your messages (currently %s).",
+	"lang.set":          "✅ I will reply in %s.",
+
+	"manager.autocorrected":   "_/%s → /%s_",
+	"manager.did_you_mean":    "There is no /%s command. Did you mean %s?",
+	"manager.empty":           "Send a command like '/price Bitcoin' or ask a question.",
+	"manager.forbidden":       "You are not allowed to use /%s.",
+	"manager.not_understood":  "I don't understand that. Try typing /help for assistance.",
+	"manager.unknown_command": "Unknown command. Type /help for a list of commands.",
+
+	"middleware.rate_limited": "You're sending messages too quickly. Please wait a moment.",
+	"middleware.timeout":      "Request timed out. Please try again later.",
+
+	"portfolio.added":                "✅ Added %s %s at %s.\nYou now hold %s %s with an average cost of %s.",
+	"portfolio.currency.current":     "Your portfolio currency is %s. Change it with /portfolio currency <code> (e.g., eur).",
+	"portfolio.currency.set":         "✅ Your portfolio will be reported in %s.",
+	"portfolio.currency.unsupported": "%s is not a supported currency.",
+	"portfolio.currency_mismatch":    "Your %s position is tracked in %s. Please give the price in %s.",
+	"portfolio.empty":                "Your portfolio is empty. Add a holding with /portfolio add btc 0.5 @ 42000",
+	"portfolio.no_price":             "No %s price available for %s. Please give the purchase price with @.",
+	"portfolio.not_held":             "You don't hold any %s. Use /portfolio to see your holdings.",
+	"portfolio.removed":              "✅ Removed %s %s. You now hold %s %s.",
+	"portfolio.removed_all":          "✅ Removed %s from your portfolio.",
+	"portfolio.report.cost_basis":    "*Cost Basis:* %s",
+	"portfolio.report.pnl":           "*Unrealized P&L:* %s%s (%s)",
+	"portfolio.report.title":         "*Portfolio* (%s)",
+	"portfolio.report.total_value":   "*Total Value:* %s",
+	"portfolio.report.unpriced":      "_No current price for %s; excluded from totals._",
+
+	"price.max_coins":      "Please ask for at most %d coins at a time.",
+	"price.max_currencies": "Please ask for at most %d currencies at a time.",
+	"price.usage":          "Please specify a cryptocurrency (e.g., /%s Bitcoin or /%s btc eth in usd eur)",
+
+	"quote.change":        "• 24h Change: %s",
+	"quote.market_cap":    "• Market Cap: %s %s",
+	"quote.market_cap_na": "• Market Cap: N/A",
+	"quote.no_data":       "• No price data available",
+	"quote.price":         "• Price: %s",
+	"quote.updated":       "Updated %s",
+	"quote.volume":        "• 24h Volume: %s %s",
+
+	"recommend.disclaimer":     "*This is not financial advice. Always do your own research.*",
+	"recommend.usage":          "Please specify a cryptocurrency (e.g., /recommend Bitcoin), or add coins to your watchlist with /watch add",
+	"recommend.watchlist_pick": "Using %s from your watchlist.",
+
+	"recommendation.changed":            "Changed from %s on %s (%d%% confidence).",
+	"recommendation.details":            "Confidence: %d%% · Horizon: %s",
+	"recommendation.fallback":           "_Automatic fallback: the analysis could not be completed._",
+	"recommendation.fallback.reasoning": "The analysis could not be completed, so there is no basis to change your position. Try again later.",
+	"recommendation.fallback.risk":      "No reliable analysis is available right now",
+	"recommendation.key_risks":          "*Key risks:*",
+	"recommendation.title":              "*Recommendation for %s: %s*",
+	"recommendation.unchanged":          "Unchanged since %s (%s, %d%% confidence).",
+
+	"reset.done": "🧹 Conversation cleared. Ask me anything!",
+
+	"resolve.ambiguous":           "\"%s\" matches several coins. Did you mean:\n%s",
+	"resolve.unknown":             "I couldn't find a coin matching \"%s\".",
+	"resolve.unknown_suggestions": "I couldn't find a coin matching \"%s\". Did you mean:\n%s",
+
+	"role.admin":   "an admin",
+	"role.blocked": "blocked",
+	"role.owner":   "the owner",
+	"role.user":    "a user",
+
+	"schedule.daily":  "daily at %s",
+	"schedule.weekly": "every %s at %s",
+
+	"security.script": "⚠️ Suspicious script detected in input",
+	"security.sql":    "⚠️ Suspicious SQL syntax detected in input",
+
+	"subscribe.cancelled":        "Subscription #%d cancelled.",
+	"subscribe.created":          "✅ Digest #%d scheduled %s. The first one arrives on %s.",
+	"subscribe.invalid_timezone": "\"%s\" is not a valid timezone. Use a name such as Europe/Madrid or America/New_York.",
+	"subscribe.limit":            "You already have %d digests. Cancel one with /subscribe cancel <id> first.",
+	"subscribe.list.footer":      "Cancel one with /subscribe cancel <id>",
+	"subscribe.list.title":       "*Your digests:*",
+	"subscribe.max_coins":        "Please include at most %d coins in a digest.",
+	"subscribe.none":             "You have no digest subscriptions. Create one with /subscribe digest 08:00 btc eth sol",
+	"subscribe.not_found":        "Subscription #%d not found. Use /subscribe list to see your digests.",
+
+	"table.allocation": "ALLOC",
+	"table.amount":     "AMOUNT",
+	"table.coin":       "COIN",
+	"table.na":         "N/A",
+	"table.pnl":        "P&L",
+	"table.value":      "VALUE",
+
+	"verdict.buy":  "BUY",
+	"verdict.hold": "HOLD",
+	"verdict.sell": "SELL",
+
+	"watch.added":   "✅ Added to your watchlist: %s",
+	"watch.empty":   "Your watchlist is empty. Add coins with /watch add btc eth",
+	"watch.full":    "Your watchlist is full (%d coins). Remove one with /watch remove <coin>.",
+	"watch.missing": "Not on your watchlist: %s",
+	"watch.present": "Already on your watchlist: %s",
+	"watch.removed": "✅ Removed from your watchlist: %s",
+	"watch.title":   "*Your watchlist:*",
+
+	"weekday.0": "Sunday",
+	"weekday.1": "Monday",
+	"weekday.2": "Tuesday",
+	"weekday.3": "Wednesday",
+	"weekday.4": "Thursday",
+	"weekday.5": "Friday",
+	"weekday.6": "Saturday",
+}
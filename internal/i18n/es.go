@@ -0,0 +1,258 @@
+package i18n
+
+// es is the Spanish catalog
+var es = catalog{
+	"admin.blocked":            "🚫 %s está bloqueado; el bot ignorará sus mensajes.",
+	"admin.invalid_user":       "%s no es un número de teléfono ni un JID de WhatsApp.",
+	"admin.knowledge_disabled": "La base de conocimiento está desactivada. Define KNOWLEDGE_DIR para activarla.",
+	"admin.not_above":          "No puedes cambiar el rol de %s, que es %s.",
+	"admin.not_blocked":        "%s no está bloqueado.",
+	"admin.owner_only":         "Solo el propietario del bot puede cambiar roles.",
+	"admin.reindexed":          "✅ Base de conocimiento reindexada: %d documentos, %d pasajes en %s.",
+	"admin.role":               "%s es %s.",
+	"admin.role_set":           "✅ %s ahora es %s.",
+	"admin.self":               "No puedes cambiar tu propio rol.",
+	"admin.stats":              "*Estadísticas del bot:*\nUsuarios: %d (%d administradores, %d bloqueados)\nAlertas activas: %d\nSuscripciones a resúmenes: %d\nPosiciones en carteras: %d\nMonedas en seguimiento: %d\nRecomendaciones dadas: %d",
+	"admin.unblocked":          "✅ %s está desbloqueado.",
+
+	"alert.condition.above":  "por encima de",
+	"alert.condition.below":  "por debajo de",
+	"alert.created":          "✅ Alerta #%d creada: te avisaré cuando %s esté %s %s (%s).",
+	"alert.deleted":          "Alerta #%d eliminada.",
+	"alert.limit":            "Ya tienes %d alertas. Elimina una con /alert delete <id> primero.",
+	"alert.list.footer":      "Elimina una con /alert delete <id>",
+	"alert.list.title":       "*Tus alertas:*",
+	"alert.mode.once":        "una vez",
+	"alert.mode.repeat":      "cada vez que el precio lo cruce",
+	"alert.none":             "No tienes alertas activas. Crea una con /alert btc > 70000",
+	"alert.not_found":        "No se encontró la alerta #%d. Usa /alerts para ver tus alertas.",
+	"alert.repeat_tag":       "(repetida)",
+	"alert.triggered":        "🔔 *Alerta #%d*: %s está %s %s\nPrecio actual: %s",
+	"alert.triggered.repeat": "_Esta alerta se repite cada vez que el precio cruza el objetivo._",
+
+	"answer.disclaimer":  "*La IA puede cometer errores, verifica la información.*",
+	"answer.interrupted": "_(respuesta interrumpida)_",
+	"answer.sources":     "📚 Fuentes:",
+
+	"arg.amount":   "cantidad",
+	"arg.coin":     "moneda",
+	"arg.command":  "comando",
+	"arg.currency": "divisa",
+	"arg.id":       "id",
+	"arg.price":    "precio",
+	"arg.time":     "HH:MM",
+	"arg.timezone": "zona",
+	"arg.user":     "teléfono",
+
+	"args.flag_value":         "La opción --%s necesita un valor.",
+	"args.invalid_choice":     "\"%s\" no es válido aquí. Usa %s.",
+	"args.invalid_currency":   "\"%s\" no es un código de divisa como usd o eur.",
+	"args.invalid_integer":    "\"%s\" no es un número entero válido para %s.",
+	"args.invalid_number":     "\"%s\" no es un número válido para %s.",
+	"args.invalid_time":       "\"%s\" no es una hora como 08:00.",
+	"args.missing":            "Falta %s.",
+	"args.missing_subcommand": "Elige una acción.",
+	"args.unexpected":         "No esperaba \"%s\" ahí.",
+	"args.unknown_flag":       "Opción desconocida --%s.",
+	"args.unknown_subcommand": "Acción desconocida \"%s\".",
+	"args.usage":              "*Uso:*",
+
+	"chart.not_enough_history": "No hay suficiente historial de precios de %s para dibujar un gráfico.",
+	"chart.summary":            "Último: %s (%s)\nMáximo: %s\nMínimo: %s",
+	"chart.usage":              "Indica una criptomoneda (p. ej., /chart eth 30d)",
+
+	"desc.admin":     "Administración del bot (solo administradores): /admin reindex, /admin block <teléfono>, /admin unblock <teléfono>, /admin role <teléfono> [admin|user], /admin stats",
+	"desc.alert":     "Alertas de precio: /alert btc > 70000 [eur] [repeat], /alerts, /alert delete <id>",
+	"desc.chart":     "Gráfico de precio de una criptomoneda (1d, 7d, 30d, 90d o 1y)",
+	"desc.help":      "Muestra los comandos disponibles y cómo usarlos",
+	"desc.lang":      "Idioma de las respuestas: /lang es, /lang pt, /lang en, /lang auto para detectarlo",
+	"desc.portfolio": "Sigue tus inversiones: /portfolio add btc 0.5 @ 42000, /portfolio remove btc [cantidad], /portfolio currency eur, /portfolio",
+	"desc.price":     "Consulta el precio de una o varias criptomonedas",
+	"desc.quote":     "Cotización detallada con variación 24h, volumen y capitalización (igual que /price <moneda> full)",
+	"desc.recommend": "Obtén una recomendación sobre una criptomoneda",
+	"desc.reset":     "Olvida la conversación y empieza un tema nuevo",
+	"desc.subscribe": "Resúmenes de mercado: /subscribe digest 08:00 [weekly lun] [tz Europe/Madrid] btc eth sol, /subscribe list, /subscribe cancel <id>",
+	"desc.watch":     "Lista de seguimiento: /watch add sol eth, /watch remove sol, /watch para ver los precios de toda la lista",
+
+	"details.admin":     "Mantenimiento y gestión de usuarios para administradores. reindex vuelve a cargar los documentos de la base de conocimiento sin reiniciar el bot. block hace que el bot ignore a un usuario y unblock lo deshace. role muestra el rol de un usuario; solo el propietario puede hacer administradores o quitar ese rol. stats cuenta usuarios, alertas, resúmenes, carteras y listas de seguimiento.",
+	"details.alert":     "Te avisa cuando el precio de una moneda sube (>) o baja (<) de un objetivo. Una alerta avisa una sola vez; añade repeat para recibir un aviso cada vez que el precio vuelva a cruzar el objetivo. Los precios son en USD salvo que indiques otra divisa.",
+	"details.chart":     "Envía un gráfico PNG del precio de una moneda durante el último día (1d), semana (7d), mes (30d), trimestre (90d) o año (1y). Por defecto, 7d y en USD.",
+	"details.help":      "Muestra todos los comandos o explica uno en detalle, con su sintaxis, ejemplos y alias.",
+	"details.lang":      "Elige el idioma de las respuestas: inglés (en), español (es) o portugués (pt). Con auto, el idioma se detecta a partir de tus mensajes y tu número de teléfono.",
+	"details.portfolio": "Sigue tus inversiones. Añade compras con un precio opcional para seguir el coste medio y las ganancias o pérdidas no realizadas; sin argumentos muestra el valor y la distribución de toda la cartera.",
+	"details.price":     "Muestra el precio actual de una o varias monedas, por símbolo, nombre o id. Indica las divisas después de \"in\"; por defecto, USD. --full añade la variación 24h, el volumen y la capitalización.",
+	"details.quote":     "Muestra una cotización detallada de una o varias monedas: precio, variación 24h, volumen, capitalización y hora de la última actualización.",
+	"details.recommend": "Pide a la IA un veredicto de compra, mantener o venta con confianza, horizonte temporal, riesgos principales y razonamiento, según los datos de mercado y los indicadores técnicos. Sin moneda, elige la que más se ha movido de tu lista de seguimiento. No es asesoramiento financiero.",
+	"details.reset":     "Olvida la conversación hasta ahora, para que la siguiente pregunta empiece un tema nuevo.",
+	"details.subscribe": "Envía un resumen del mercado de tus monedas cada día, o una vez por semana, a una hora local. Sin monedas, cubre tu lista de seguimiento.",
+	"details.watch":     "Guarda una lista de las monedas que sigues. Sin argumentos muestra el precio y la variación 24h de todas las monedas de la lista.",
+
+	"digest.gainers": "*Mayores subidas:*",
+	"digest.losers":  "*Mayores bajadas:*",
+	"digest.title":   "☀️ *Resumen del mercado*",
+
+	"error.generic":               "Lo siento, ocurrió un error al procesar tu solicitud.",
+	"error.model_loading":         "El modelo de IA se está iniciando. Inténtalo de nuevo en %s.",
+	"error.model_loading.minute":  "un minuto",
+	"error.model_loading.seconds": "%d segundos",
+	"error.rate_limited":          "El servicio de IA está ocupado. Inténtalo de nuevo en un momento.",
+	"error.unavailable":           "El servicio de IA no está disponible temporalmente. Inténtalo más tarde.",
+
+	"example.admin.block":         "ignora los mensajes de +34 600 111 222",
+	"example.admin.reindex":       "vuelve a cargar la base de conocimiento",
+	"example.admin.role":          "hace administrador a +34 600 111 222 (solo el propietario)",
+	"example.admin.stats":         "estadísticas de uso",
+	"example.admin.unblock":       "vuelve a responder a +34 600 111 222",
+	"example.alert.above":         "avísame una vez cuando bitcoin supere los 70000 USD",
+	"example.alert.delete":        "elimina la alerta número 3",
+	"example.alert.list":          "muestra mis alertas",
+	"example.alert.repeat":        "avísame cada vez que ether baje de 3000 EUR",
+	"example.chart.default":       "ether en los últimos 7 días",
+	"example.chart.period":        "bitcoin en el último año, en euros",
+	"example.help.command":        "sintaxis y ejemplos de /price",
+	"example.help.list":           "muestra todos los comandos",
+	"example.lang.auto":           "vuelve a detectar el idioma",
+	"example.lang.set":            "responde en español",
+	"example.portfolio.add":       "registra 0.5 BTC comprados a 42000",
+	"example.portfolio.currency":  "valora la cartera en euros a partir de ahora",
+	"example.portfolio.remove":    "quita 0.1 BTC",
+	"example.portfolio.show":      "muestra la cartera valorada en euros",
+	"example.price.full":          "cotización detallada de solana",
+	"example.price.multiple":      "bitcoin y ether en euros y libras",
+	"example.price.single":        "precio de bitcoin en USD",
+	"example.quote.currency":      "cotización detallada de ether en euros",
+	"example.quote.single":        "cotización detallada de bitcoin",
+	"example.recommend.coin":      "recomendación sobre ether",
+	"example.recommend.watchlist": "recomendación sobre la moneda que más se ha movido de tu lista",
+	"example.reset.reset":         "empieza una conversación nueva",
+	"example.subscribe.cancel":    "cancela el resumen número 2",
+	"example.subscribe.daily":     "resumen diario de BTC, ETH y SOL a las 08:00",
+	"example.subscribe.list":      "muestra mis resúmenes",
+	"example.subscribe.weekly":    "resumen de tu lista cada viernes a las 18:30, hora de Nueva York",
+	"example.watch.add":           "añade solana y ether a la lista de seguimiento",
+	"example.watch.remove":        "quita solana de la lista de seguimiento",
+	"example.watch.show":          "precios de toda la lista de seguimiento",
+
+	"help.aliases":  "Alias: %s",
+	"help.examples": "*Ejemplos:*",
+	"help.footer":   "¡También puedes hacerme preguntas directamente!",
+	"help.more":     "Envía /help <comando> para ver su sintaxis y ejemplos.",
+	"help.title":    "*Comandos disponibles:*",
+	"help.unknown":  "No existe el comando /%s. Escribe /help para ver la lista.",
+
+	"horizon.long":   "largo plazo",
+	"horizon.medium": "medio plazo",
+	"horizon.short":  "corto plazo",
+
+	"lang.auto":         "✅ Te responderé en el idioma de tus mensajes.",
+	"lang.auto.option":  "Automático — /lang auto",
+	"lang.available":    "*Idiomas disponibles:*",
+	"lang.current":      "Tu idioma es %s.",
+	"lang.current.auto": "Tu idioma se detecta a partir de tus mensajes (ahora %s).",
+	"lang.set":          "✅ Te responderé en %s.",
+
+	"manager.autocorrected":   "_/%s → /%s_",
+	"manager.did_you_mean":    "No existe el comando /%s. ¿Quisiste decir %s?",
+	"manager.empty":           "Envía un comando como '/price Bitcoin' o haz una pregunta.",
+	"manager.forbidden":       "No tienes permiso para usar /%s.",
+	"manager.not_understood":  "No entiendo eso. Escribe /help para obtener ayuda.",
+	"manager.unknown_command": "Comando desconocido. Escribe /help para ver la lista de comandos.",
+
+	"middleware.rate_limited": "Estás enviando mensajes demasiado rápido. Espera un momento.",
+	"middleware.timeout":      "La solicitud tardó demasiado. Inténtalo más tarde.",
+
+	"portfolio.added":                "✅ Añadido %s %s a %s.\nAhora tienes %s %s con un coste medio de %s.",
+	"portfolio.currency.current":     "La divisa de tu cartera es %s. Cámbiala con /portfolio currency <código> (p. ej., eur).",
+	"portfolio.currency.set":         "✅ Tu cartera se mostrará en %s.",
+	"portfolio.currency.unsupported": "%s no es una divisa admitida.",
+	"portfolio.currency_mismatch":    "Tu posición en %s se sigue en %s. Indica el precio en %s.",
+	"portfolio.empty":                "Tu cartera está vacía. Añade una posición con /portfolio add btc 0.5 @ 42000",
+	"portfolio.no_price":             "No hay precio en %s disponible para %s. Indica el precio de compra con @.",
+	"portfolio.not_held":             "No tienes %s. Usa /portfolio para ver tus posiciones.",
+	"portfolio.removed":              "✅ Retirado %s %s. Ahora tienes %s %s.",
+	"portfolio.removed_all":          "✅ %s eliminado de tu cartera.",
+	"portfolio.report.cost_basis":    "*Coste total:* %s",
+	"portfolio.report.pnl":           "*G/P no realizada:* %s%s (%s)",
+	"portfolio.report.title":         "*Cartera* (%s)",
+	"portfolio.report.total_value":   "*Valor total:* %s",
+	"portfolio.report.unpriced":      "_Sin precio actual para %s; excluido de los totales._",
+
+	"price.max_coins":      "Pide como máximo %d monedas a la vez.",
+	"price.max_currencies": "Pide como máximo %d divisas a la vez.",
+	"price.usage":          "Indica una criptomoneda (p. ej., /%s Bitcoin o /%s btc eth in usd eur)",
+
+	"quote.change":        "• Variación 24h: %s",
+	"quote.market_cap":    "• Capitalización: %s %s",
+	"quote.market_cap_na": "• Capitalización: N/D",
+	"quote.no_data":       "• No hay datos de precio disponibles",
+	"quote.price":         "• Precio: %s",
+	"quote.updated":       "Actualizado %s",
+	"quote.volume":        "• Volumen 24h: %s %s",
+
+	"recommend.disclaimer":     "*Esto no es asesoramiento financiero. Investiga siempre por tu cuenta.*",
+	"recommend.usage":          "Indica una criptomoneda (p. ej., /recommend Bitcoin), o añade monedas a tu lista con /watch add",
+	"recommend.watchlist_pick": "Usando %s de tu lista de seguimiento.",
+
+	"recommendation.changed":            "Antes: %s el %s (%d%% de confianza).",
+	"recommendation.details":            "Confianza: %d%% · Horizonte: %s",
+	"recommendation.fallback":           "_Respuesta automática: no se pudo completar el análisis._",
+	"recommendation.fallback.reasoning": "No se pudo completar el análisis, así que no hay motivo para cambiar tu posición. Inténtalo más tarde.",
+	"recommendation.fallback.risk":      "No hay un análisis fiable disponible en este momento",
+	"recommendation.key_risks":          "*Riesgos principales:*",
+	"recommendation.title":              "*Recomendación para %s: %s*",
+	"recommendation.unchanged":          "Sin cambios desde el %s (%s, %d%% de confianza).",
+
+	"reset.done": "🧹 Conversación borrada. ¡Pregúntame lo que quieras!",
+
+	"resolve.ambiguous":           "\"%s\" coincide con varias monedas. ¿Quisiste decir alguna de estas?\n%s",
+	"resolve.unknown":             "No encontré ninguna moneda que coincida con \"%s\".",
+	"resolve.unknown_suggestions": "No encontré ninguna moneda que coincida con \"%s\". ¿Quisiste decir alguna de estas?\n%s",
+
+	"role.admin":   "administrador",
+	"role.blocked": "bloqueado",
+	"role.owner":   "el propietario",
+	"role.user":    "usuario",
+
+	"schedule.daily":  "cada día a las %s",
+	"schedule.weekly": "cada %s a las %s",
+
+	"security.script": "⚠️ Se detectó un script sospechoso en el mensaje",
+	"security.sql":    "⚠️ Se detectó sintaxis SQL sospechosa en el mensaje",
+
+	"subscribe.cancelled":        "Suscripción #%d cancelada.",
+	"subscribe.created":          "✅ Resumen #%d programado %s. El primero llegará el %s.",
+	"subscribe.invalid_timezone": "\"%s\" no es una zona horaria válida. Usa un nombre como Europe/Madrid o America/New_York.",
+	"subscribe.limit":            "Ya tienes %d resúmenes. Cancela uno con /subscribe cancel <id> primero.",
+	"subscribe.list.footer":      "Cancela uno con /subscribe cancel <id>",
+	"subscribe.list.title":       "*Tus resúmenes:*",
+	"subscribe.max_coins":        "Incluye como máximo %d monedas en un resumen.",
+	"subscribe.none":             "No tienes suscripciones a resúmenes. Crea una con /subscribe digest 08:00 btc eth sol",
+	"subscribe.not_found":        "No se encontró la suscripción #%d. Usa /subscribe list para ver tus resúmenes.",
+
+	"table.allocation": "PESO",
+	"table.amount":     "CANTIDAD",
+	"table.coin":       "MONEDA",
+	"table.na":         "N/D",
+	"table.pnl":        "G/P",
+	"table.value":      "VALOR",
+
+	"verdict.buy":  "COMPRAR",
+	"verdict.hold": "MANTENER",
+	"verdict.sell": "VENDER",
+
+	"watch.added":   "✅ Añadido a tu lista de seguimiento: %s",
+	"watch.empty":   "Tu lista de seguimiento está vacía. Añade monedas con /watch add btc eth",
+	"watch.full":    "Tu lista de seguimiento está llena (%d monedas). Quita una con /watch remove <moneda>.",
+	"watch.missing": "No está en tu lista de seguimiento: %s",
+	"watch.present": "Ya está en tu lista de seguimiento: %s",
+	"watch.removed": "✅ Quitado de tu lista de seguimiento: %s",
+	"watch.title":   "*Tu lista de seguimiento:*",
+
+	"weekday.0": "domingo",
+	"weekday.1": "lunes",
+	"weekday.2": "martes",
+	"weekday.3": "miércoles",
+	"weekday.4": "jueves",
+	"weekday.5": "viernes",
+	"weekday.6": "sábado",
+}
@@ -0,0 +1,120 @@
+// Package i18n translates the bot's replies. Messages are looked up by key in
+// per-language catalogs and formatted with fmt verbs; missing translations
+// fall back to English.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Supported locales
+const (
+	English    = "en"
+	Spanish    = "es"
+	Portuguese = "pt"
+)
+
+// DefaultLocale is used when nothing better is known about a user
+const DefaultLocale = English
+
+// catalog maps message keys to format strings
+type catalog map[string]string
+
+// catalogs holds the translations of every supported locale
+var catalogs = map[string]catalog{
+	English:    en,
+	Spanish:    es,
+	Portuguese: pt,
+}
+
+// names are the names of the locales in their own language
+var names = map[string]string{
+	English:    "English",
+	Spanish:    "Español",
+	Portuguese: "Português",
+}
+
+// localeKey is the context key of the locale
+type localeKey struct{}
+
+// Locales returns the supported locales
+func Locales() []string {
+	return []string{English, Spanish, Portuguese}
+}
+
+// IsSupported reports whether locale has a catalog
+func IsSupported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// Name returns the name of a locale in its own language
+func Name(locale string) string {
+	if name, ok := names[locale]; ok {
+		return name
+	}
+	return locale
+}
+
+// WithLocale returns a context carrying the locale of the user
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext returns the locale carried by the context, or the default
+func FromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok && IsSupported(locale) {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// T translates a message into the locale of the context
+func T(ctx context.Context, key string, args ...interface{}) string {
+	return Translate(FromContext(ctx), key, args...)
+}
+
+// Translate translates a message into locale. Missing messages fall back to
+// English, and to the key itself when English lacks them too.
+func Translate(locale, key string, args ...interface{}) string {
+	message, ok := catalogs[locale][key]
+	if !ok {
+		message, ok = catalogs[DefaultLocale][key]
+	}
+	if !ok {
+		message = key
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Has reports whether a message exists in the English catalog
+func Has(key string) bool {
+	_, ok := catalogs[DefaultLocale][key]
+	return ok
+}
+
+// Weekday returns the name of a weekday in locale
+func Weekday(locale string, day time.Weekday) string {
+	return Translate(locale, fmt.Sprintf("weekday.%d", day))
+}
+
+// Resolve picks the locale of a user: the stored preference first, then the
+// language of the message, then the country of the phone number
+func Resolve(preference, text, jid string) string {
+	if IsSupported(preference) {
+		return preference
+	}
+	if !strings.HasPrefix(strings.TrimSpace(text), "/") {
+		if locale, ok := Detect(text); ok {
+			return locale
+		}
+	}
+	return FromJID(jid)
+}
@@ -0,0 +1,258 @@
+package i18n
+
+// pt is the Portuguese catalog
+var pt = catalog{
+	"admin.blocked":            "🚫 %s está bloqueado; o bot vai ignorar as mensagens dessa pessoa.",
+	"admin.invalid_user":       "%s não é um número de telefone nem um JID do WhatsApp.",
+	"admin.knowledge_disabled": "A base de conhecimento está desativada. Defina KNOWLEDGE_DIR para ativá-la.",
+	"admin.not_above":          "Você não pode mudar o papel de %s, que é %s.",
+	"admin.not_blocked":        "%s não está bloqueado.",
+	"admin.owner_only":         "Só o dono do bot pode mudar papéis.",
+	"admin.reindexed":          "✅ Base de conhecimento reindexada: %d documentos, %d trechos em %s.",
+	"admin.role":               "%s é %s.",
+	"admin.role_set":           "✅ %s agora é %s.",
+	"admin.self":               "Você não pode mudar o seu próprio papel.",
+	"admin.stats":              "*Estatísticas do bot:*\nUsuários: %d (%d administradores, %d bloqueados)\nAlertas ativos: %d\nAssinaturas de resumos: %d\nPosições em carteiras: %d\nMoedas acompanhadas: %d\nRecomendações dadas: %d",
+	"admin.unblocked":          "✅ %s está desbloqueado.",
+
+	"alert.condition.above":  "acima de",
+	"alert.condition.below":  "abaixo de",
+	"alert.created":          "✅ Alerta #%d criado: vou avisar você quando %s estiver %s %s (%s).",
+	"alert.deleted":          "Alerta #%d excluído.",
+	"alert.limit":            "Você já tem %d alertas. Exclua um com /alert delete <id> primeiro.",
+	"alert.list.footer":      "Exclua um com /alert delete <id>",
+	"alert.list.title":       "*Seus alertas:*",
+	"alert.mode.once":        "uma vez",
+	"alert.mode.repeat":      "sempre que o preço cruzar o alvo",
+	"alert.none":             "Você não tem alertas ativos. Crie um com /alert btc > 70000",
+	"alert.not_found":        "Alerta #%d não encontrado. Use /alerts para ver seus alertas.",
+	"alert.repeat_tag":       "(repetido)",
+	"alert.triggered":        "🔔 *Alerta #%d*: %s está %s %s\nPreço atual: %s",
+	"alert.triggered.repeat": "_Este alerta se repete sempre que o preço cruza o alvo._",
+
+	"answer.disclaimer":  "*A IA pode cometer erros, confira as informações.*",
+	"answer.interrupted": "_(resposta interrompida)_",
+	"answer.sources":     "📚 Fontes:",
+
+	"arg.amount":   "quantidade",
+	"arg.coin":     "moeda",
+	"arg.command":  "comando",
+	"arg.currency": "divisa",
+	"arg.id":       "id",
+	"arg.price":    "preço",
+	"arg.time":     "HH:MM",
+	"arg.timezone": "fuso",
+	"arg.user":     "telefone",
+
+	"args.flag_value":         "A opção --%s precisa de um valor.",
+	"args.invalid_choice":     "\"%s\" não é válido aqui. Use %s.",
+	"args.invalid_currency":   "\"%s\" não é um código de divisa como usd ou brl.",
+	"args.invalid_integer":    "\"%s\" não é um número inteiro válido para %s.",
+	"args.invalid_number":     "\"%s\" não é um número válido para %s.",
+	"args.invalid_time":       "\"%s\" não é um horário como 08:00.",
+	"args.missing":            "Falta %s.",
+	"args.missing_subcommand": "Escolha uma ação.",
+	"args.unexpected":         "Não esperava \"%s\" aí.",
+	"args.unknown_flag":       "Opção desconhecida --%s.",
+	"args.unknown_subcommand": "Ação desconhecida \"%s\".",
+	"args.usage":              "*Uso:*",
+
+	"chart.not_enough_history": "Não há histórico de preços suficiente de %s para desenhar um gráfico.",
+	"chart.summary":            "Último: %s (%s)\nMáxima: %s\nMínima: %s",
+	"chart.usage":              "Informe uma criptomoeda (ex.: /chart eth 30d)",
+
+	"desc.admin":     "Administração do bot (só administradores): /admin reindex, /admin block <telefone>, /admin unblock <telefone>, /admin role <telefone> [admin|user], /admin stats",
+	"desc.alert":     "Alertas de preço: /alert btc > 70000 [eur] [repeat], /alerts, /alert delete <id>",
+	"desc.chart":     "Gráfico de preço de uma criptomoeda (1d, 7d, 30d, 90d ou 1y)",
+	"desc.help":      "Mostra os comandos disponíveis e como usá-los",
+	"desc.lang":      "Idioma das respostas: /lang es, /lang pt, /lang en, /lang auto para detectá-lo",
+	"desc.portfolio": "Acompanhe seus ativos: /portfolio add btc 0.5 @ 42000, /portfolio remove btc [quantidade], /portfolio currency eur, /portfolio",
+	"desc.price":     "Consulte o preço de uma ou mais criptomoedas",
+	"desc.quote":     "Cotação detalhada com variação 24h, volume e capitalização (igual a /price <moeda> full)",
+	"desc.recommend": "Receba uma recomendação sobre uma criptomoeda",
+	"desc.reset":     "Esquece a conversa e começa um assunto novo",
+	"desc.subscribe": "Resumos do mercado: /subscribe digest 08:00 [weekly seg] [tz America/Sao_Paulo] btc eth sol, /subscribe list, /subscribe cancel <id>",
+	"desc.watch":     "Lista de acompanhamento: /watch add sol eth, /watch remove sol, /watch para ver os preços da lista toda",
+
+	"details.admin":     "Manutenção e gestão de usuários para administradores. reindex recarrega os documentos da base de conhecimento sem reiniciar o bot. block faz o bot ignorar um usuário e unblock desfaz isso. role mostra o papel de um usuário; só o dono pode tornar usuários administradores ou tirar esse papel. stats conta usuários, alertas, resumos, carteiras e listas de acompanhamento.",
+	"details.alert":     "Avisa quando o preço de uma moeda sobe (>) ou desce (<) de um alvo. Um alerta avisa uma única vez; adicione repeat para ser avisado toda vez que o preço cruzar o alvo de novo. Os preços são em USD, a menos que você indique outra moeda.",
+	"details.chart":     "Envia um gráfico PNG do preço de uma moeda no último dia (1d), semana (7d), mês (30d), trimestre (90d) ou ano (1y). Por padrão, 7d e em USD.",
+	"details.help":      "Lista todos os comandos ou explica um em detalhe, com sintaxe, exemplos e apelidos.",
+	"details.lang":      "Escolhe o idioma das respostas: inglês (en), espanhol (es) ou português (pt). Com auto, o idioma é detectado pelas suas mensagens e pelo seu número de telefone.",
+	"details.portfolio": "Acompanha seus investimentos. Adicione compras com um preço opcional para acompanhar o custo médio e o lucro ou prejuízo não realizado; sem argumentos mostra o valor e a distribuição de toda a carteira.",
+	"details.price":     "Mostra o preço atual de uma ou mais moedas, por símbolo, nome ou id. Indique as moedas de cotação depois de \"in\"; o padrão é USD. --full adiciona a variação 24h, o volume e a capitalização.",
+	"details.quote":     "Mostra uma cotação detalhada de uma ou mais moedas: preço, variação 24h, volume, capitalização e horário da última atualização.",
+	"details.recommend": "Pede à IA um veredito de compra, manter ou venda com confiança, horizonte de tempo, principais riscos e raciocínio, com base nos dados de mercado e nos indicadores técnicos. Sem moeda, escolhe a que mais se moveu na sua lista de acompanhamento. Não é aconselhamento financeiro.",
+	"details.reset":     "Esquece a conversa até agora, para que a próxima pergunta comece um assunto novo.",
+	"details.subscribe": "Envia um resumo do mercado das suas moedas todos os dias, ou uma vez por semana, em um horário local. Sem moedas, cobre sua lista de acompanhamento.",
+	"details.watch":     "Guarda uma lista das moedas que você acompanha. Sem argumentos mostra o preço e a variação 24h de todas as moedas da lista.",
+
+	"digest.gainers": "*Maiores altas:*",
+	"digest.losers":  "*Maiores baixas:*",
+	"digest.title":   "☀️ *Resumo do mercado*",
+
+	"error.generic":               "Desculpe, ocorreu um erro ao processar sua solicitação.",
+	"error.model_loading":         "O modelo de IA está iniciando. Tente novamente em %s.",
+	"error.model_loading.minute":  "um minuto",
+	"error.model_loading.seconds": "%d segundos",
+	"error.rate_limited":          "O serviço de IA está ocupado agora. Tente novamente em instantes.",
+	"error.unavailable":           "O serviço de IA está temporariamente indisponível. Tente mais tarde.",
+
+	"example.admin.block":         "ignora as mensagens de +34 600 111 222",
+	"example.admin.reindex":       "recarrega a base de conhecimento",
+	"example.admin.role":          "torna +34 600 111 222 administrador (só o dono)",
+	"example.admin.stats":         "estatísticas de uso",
+	"example.admin.unblock":       "volta a responder a +34 600 111 222",
+	"example.alert.above":         "me avise uma vez quando o bitcoin passar de 70000 USD",
+	"example.alert.delete":        "exclui o alerta número 3",
+	"example.alert.list":          "mostra meus alertas",
+	"example.alert.repeat":        "me avise toda vez que o ether cair abaixo de 3000 EUR",
+	"example.chart.default":       "ether nos últimos 7 dias",
+	"example.chart.period":        "bitcoin no último ano, em euros",
+	"example.help.command":        "sintaxe e exemplos de /price",
+	"example.help.list":           "lista todos os comandos",
+	"example.lang.auto":           "detecta o idioma de novo",
+	"example.lang.set":            "responde em espanhol",
+	"example.portfolio.add":       "registra 0.5 BTC comprados a 42000",
+	"example.portfolio.currency":  "avalia a carteira em euros a partir de agora",
+	"example.portfolio.remove":    "remove 0.1 BTC",
+	"example.portfolio.show":      "mostra a carteira avaliada em euros",
+	"example.price.full":          "cotação detalhada da solana",
+	"example.price.multiple":      "bitcoin e ether em euros e libras",
+	"example.price.single":        "preço do bitcoin em USD",
+	"example.quote.currency":      "cotação detalhada do ether em euros",
+	"example.quote.single":        "cotação detalhada do bitcoin",
+	"example.recommend.coin":      "recomendação sobre o ether",
+	"example.recommend.watchlist": "recomendação sobre a moeda que mais se moveu na sua lista",
+	"example.reset.reset":         "começa uma conversa nova",
+	"example.subscribe.cancel":    "cancela o resumo número 2",
+	"example.subscribe.daily":     "resumo diário de BTC, ETH e SOL às 08:00",
+	"example.subscribe.list":      "mostra meus resumos",
+	"example.subscribe.weekly":    "resumo da sua lista toda sexta às 18:30, horário de Nova York",
+	"example.watch.add":           "adiciona solana e ether à lista de acompanhamento",
+	"example.watch.remove":        "remove solana da lista de acompanhamento",
+	"example.watch.show":          "preços de toda a lista de acompanhamento",
+
+	"help.aliases":  "Atalhos: %s",
+	"help.examples": "*Exemplos:*",
+	"help.footer":   "Você também pode me fazer perguntas diretamente!",
+	"help.more":     "Envie /help <comando> para ver a sintaxe e exemplos.",
+	"help.title":    "*Comandos disponíveis:*",
+	"help.unknown":  "Não existe o comando /%s. Digite /help para ver a lista.",
+
+	"horizon.long":   "longo prazo",
+	"horizon.medium": "médio prazo",
+	"horizon.short":  "curto prazo",
+
+	"lang.auto":         "✅ Vou responder no idioma das suas mensagens.",
+	"lang.auto.option":  "Automático — /lang auto",
+	"lang.available":    "*Idiomas disponíveis:*",
+	"lang.current":      "Seu idioma é %s.",
+	"lang.current.auto": "Seu idioma é detectado pelas suas mensagens (agora %s).",
+	"lang.set":          "✅ Vou responder em %s.",
+
+	"manager.autocorrected":   "_/%s → /%s_",
+	"manager.did_you_mean":    "Não existe o comando /%s. Você quis dizer %s?",
+	"manager.empty":           "Envie um comando como '/price Bitcoin' ou faça uma pergunta.",
+	"manager.forbidden":       "Você não tem permissão para usar /%s.",
+	"manager.not_understood":  "Não entendi. Digite /help para obter ajuda.",
+	"manager.unknown_command": "Comando desconhecido. Digite /help para ver a lista de comandos.",
+
+	"middleware.rate_limited": "Você está enviando mensagens rápido demais. Aguarde um momento.",
+	"middleware.timeout":      "A solicitação demorou demais. Tente novamente mais tarde.",
+
+	"portfolio.added":                "✅ Adicionado %s %s a %s.\nAgora você tem %s %s com custo médio de %s.",
+	"portfolio.currency.current":     "A divisa da sua carteira é %s. Altere com /portfolio currency <código> (ex.: brl).",
+	"portfolio.currency.set":         "✅ Sua carteira será mostrada em %s.",
+	"portfolio.currency.unsupported": "%s não é uma divisa suportada.",
+	"portfolio.currency_mismatch":    "Sua posição em %s é acompanhada em %s. Informe o preço em %s.",
+	"portfolio.empty":                "Sua carteira está vazia. Adicione um ativo com /portfolio add btc 0.5 @ 42000",
+	"portfolio.no_price":             "Não há preço em %s disponível para %s. Informe o preço de compra com @.",
+	"portfolio.not_held":             "Você não tem %s. Use /portfolio para ver seus ativos.",
+	"portfolio.removed":              "✅ Removido %s %s. Agora você tem %s %s.",
+	"portfolio.removed_all":          "✅ %s removido da sua carteira.",
+	"portfolio.report.cost_basis":    "*Custo total:* %s",
+	"portfolio.report.pnl":           "*L/P não realizado:* %s%s (%s)",
+	"portfolio.report.title":         "*Carteira* (%s)",
+	"portfolio.report.total_value":   "*Valor total:* %s",
+	"portfolio.report.unpriced":      "_Sem preço atual para %s; excluído dos totais._",
+
+	"price.max_coins":      "Peça no máximo %d moedas por vez.",
+	"price.max_currencies": "Peça no máximo %d divisas por vez.",
+	"price.usage":          "Informe uma criptomoeda (ex.: /%s Bitcoin ou /%s btc eth in usd brl)",
+
+	"quote.change":        "• Variação 24h: %s",
+	"quote.market_cap":    "• Capitalização: %s %s",
+	"quote.market_cap_na": "• Capitalização: N/D",
+	"quote.no_data":       "• Não há dados de preço disponíveis",
+	"quote.price":         "• Preço: %s",
+	"quote.updated":       "Atualizado %s",
+	"quote.volume":        "• Volume 24h: %s %s",
+
+	"recommend.disclaimer":     "*Isto não é aconselhamento financeiro. Sempre faça sua própria pesquisa.*",
+	"recommend.usage":          "Informe uma criptomoeda (ex.: /recommend Bitcoin), ou adicione moedas à sua lista com /watch add",
+	"recommend.watchlist_pick": "Usando %s da sua lista de acompanhamento.",
+
+	"recommendation.changed":            "Antes: %s em %s (%d%% de confiança).",
+	"recommendation.details":            "Confiança: %d%% · Horizonte: %s",
+	"recommendation.fallback":           "_Resposta automática: não foi possível concluir a análise._",
+	"recommendation.fallback.reasoning": "Não foi possível concluir a análise, então não há motivo para mudar sua posição. Tente mais tarde.",
+	"recommendation.fallback.risk":      "Nenhuma análise confiável disponível no momento",
+	"recommendation.key_risks":          "*Principais riscos:*",
+	"recommendation.title":              "*Recomendação para %s: %s*",
+	"recommendation.unchanged":          "Sem mudanças desde %s (%s, %d%% de confiança).",
+
+	"reset.done": "🧹 Conversa apagada. Pergunte o que quiser!",
+
+	"resolve.ambiguous":           "\"%s\" corresponde a várias moedas. Você quis dizer:\n%s",
+	"resolve.unknown":             "Não encontrei nenhuma moeda correspondente a \"%s\".",
+	"resolve.unknown_suggestions": "Não encontrei nenhuma moeda correspondente a \"%s\". Você quis dizer:\n%s",
+
+	"role.admin":   "administrador",
+	"role.blocked": "bloqueado",
+	"role.owner":   "o dono",
+	"role.user":    "usuário",
+
+	"schedule.daily":  "todos os dias às %s",
+	"schedule.weekly": "todas as semanas (%s) às %s",
+
+	"security.script": "⚠️ Script suspeito detectado na mensagem",
+	"security.sql":    "⚠️ Sintaxe SQL suspeita detectada na mensagem",
+
+	"subscribe.cancelled":        "Assinatura #%d cancelada.",
+	"subscribe.created":          "✅ Resumo #%d agendado %s. O primeiro chega em %s.",
+	"subscribe.invalid_timezone": "\"%s\" não é um fuso horário válido. Use um nome como America/Sao_Paulo ou Europe/Lisbon.",
+	"subscribe.limit":            "Você já tem %d resumos. Cancele um com /subscribe cancel <id> primeiro.",
+	"subscribe.list.footer":      "Cancele um com /subscribe cancel <id>",
+	"subscribe.list.title":       "*Seus resumos:*",
+	"subscribe.max_coins":        "Inclua no máximo %d moedas em um resumo.",
+	"subscribe.none":             "Você não tem assinaturas de resumos. Crie uma com /subscribe digest 08:00 btc eth sol",
+	"subscribe.not_found":        "Assinatura #%d não encontrada. Use /subscribe list para ver seus resumos.",
+
+	"table.allocation": "PESO",
+	"table.amount":     "QTD",
+	"table.coin":       "MOEDA",
+	"table.na":         "N/D",
+	"table.pnl":        "L/P",
+	"table.value":      "VALOR",
+
+	"verdict.buy":  "COMPRAR",
+	"verdict.hold": "MANTER",
+	"verdict.sell": "VENDER",
+
+	"watch.added":   "✅ Adicionado à sua lista de acompanhamento: %s",
+	"watch.empty":   "Sua lista de acompanhamento está vazia. Adicione moedas com /watch add btc eth",
+	"watch.full":    "Sua lista de acompanhamento está cheia (%d moedas). Remova uma com /watch remove <moeda>.",
+	"watch.missing": "Não está na sua lista de acompanhamento: %s",
+	"watch.present": "Já está na sua lista de acompanhamento: %s",
+	"watch.removed": "✅ Removido da sua lista de acompanhamento: %s",
+	"watch.title":   "*Sua lista de acompanhamento:*",
+
+	"weekday.0": "domingo",
+	"weekday.1": "segunda-feira",
+	"weekday.2": "terça-feira",
+	"weekday.3": "quarta-feira",
+	"weekday.4": "quinta-feira",
+	"weekday.5": "sexta-feira",
+	"weekday.6": "sábado",
+}
@@ -1,120 +1,144 @@
 package ia
 
 import (
-	"blockmind/internal/config"
-	"bytes"
+	"blockmind/internal/i18n"
+	"blockmind/internal/knowledge"
+	"blockmind/internal/logger"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"time"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
-// Message represents a chat message
-type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-// AskQuestion sends a question to the Hugging Face API and returns the answer
-func AskQuestion(question string, cfg *config.Config) (string, error) {
-	// Create a context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.AITimeout)
-	defer cancel()
-
-	// Improved prompt structure with clear separation of instructions
-	systemPrompt := `You are a precise question-answering system.
+// questionSystemPrompt instructs the model for general Q&A
+const questionSystemPrompt = `You are a precise question-answering system.
 Rules:
 1. Respond ONLY with the answer, no extra text or formatting
 2. Match the question's language exactly
 3. Never use markdown or special characters
-4. Stop generation immediately after answer`
-
-	userPrompt := fmt.Sprintf("Question: %s", question)
-
-	requestBody := map[string]interface{}{
-		"model": cfg.HuggingFaceModel,
-		"messages": []Message{
-			{Role: "system", Content: systemPrompt},
-			{Role: "user", Content: userPrompt},
-		},
-		"temperature": cfg.AITemperature,
-		"max_tokens":  cfg.AIMaxTokens,
+4. Stop generation immediately after answer
+5. When reference passages are given, prefer them over your own knowledge and cite the ones you use as [1], [2]`
+
+// citationPattern matches a citation such as [2] in an answer
+var citationPattern = regexp.MustCompile(`\[(\d+)\]`)
+
+// AskQuestion sends a question to the model and returns the answer. Prior
+// turns of the chat are sent along so follow-up questions keep their context.
+func (a *Assistant) AskQuestion(ctx context.Context, chatID, question string) (string, error) {
+	return a.AskQuestionStream(ctx, chatID, question, nil)
+}
+
+// AskQuestionStream is like AskQuestion but streams the answer, calling
+// onUpdate with the text so far as it grows. A nil onUpdate waits for the
+// whole answer instead.
+func (a *Assistant) AskQuestionStream(ctx context.Context, chatID, question string, onUpdate func(text string)) (string, error) {
+	userMessage := Message{Role: "user", Content: fmt.Sprintf("Question: %s", question)}
+
+	// Passages go with the question only; the memory keeps the bare question
+	passages := a.knowledge.Search(question, a.passages)
+	prompt := userMessage
+	if len(passages) > 0 {
+		prompt.Content = formatPassages(passages) + "\n\n" + userMessage.Content
 	}
 
-	body, err := json.Marshal(requestBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to encode request body: %w", err)
+	systemPrompt := questionSystemPrompt
+	if a.toolsEnabled() {
+		systemPrompt += "\n\n" + a.toolPrompt()
 	}
 
-	// Create HTTP request with context
-	req, err := http.NewRequestWithContext(
-		ctx,
-		"POST",
-		cfg.GetHuggingFaceAPIURL(),
-		bytes.NewBuffer(body),
-	)
+	messages := []Message{{Role: "system", Content: systemPrompt}}
+	messages = append(messages, a.memory.History(chatID)...)
+	messages = append(messages, prompt)
+
+	content, err := a.answer(ctx, messages, onUpdate)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", err
 	}
+	a.memory.Add(chatID, userMessage, Message{Role: "assistant", Content: content})
 
-	// Set headers
-	req.Header.Set("Authorization", "Bearer "+cfg.HuggingFaceAPIKey)
-	req.Header.Set("Content-Type", "application/json")
+	return content + formatSources(ctx, content, passages) + "\n\n" + i18n.T(ctx, "answer.disclaimer"), nil
+}
 
-	// Send request with custom client and timeout
-	client := &http.Client{
-		Timeout: cfg.AITimeout - 1*time.Second, // Slightly less than context timeout
-	}
+// answer gets the model's answer to messages, running the tools it calls on
+// the way. After maxToolCalls calls the model is told to answer with what it
+// has. Streamed replies that may be tool calls are not shown.
+func (a *Assistant) answer(ctx context.Context, messages []Message, onUpdate func(text string)) (string, error) {
+	for calls := 0; ; calls++ {
+		var content string
+		var err error
+		if onUpdate != nil {
+			content, err = a.stream(ctx, a.qaModel, messages, func(text string) {
+				if a.toolsEnabled() && mayBeToolCall(text) {
+					return
+				}
+				onUpdate(text)
+			})
+		} else {
+			content, err = a.complete(ctx, a.qaModel, messages)
+		}
+		if err != nil {
+			return "", err
+		}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("API request failed: %w", err)
-	}
-	defer resp.Body.Close()
+		call, ok := parseToolCall(content)
+		if !ok || !a.toolsEnabled() {
+			return content, nil
+		}
+		if calls == a.maxToolCalls {
+			return "", ErrTooManyToolCalls
+		}
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		logger.Info("Tool call", logger.Field{Key: "tool", Value: call.Tool}, logger.Field{Key: "call", Value: calls + 1})
+		result := a.runTool(ctx, call)
+		if calls+1 == a.maxToolCalls {
+			result += "\n\nYou cannot call more tools; answer with the data you have."
+		}
+		messages = append(messages,
+			Message{Role: "assistant", Content: content},
+			Message{Role: "user", Content: result},
+		)
 	}
+}
 
-	// Read response
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API request failed with status %s: %s", resp.Status, string(respBody))
-	}
+// toolsEnabled reports whether the model may call tools
+func (a *Assistant) toolsEnabled() bool {
+	return len(a.tools) > 0 && a.maxToolCalls > 0
+}
 
-	var response map[string]interface{}
-	if err := json.Unmarshal(respBody, &response); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+// formatPassages numbers the reference passages for the prompt
+func formatPassages(passages []knowledge.Result) string {
+	var b strings.Builder
+	b.WriteString("Reference passages:")
+	for i, passage := range passages {
+		fmt.Fprintf(&b, "\n[%d] (%s) %s", i+1, passageTitle(passage.Passage), passage.Text)
 	}
+	return b.String()
+}
 
-	// Extract answer using a more robust approach with type assertions
-	if choices, ok := response["choices"].([]interface{}); ok && len(choices) > 0 {
-		if choice, ok := choices[0].(map[string]interface{}); ok {
-			if message, ok := choice["message"].(map[string]interface{}); ok {
-				if content, ok := message["content"].(string); ok {
-					content = content + "\n\n" + "*The AI can have errors, check the information.*"
-					return content, nil
-				}
-			}
+// formatSources lists the passages cited in the answer, in citation order
+func formatSources(ctx context.Context, answer string, passages []knowledge.Result) string {
+	var sources []string
+	cited := make(map[int]bool)
+	for _, match := range citationPattern.FindAllStringSubmatch(answer, -1) {
+		n, err := strconv.Atoi(match[1])
+		if err != nil || n < 1 || n > len(passages) || cited[n] {
+			continue
 		}
+		cited[n] = true
+		sources = append(sources, fmt.Sprintf("[%d] %s", n, passageTitle(passages[n-1].Passage)))
 	}
 
-	// Try to extract the answer from different possible keys
-	if answer, ok := response["answer"].(string); ok {
-		return answer, nil
+	if len(sources) == 0 {
+		return ""
 	}
+	return "\n\n" + i18n.T(ctx, "answer.sources") + " " + strings.Join(sources, ", ")
+}
 
-	// Some models might return an array of answers
-	if answers, ok := response["answers"].([]interface{}); ok && len(answers) > 0 {
-		if firstAnswer, ok := answers[0].(map[string]interface{}); ok {
-			if text, ok := firstAnswer["answer"].(string); ok {
-				return text, nil
-			}
-		}
+// passageTitle names a passage by its document and section
+func passageTitle(passage knowledge.Passage) string {
+	if passage.Heading == "" {
+		return passage.Source
 	}
-
-	// If we can't find a structured answer, return a generic message
-	return "I couldn't understand the response from the AI service.", nil
+	return passage.Source + " › " + passage.Heading
 }
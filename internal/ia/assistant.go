@@ -0,0 +1,116 @@
+package ia
+
+import (
+	"blockmind/internal/config"
+	"blockmind/internal/knowledge"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Assistant implements the bot's AI features on top of an LLMClient. Q&A and
+// market analysis may run on different models.
+type Assistant struct {
+	client         LLMClient
+	qaModel        string
+	recommendModel string
+	temperature    float64
+	maxTokens      int
+	timeout        time.Duration
+	memory         *ConversationMemory
+	knowledge      *knowledge.Base
+	passages       int
+	tools          []Tool
+	maxToolCalls   int
+}
+
+// NewAssistant creates an assistant using the model settings in cfg. Memory
+// keeps Q&A context between messages and the knowledge base provides reference
+// passages for answers; both may be nil.
+func NewAssistant(client LLMClient, cfg *config.Config, memory *ConversationMemory, kb *knowledge.Base) *Assistant {
+	return &Assistant{
+		client:         client,
+		qaModel:        cfg.LLMQAModel,
+		recommendModel: cfg.LLMRecommendModel,
+		temperature:    cfg.AITemperature,
+		maxTokens:      cfg.AIMaxTokens,
+		timeout:        cfg.AITimeout,
+		memory:         memory,
+		knowledge:      kb,
+		passages:       cfg.KnowledgePassages,
+		maxToolCalls:   cfg.AIMaxToolCalls,
+	}
+}
+
+// complete sends messages to model within the AI timeout and returns the
+// trimmed answer
+func (a *Assistant) complete(ctx context.Context, model string, messages []Message) (string, error) {
+	return a.send(ctx, ChatRequest{
+		Model:       model,
+		Messages:    messages,
+		Temperature: a.temperature,
+		MaxTokens:   a.maxTokens,
+	})
+}
+
+// send sends a request within the AI timeout and returns the trimmed answer
+func (a *Assistant) send(ctx context.Context, req ChatRequest) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	resp, err := a.client.Chat(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	content := strings.TrimSpace(resp.Content)
+	if content == "" {
+		return "", ErrEmptyResponse
+	}
+	return content, nil
+}
+
+// stream sends messages to model and calls onUpdate with the whole answer so
+// far each time more of it arrives. The AI timeout applies to the wait for
+// each chunk rather than to the whole answer, so long answers can finish as
+// long as the model keeps producing. Clients that cannot stream answer at once.
+func (a *Assistant) stream(ctx context.Context, model string, messages []Message, onUpdate func(text string)) (string, error) {
+	streamer, ok := a.client.(StreamingClient)
+	if !ok {
+		content, err := a.complete(ctx, model, messages)
+		if err == nil {
+			onUpdate(content)
+		}
+		return content, err
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	idle := time.AfterFunc(a.timeout, func() { cancel(context.DeadlineExceeded) })
+	defer idle.Stop()
+
+	var content strings.Builder
+	resp, err := streamer.ChatStream(ctx, ChatRequest{
+		Model:       model,
+		Messages:    messages,
+		Temperature: a.temperature,
+		MaxTokens:   a.maxTokens,
+	}, func(delta string) {
+		idle.Reset(a.timeout)
+		content.WriteString(delta)
+		onUpdate(content.String())
+	})
+	if err != nil {
+		if cause := context.Cause(ctx); cause != nil && ctx.Err() != nil {
+			return "", fmt.Errorf("%w: %w", cause, err)
+		}
+		return "", err
+	}
+
+	answer := strings.TrimSpace(resp.Content)
+	if answer == "" {
+		return "", ErrEmptyResponse
+	}
+	return answer, nil
+}
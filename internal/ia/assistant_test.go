@@ -0,0 +1,103 @@
+package ia
+
+import (
+	"blockmind/internal/config"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newStreamAssistant creates an assistant streaming from client with an AI
+// timeout of timeout
+func newStreamAssistant(client LLMClient, timeout time.Duration) *Assistant {
+	return NewAssistant(client, &config.Config{
+		LLMQAModel:  "qa",
+		AITimeout:   timeout,
+		AIMaxTokens: 100,
+	}, nil, nil)
+}
+
+// slowStream returns a handler streaming chunks with delay before each,
+// then stalling until the client gives up when stall is set
+func slowStream(chunks []string, delay time.Duration, stall bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range chunks {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+			w.Write([]byte(sseChunk(chunk, "")))
+			w.(http.Flusher).Flush()
+		}
+		if stall {
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte("data: [DONE]\n"))
+	}
+}
+
+func TestStreamOutlivesTimeoutWhileProducing(t *testing.T) {
+	// Each chunk arrives well within the timeout, the whole answer does not
+	chunks := []string{"Bitcoin ", "is ", "a ", "decentralized ", "currency. "}
+	client := newTestOpenAIClient(t, slowStream(chunks, 30*time.Millisecond, false))
+	assistant := newStreamAssistant(client, 100*time.Millisecond)
+
+	var updates []string
+	answer, err := assistant.stream(context.Background(), "qa", nil, func(text string) {
+		updates = append(updates, text)
+	})
+	if err != nil {
+		t.Fatalf("stream() error = %v", err)
+	}
+	if answer != "Bitcoin is a decentralized currency." {
+		t.Errorf("stream() = %q, want the trimmed answer", answer)
+	}
+	if len(updates) != len(chunks) || updates[1] != "Bitcoin is " {
+		t.Errorf("updates = %q, want the answer so far after each chunk", updates)
+	}
+}
+
+func TestStreamStopsWhenStalled(t *testing.T) {
+	client := newTestOpenAIClient(t, slowStream([]string{"Bitcoin ", "is "}, 0, true))
+	assistant := newStreamAssistant(client, 50*time.Millisecond)
+
+	var shown string
+	start := time.Now()
+	_, err := assistant.stream(context.Background(), "qa", nil, func(text string) {
+		shown = text
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("stream() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("stream() gave up after %v, want about the timeout", elapsed)
+	}
+	if shown != "Bitcoin is " {
+		t.Errorf("shown %q before the stall, want the chunks that arrived", shown)
+	}
+}
+
+func TestStreamMalformedChunk(t *testing.T) {
+	client := newTestOpenAIClient(t, streamLines("text/event-stream", sseChunk("Bitcoin", ""), "data: {oops"))
+	_, err := newStreamAssistant(client, time.Second).stream(context.Background(), "qa", nil, func(string) {})
+	if err == nil || errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "failed to decode stream chunk") {
+		t.Errorf("stream() error = %v, want the decoding error", err)
+	}
+}
+
+func TestStreamWithoutStreamingClient(t *testing.T) {
+	client := newScriptedClient("  Hello  ")
+	var updates []string
+	answer, err := newStreamAssistant(client, time.Second).stream(context.Background(), "qa", nil, func(text string) {
+		updates = append(updates, text)
+	})
+	if err != nil || answer != "Hello" || len(updates) != 1 || updates[0] != "Hello" {
+		t.Errorf("stream() = %q, %v with updates %q, want Hello at once", answer, err, updates)
+	}
+}
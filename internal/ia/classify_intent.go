@@ -0,0 +1,65 @@
+package ia
+
+import (
+	"blockmind/internal/intent"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// intentSystemPrompt instructs the model to classify messages for the intent router
+const intentSystemPrompt = `You classify chat messages sent to a cryptocurrency bot.
+Reply ONLY with a JSON object: {"intent": "price" | "recommend" | "help" | "none", "coins": [coin names or symbols], "currency": "currency code or empty", "confidence": number from 0 to 1}
+- price: the user wants the current price of one or more coins
+- recommend: the user asks whether to buy, sell or hold one coin
+- help: the user asks what the bot can do
+- none: anything else, including questions about how crypto works or price history`
+
+// maxIntentTokens is enough for the classification object
+const maxIntentTokens = 100
+
+// ClassifyIntent asks the model which command a free-text message is meant
+// for. It implements intent.Classifier.
+func (a *Assistant) ClassifyIntent(ctx context.Context, text string) (intent.Intent, error) {
+	content, err := a.send(ctx, ChatRequest{
+		Model: a.qaModel,
+		Messages: []Message{
+			{Role: "system", Content: intentSystemPrompt},
+			{Role: "user", Content: text},
+		},
+		Temperature: 0,
+		MaxTokens:   maxIntentTokens,
+	})
+	if err != nil {
+		return intent.Intent{}, err
+	}
+
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return intent.Intent{}, fmt.Errorf("intent classification is not JSON: %q", content)
+	}
+
+	var result struct {
+		Intent     string   `json:"intent"`
+		Coins      []string `json:"coins"`
+		Currency   string   `json:"currency"`
+		Confidence float64  `json:"confidence"`
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &result); err != nil {
+		return intent.Intent{}, fmt.Errorf("failed to decode intent classification: %w", err)
+	}
+
+	switch result.Intent {
+	case intent.Price, intent.Recommend, intent.Help:
+	default:
+		return intent.Intent{}, nil
+	}
+	return intent.Intent{
+		Command:    result.Intent,
+		Coins:      result.Coins,
+		Currency:   strings.ToLower(strings.TrimSpace(result.Currency)),
+		Confidence: result.Confidence,
+	}, nil
+}
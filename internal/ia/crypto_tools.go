@@ -0,0 +1,158 @@
+package ia
+
+import (
+	"blockmind/internal/crypto"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// coinArguments are the arguments of the crypto tools
+type coinArguments struct {
+	Coin     string `json:"coin"`
+	Currency string `json:"currency"`
+}
+
+// CryptoTools returns the tools that give the model live market data: spot
+// prices, a market snapshot and coin information
+func CryptoTools(provider crypto.MarketDataProvider, resolver *crypto.Resolver) []Tool {
+	return []Tool{
+		{
+			Name:        "get_price",
+			Description: "Current price and 24h change of a coin.",
+			Arguments:   `{"coin": "name, symbol or id", "currency": "optional currency code, default usd"}`,
+			Run: func(ctx context.Context, raw json.RawMessage) (string, error) {
+				coin, currency, err := parseCoinArguments(ctx, resolver, raw)
+				if err != nil {
+					return "", err
+				}
+
+				quotes, err := provider.SpotPrices(ctx, []crypto.Coin{coin}, []string{currency})
+				if err != nil {
+					return "", err
+				}
+				quote, ok := quotes.Get(coin.ID, currency)
+				if !ok {
+					return "", fmt.Errorf("no %s price for %s", strings.ToUpper(currency), coin)
+				}
+				result := fmt.Sprintf("%s: %s, 24h change %+.2f%%", coin, crypto.FormatPrice(quote.Price, currency), quote.Change24h)
+				if !quote.LastUpdatedAt.IsZero() {
+					result += ", updated " + quote.LastUpdatedAt.UTC().Format("2006-01-02 15:04 UTC")
+				}
+				return result, nil
+			},
+		},
+		{
+			Name:        "market_snapshot",
+			Description: "Market state of a coin: price, market cap and rank, 24h volume, high and low, all-time high.",
+			Arguments:   `{"coin": "name, symbol or id", "currency": "optional currency code, default usd"}`,
+			Run: func(ctx context.Context, raw json.RawMessage) (string, error) {
+				coin, currency, err := parseCoinArguments(ctx, resolver, raw)
+				if err != nil {
+					return "", err
+				}
+
+				snapshot, err := provider.MarketSnapshot(ctx, coin, currency)
+				if err != nil {
+					return "", err
+				}
+
+				var b strings.Builder
+				fmt.Fprintf(&b, "%s: price %s", coin, crypto.FormatPrice(snapshot.Price, currency))
+				if snapshot.MarketCap > 0 {
+					fmt.Fprintf(&b, ", market cap %s", crypto.FormatPrice(snapshot.MarketCap, currency))
+				}
+				if snapshot.MarketCapRank != nil {
+					fmt.Fprintf(&b, " (rank %d)", *snapshot.MarketCapRank)
+				}
+				fmt.Fprintf(&b, ", 24h volume %s, 24h high %s, 24h low %s, 24h change %+.2f%%",
+					crypto.FormatPrice(snapshot.Volume24h, currency), crypto.FormatPrice(snapshot.High24h, currency),
+					crypto.FormatPrice(snapshot.Low24h, currency), snapshot.PriceChangePct24h)
+				if snapshot.ATH > 0 {
+					fmt.Fprintf(&b, ", all-time high %s on %s (%+.2f%% from it)",
+						crypto.FormatPrice(snapshot.ATH, currency), snapshot.ATHDate.Format("2006-01-02"), snapshot.ATHChangePct)
+				}
+				return b.String(), nil
+			},
+		},
+		{
+			Name:        "coin_info",
+			Description: "Description, categories, genesis date and long-term price changes of a coin.",
+			Arguments:   `{"coin": "name, symbol or id"}`,
+			Run: func(ctx context.Context, raw json.RawMessage) (string, error) {
+				coin, _, err := parseCoinArguments(ctx, resolver, raw)
+				if err != nil {
+					return "", err
+				}
+
+				info, err := provider.CoinInfo(ctx, coin)
+				if errors.Is(err, crypto.ErrNotSupported) {
+					return "", errors.New("coin information is not available from the current data provider")
+				}
+				if err != nil {
+					return "", err
+				}
+
+				var b strings.Builder
+				b.WriteString(coin.String())
+				if info.Description != "" {
+					fmt.Fprintf(&b, ": %s", truncate(info.Description, 500))
+				}
+				if len(info.Categories) > 0 {
+					fmt.Fprintf(&b, " Categories: %s.", strings.Join(info.Categories, ", "))
+				}
+				if info.GenesisDate != "" {
+					fmt.Fprintf(&b, " Genesis date: %s.", info.GenesisDate)
+				}
+				for _, period := range []string{"7d", "30d", "1y"} {
+					if change, ok := info.PriceChangePct[period]; ok {
+						fmt.Fprintf(&b, " %s change: %+.2f%%.", period, change)
+					}
+				}
+				return b.String(), nil
+			},
+		},
+	}
+}
+
+// parseCoinArguments decodes the arguments of a crypto tool and resolves the
+// coin. The currency defaults to usd.
+func parseCoinArguments(ctx context.Context, resolver *crypto.Resolver, raw json.RawMessage) (crypto.Coin, string, error) {
+	var args coinArguments
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return crypto.Coin{}, "", fmt.Errorf("invalid arguments: %v", err)
+	}
+	if strings.TrimSpace(args.Coin) == "" {
+		return crypto.Coin{}, "", errors.New(`the "coin" argument is required`)
+	}
+
+	currency := strings.ToLower(strings.TrimSpace(args.Currency))
+	if currency == "" {
+		currency = "usd"
+	}
+
+	coin, err := resolver.Resolve(ctx, args.Coin)
+	var ambiguous *crypto.AmbiguousCoinError
+	if errors.As(err, &ambiguous) {
+		ids := make([]string, len(ambiguous.Candidates))
+		for i, candidate := range ambiguous.Candidates {
+			ids[i] = fmt.Sprintf("%s with id %s", candidate, candidate.ID)
+		}
+		return crypto.Coin{}, "", fmt.Errorf("%w; call again with one of: %s", err, strings.Join(ids, "; "))
+	}
+	return coin, currency, err
+}
+
+// truncate shortens text to at most n bytes, ending at a word boundary
+func truncate(text string, n int) string {
+	text = strings.TrimSpace(text)
+	if len(text) <= n {
+		return text
+	}
+	if cut := strings.LastIndex(text[:n], " "); cut > 0 {
+		n = cut
+	}
+	return text[:n] + "…"
+}
@@ -0,0 +1,55 @@
+package ia
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sentinel errors returned (wrapped in an *APIError) for common LLM failures
+var (
+	ErrUnauthorized = errors.New("llm: invalid or missing API key")
+	ErrRateLimited  = errors.New("llm: rate limit exceeded")
+	ErrModelLoading = errors.New("llm: model is loading")
+	ErrUnavailable  = errors.New("llm: service unavailable")
+	ErrBadRequest   = errors.New("llm: bad request")
+)
+
+// ErrEmptyResponse is returned when a completion contains no answer
+var ErrEmptyResponse = errors.New("llm: response contains no completion")
+
+// APIError describes a non-2xx response from an LLM endpoint
+type APIError struct {
+	StatusCode int
+	Message    string
+	// RetryAfter is how long the server asked to wait, when it said so. For a
+	// loading model it is the estimated loading time.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("llm: status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("llm: status %d: %s", e.StatusCode, e.Message)
+}
+
+// Unwrap maps the status code to one of the sentinel errors so callers can use errors.Is
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode == http.StatusServiceUnavailable && strings.Contains(strings.ToLower(e.Message), "loading"):
+		return ErrModelLoading
+	case e.StatusCode >= 500:
+		return ErrUnavailable
+	case e.StatusCode >= 400:
+		return ErrBadRequest
+	}
+	return nil
+}
@@ -1,132 +1,105 @@
 package ia
 
 import (
-	"blockmind/internal/config"
-	"bytes"
+	"blockmind/internal/i18n"
+	"blockmind/internal/logger"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"time"
 )
 
-func AskInvestData(question string, cfg *config.Config) (string, error) {
-	// Create a context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.AITimeout)
-	defer cancel()
+// maxRecommendationAttempts is the number of answers requested before falling
+// back, the first one included
+const maxRecommendationAttempts = 2
 
-	// Improved prompt structure with clear separation of instructions
-	systemPrompt := `You are a precise cryptocurrency investment system.
+// minRecommendationTokens leaves room for the whole JSON object even when
+// AI_MAX_TOKENS is set low for chat answers
+const minRecommendationTokens = 400
+
+// recommendSystemPrompt instructs the model to answer with JSON only
+const recommendSystemPrompt = `You are a precise cryptocurrency investment system.
+Rules:
+1. Respond ONLY with a JSON object, no markdown, code fences or extra text
+2. Write the string values in %s
+3. Base the analysis only on the data given`
+
+// investSystemPrompt instructs the model for market analysis
+const investSystemPrompt = `You are a precise cryptocurrency investment system.
 Rules:
 1. Respond ONLY with the answer, no extra text or formatting
 2. Match the question's language exactly
 3. Never use markdown or special characters
 4. Stop generation immediately after answer`
 
-	userPrompt := fmt.Sprintf("Question: %s", question)
-
-	requestBody := map[string]interface{}{
-		"model": cfg.HuggingFaceModel,
-		"messages": []Message{
-			{Role: "system", Content: systemPrompt},
-			{Role: "user", Content: userPrompt},
-		},
-		"temperature": cfg.AITemperature,
-		"max_tokens":  cfg.AIMaxTokens,
-	}
-
-	body, err := json.Marshal(requestBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to encode request body: %w", err)
-	}
-
-	// Create HTTP request with context
-	req, err := http.NewRequestWithContext(
-		ctx,
-		"POST",
-		cfg.GetHuggingFaceAPIURL(),
-		bytes.NewBuffer(body),
-	)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Authorization", "Bearer "+cfg.HuggingFaceAPIKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Send request with custom client and timeout
-	client := &http.Client{
-		Timeout: cfg.AITimeout - 1*time.Second, // Slightly less than context timeout
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("API request failed: %w", err)
-	}
-	defer resp.Body.Close()
+func (a *Assistant) AskInvestData(ctx context.Context, question string) (string, error) {
+	return a.complete(ctx, a.recommendModel, []Message{
+		{Role: "system", Content: investSystemPrompt},
+		{Role: "user", Content: fmt.Sprintf("Question: %s", question)},
+	})
+}
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
-	}
+// GetInvestmentRecommendation asks the model for a structured recommendation.
+// An answer that does not match the schema is sent back once with the problem
+// so the model can repair it; if that fails too, a neutral fallback is
+// returned. Errors are only returned when the model cannot be reached.
+func (a *Assistant) GetInvestmentRecommendation(ctx context.Context, crypto, data string) (Recommendation, error) {
+	locale := i18n.FromContext(ctx)
+	prompt := fmt.Sprintf(`
+Analyze the cryptocurrency %s for investment potential. Consider:
+1. Price trends (30d, 90d)
+2. Market sentiment
+3. Technical indicators (RSI, MACD, moving averages, Bollinger Bands, volatility)
+4. Project fundamentals
+5. Regulatory environment
+Use only the indicator values given in the data; never estimate indicators yourself.
+Reply with a single JSON object and nothing else, matching this schema:
+%s
+Here is the data to consider:
+%s
+`, crypto, recommendationSchema, data)
 
-	// Read response
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API request failed with status %s: %s", resp.Status, string(respBody))
+	messages := []Message{
+		{Role: "system", Content: fmt.Sprintf(recommendSystemPrompt, i18n.Name(locale))},
+		{Role: "user", Content: prompt},
 	}
 
-	var response map[string]interface{}
-	if err := json.Unmarshal(respBody, &response); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
-	}
+	for attempt := 1; attempt <= maxRecommendationAttempts; attempt++ {
+		content, err := a.send(ctx, ChatRequest{
+			Model:       a.recommendModel,
+			Messages:    messages,
+			Temperature: a.temperature,
+			MaxTokens:   max(a.maxTokens, minRecommendationTokens),
+		})
+		if err != nil && !errors.Is(err, ErrEmptyResponse) {
+			return Recommendation{}, err
+		}
 
-	// Extract answer using a more robust approach with type assertions
-	if choices, ok := response["choices"].([]interface{}); ok && len(choices) > 0 {
-		if choice, ok := choices[0].(map[string]interface{}); ok {
-			if message, ok := choice["message"].(map[string]interface{}); ok {
-				if content, ok := message["content"].(string); ok {
-					return content, nil
-				}
-			}
+		rec, err := parseRecommendation(content)
+		if err == nil {
+			return rec, nil
 		}
-	}
 
-	// Try to extract the answer from different possible keys
-	if answer, ok := response["answer"].(string); ok {
-		return answer, nil
-	}
+		logger.Warn("Invalid recommendation from model",
+			logger.Field{Key: "attempt", Value: attempt},
+			logger.Field{Key: "error", Value: err.Error()})
 
-	// Some models might return an array of answers
-	if answers, ok := response["answers"].([]interface{}); ok && len(answers) > 0 {
-		if firstAnswer, ok := answers[0].(map[string]interface{}); ok {
-			if text, ok := firstAnswer["answer"].(string); ok {
-				return text, nil
-			}
-		}
+		messages = append(messages,
+			Message{Role: "assistant", Content: content},
+			Message{Role: "user", Content: fmt.Sprintf("That reply is not valid (%v). Reply again with only the JSON object matching the schema.", err)},
+		)
 	}
 
-	// If we can't find a structured answer, return a generic message
-	return "I couldn't understand the response from the AI service.", nil
+	return fallbackRecommendation(locale), nil
 }
 
-func GetInvestmentRecommendation(crypto, data string, cfg *config.Config) (string, error) {
+func (a *Assistant) GetMarketCommentary(ctx context.Context, digest string) (string, error) {
 	prompt := fmt.Sprintf(`
-Analyze the cryptocurrency %s for investment potential. Consider:
-1. Price trends (30d, 90d)
-2. Market sentiment
-3. Technical indicators (RSI, MACD)
-4. Project fundamentals
-5. Regulatory environment
-Reply with "Buy/Hold/Sell" + 1-line reasoning.
-Here is the data to consider:
+Write a short market commentary (at most 2 sentences) for this crypto market digest.
+Mention only facts present in the digest; do not give investment advice.
+Write it in %s.
+Here is the digest:
 %s
-`, crypto, data)
+`, i18n.Name(i18n.FromContext(ctx)), digest)
 
-	response, err := AskInvestData(prompt, cfg)
-	if err != nil {
-		return "", err
-	}
-	return fmt.Sprintf("Recommendation for %s: %s", crypto, response), nil
+	return a.AskInvestData(ctx, prompt)
 }
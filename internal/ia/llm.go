@@ -0,0 +1,55 @@
+package ia
+
+import (
+	"blockmind/internal/config"
+	"context"
+)
+
+// Message represents a chat message
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatRequest is a chat completion request
+type ChatRequest struct {
+	Model       string
+	Messages    []Message
+	Temperature float64
+	MaxTokens   int
+	Stop        []string
+}
+
+// ChatResponse is the answer to a chat completion request
+type ChatResponse struct {
+	Content      string
+	FinishReason string
+}
+
+// LLMClient sends chat completion requests to a language model
+type LLMClient interface {
+	Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error)
+}
+
+// StreamingClient is implemented by LLM clients that can stream completions.
+// onDelta receives each piece of content as it arrives; the returned response
+// holds the whole content.
+type StreamingClient interface {
+	ChatStream(ctx context.Context, req ChatRequest, onDelta func(delta string)) (*ChatResponse, error)
+}
+
+// NewLLMClient creates the client of the backend selected in the configuration
+func NewLLMClient(cfg *config.Config) LLMClient {
+	switch cfg.LLMBackend {
+	case "openai":
+		return NewOpenAIClient(cfg.LLMBaseURL, cfg.LLMAPIKey, nil)
+	case "ollama":
+		return NewOllamaClient(cfg.LLMBaseURL, cfg.LLMAPIKey, nil)
+	}
+
+	baseURL := cfg.HuggingFaceAPIURL
+	if cfg.LLMBaseURL != "" {
+		baseURL = cfg.LLMBaseURL
+	}
+	return NewHuggingFaceClient(baseURL, cfg.HuggingFaceAPIKey, nil)
+}
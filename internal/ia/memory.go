@@ -0,0 +1,140 @@
+package ia
+
+import (
+	"sync"
+	"time"
+)
+
+// charsPerToken approximates how many characters make up one token. It is
+// only used to keep the history within budget, so a rough estimate is enough.
+const charsPerToken = 4
+
+// turn is one question and its answer
+type turn struct {
+	question Message
+	answer   Message
+	tokens   int
+}
+
+// conversation is the recent history of one chat
+type conversation struct {
+	turns    []turn
+	lastUsed time.Time
+}
+
+// ConversationMemory keeps the recent Q&A history of each chat so follow-up
+// questions keep their context. Histories are bounded by a number of turns and
+// an estimated token budget, and are forgotten after ttl without activity.
+type ConversationMemory struct {
+	mu        sync.Mutex
+	chats     map[string]*conversation
+	maxTurns  int
+	maxTokens int
+	ttl       time.Duration
+	lastSweep time.Time
+}
+
+// NewConversationMemory creates a new conversation memory
+func NewConversationMemory(maxTurns, maxTokens int, ttl time.Duration) *ConversationMemory {
+	return &ConversationMemory{
+		chats:     make(map[string]*conversation),
+		maxTurns:  maxTurns,
+		maxTokens: maxTokens,
+		ttl:       ttl,
+		lastSweep: time.Now(),
+	}
+}
+
+// History returns the prior messages of a chat, oldest first
+func (m *ConversationMemory) History(chatID string) []Message {
+	if m == nil || chatID == "" {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	chat, ok := m.chats[chatID]
+	if !ok {
+		return nil
+	}
+	if time.Since(chat.lastUsed) > m.ttl {
+		delete(m.chats, chatID)
+		return nil
+	}
+
+	messages := make([]Message, 0, 2*len(chat.turns))
+	for _, t := range chat.turns {
+		messages = append(messages, t.question, t.answer)
+	}
+	return messages
+}
+
+// Add records a question and its answer, dropping the oldest turns that no
+// longer fit the turn and token limits
+func (m *ConversationMemory) Add(chatID string, question, answer Message) {
+	if m == nil || chatID == "" || m.maxTurns <= 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.sweep(now)
+
+	chat, ok := m.chats[chatID]
+	if !ok || now.Sub(chat.lastUsed) > m.ttl {
+		chat = &conversation{}
+		m.chats[chatID] = chat
+	}
+
+	chat.turns = append(chat.turns, turn{
+		question: question,
+		answer:   answer,
+		tokens:   estimateTokens(question.Content) + estimateTokens(answer.Content),
+	})
+	chat.lastUsed = now
+
+	total := 0
+	for _, t := range chat.turns {
+		total += t.tokens
+	}
+	for len(chat.turns) > m.maxTurns || (total > m.maxTokens && len(chat.turns) > 0) {
+		total -= chat.turns[0].tokens
+		chat.turns = chat.turns[1:]
+	}
+	if len(chat.turns) == 0 {
+		delete(m.chats, chatID)
+	}
+}
+
+// Reset forgets the history of a chat
+func (m *ConversationMemory) Reset(chatID string) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.chats, chatID)
+}
+
+// sweep drops expired chats at most once per ttl so idle chats do not
+// accumulate. The caller must hold the lock.
+func (m *ConversationMemory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < m.ttl {
+		return
+	}
+	for id, chat := range m.chats {
+		if now.Sub(chat.lastUsed) > m.ttl {
+			delete(m.chats, id)
+		}
+	}
+	m.lastSweep = now
+}
+
+// estimateTokens approximates the number of tokens in text
+func estimateTokens(text string) int {
+	return len(text)/charsPerToken + 1
+}
@@ -0,0 +1,156 @@
+package ia
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// OllamaClient talks to the native chat API of an Ollama server
+type OllamaClient struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewOllamaClient creates a client for the Ollama server at baseURL, e.g.
+// http://localhost:11434. The API key is only sent when set, for servers
+// behind an authenticating proxy. If httpClient is nil, http.DefaultClient is used.
+func NewOllamaClient(baseURL, apiKey string, httpClient *http.Client) *OllamaClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &OllamaClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: httpClient,
+	}
+}
+
+// ollamaChatRequest is the body of POST /api/chat
+type ollamaChatRequest struct {
+	Model    string        `json:"model"`
+	Messages []Message     `json:"messages"`
+	Stream   bool          `json:"stream"`
+	Options  ollamaOptions `json:"options"`
+}
+
+// ollamaOptions are the model parameters of an Ollama request
+type ollamaOptions struct {
+	Temperature float64  `json:"temperature"`
+	NumPredict  int      `json:"num_predict,omitempty"`
+	Stop        []string `json:"stop,omitempty"`
+}
+
+// ollamaChatResponse is the body of a non-streaming /api/chat response, and
+// each line of a streaming one
+type ollamaChatResponse struct {
+	Message    Message `json:"message"`
+	Done       bool    `json:"done"`
+	DoneReason string  `json:"done_reason"`
+	Error      string  `json:"error"`
+}
+
+// Chat sends a chat request
+func (c *OllamaClient) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	httpReq, err := c.newRequest(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := doRequest(c.httpClient, httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	var chat ollamaChatResponse
+	if err := json.Unmarshal(respBody, &chat); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if chat.Message.Content == "" {
+		return nil, ErrEmptyResponse
+	}
+	return &ChatResponse{Content: chat.Message.Content, FinishReason: chat.DoneReason}, nil
+}
+
+// ChatStream sends a streaming chat request and calls onDelta with each piece
+// of content as it arrives. Ollama streams one JSON object per line.
+func (c *OllamaClient) ChatStream(ctx context.Context, req ChatRequest, onDelta func(delta string)) (*ChatResponse, error) {
+	httpReq, err := c.newRequest(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := doStream(c.httpClient, httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var content strings.Builder
+	result := &ChatResponse{}
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLine)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk ollamaChatResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return nil, fmt.Errorf("failed to decode stream chunk: %w", err)
+		}
+		if chunk.Error != "" {
+			return nil, &APIError{StatusCode: http.StatusInternalServerError, Message: chunk.Error}
+		}
+		if chunk.Message.Content != "" {
+			content.WriteString(chunk.Message.Content)
+			onDelta(chunk.Message.Content)
+		}
+		if chunk.Done {
+			result.FinishReason = chunk.DoneReason
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	if content.Len() == 0 {
+		return nil, ErrEmptyResponse
+	}
+	result.Content = content.String()
+	return result, nil
+}
+
+// newRequest builds the HTTP request of a chat
+func (c *OllamaClient) newRequest(ctx context.Context, req ChatRequest, stream bool) (*http.Request, error) {
+	body, err := json.Marshal(ollamaChatRequest{
+		Model:    req.Model,
+		Messages: req.Messages,
+		Stream:   stream,
+		Options: ollamaOptions{
+			Temperature: req.Temperature,
+			NumPredict:  req.MaxTokens,
+			Stop:        req.Stop,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request body: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	return httpReq, nil
+}
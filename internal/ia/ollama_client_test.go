@@ -0,0 +1,104 @@
+package ia
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestOllamaClient returns a client talking to a test server running handler
+func newTestOllamaClient(t *testing.T, handler http.HandlerFunc) *OllamaClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewOllamaClient(server.URL+"/", "", server.Client())
+}
+
+// ollamaChunk is a line of a streamed Ollama chat carrying content
+func ollamaChunk(content string) string {
+	return `{"model": "test", "message": {"role": "assistant", "content": "` + content + `"}, "done": false}`
+}
+
+func TestOllamaChat(t *testing.T) {
+	client := newTestOllamaClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body ollamaChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || r.URL.Path != "/api/chat" || body.Stream || body.Options.NumPredict != 10 {
+			t.Errorf("request to %s = %+v, %v", r.URL.Path, body, err)
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Error("Authorization sent without an API key")
+		}
+		w.Write([]byte(`{"message": {"role": "assistant", "content": "Hello"}, "done": true, "done_reason": "stop"}`))
+	})
+
+	resp, err := client.Chat(context.Background(), testChatRequest)
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if resp.Content != "Hello" || resp.FinishReason != "stop" {
+		t.Errorf("Chat() = %+v, want Hello", resp)
+	}
+}
+
+func TestOllamaChatStream(t *testing.T) {
+	client := newTestOllamaClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body ollamaChatRequest
+		if json.NewDecoder(r.Body).Decode(&body); !body.Stream {
+			t.Errorf("request = %+v, want a stream", body)
+		}
+		streamLines("application/x-ndjson",
+			ollamaChunk("Hel"),
+			"",
+			ollamaChunk("lo"),
+			`{"model": "test", "message": {"role": "assistant", "content": "!"}, "done": true, "done_reason": "length"}`,
+			// Nothing after the done line is read
+			ollamaChunk(" ignored"),
+		)(w, r)
+	})
+
+	var deltas []string
+	resp, err := client.ChatStream(context.Background(), testChatRequest, func(delta string) {
+		deltas = append(deltas, delta)
+	})
+	if err != nil {
+		t.Fatalf("ChatStream() error = %v", err)
+	}
+	if resp.Content != "Hello!" || resp.FinishReason != "length" {
+		t.Errorf("ChatStream() = %+v, want Hello! finished by length", resp)
+	}
+	if strings.Join(deltas, "|") != "Hel|lo|!" {
+		t.Errorf("deltas = %q, want each piece once", deltas)
+	}
+}
+
+func TestOllamaChatStreamErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{"malformed chunk", []string{ollamaChunk("Hel"), `{"message": `}, "failed to decode stream chunk"},
+		{"error line", []string{ollamaChunk("Hel"), `{"error": "model runner has unexpectedly stopped"}`},
+			"llm: status 500: model runner has unexpectedly stopped"},
+		{"no content", []string{`{"message": {"content": ""}, "done": true}`}, ErrEmptyResponse.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestOllamaClient(t, streamLines("application/x-ndjson", tt.lines...))
+			_, err := client.ChatStream(context.Background(), testChatRequest, func(string) {})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ChatStream() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	// An error line is an unavailable service
+	client := newTestOllamaClient(t, streamLines("application/x-ndjson", `{"error": "out of memory"}`))
+	if _, err := client.ChatStream(context.Background(), testChatRequest, func(string) {}); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("ChatStream() error = %v, want ErrUnavailable", err)
+	}
+}
@@ -0,0 +1,277 @@
+package ia
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrorBody limits how much of an error response is kept in an APIError
+const maxErrorBody = 300
+
+// maxStreamLine is the longest line accepted in a streamed response
+const maxStreamLine = 1024 * 1024
+
+// OpenAIClient talks to OpenAI-compatible chat completion endpoints such as
+// the Hugging Face router, llama.cpp server, vLLM or Ollama's OpenAI API
+type OpenAIClient struct {
+	// endpoint returns the chat completions URL for a model
+	endpoint   func(model string) string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewOpenAIClient creates a client for an OpenAI-compatible server whose API
+// lives at baseURL, e.g. http://localhost:8080/v1. If httpClient is nil,
+// http.DefaultClient is used; deadlines come from the request context.
+func NewOpenAIClient(baseURL, apiKey string, httpClient *http.Client) *OpenAIClient {
+	endpoint := strings.TrimRight(baseURL, "/") + "/chat/completions"
+	return newOpenAIClient(func(string) string { return endpoint }, apiKey, httpClient)
+}
+
+// NewHuggingFaceClient creates a client for the Hugging Face inference router,
+// which serves each model under its own path below baseURL
+func NewHuggingFaceClient(baseURL, apiKey string, httpClient *http.Client) *OpenAIClient {
+	return newOpenAIClient(func(model string) string {
+		return baseURL + model + "/v1/chat/completions"
+	}, apiKey, httpClient)
+}
+
+func newOpenAIClient(endpoint func(model string) string, apiKey string, httpClient *http.Client) *OpenAIClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &OpenAIClient{
+		endpoint:   endpoint,
+		apiKey:     apiKey,
+		httpClient: httpClient,
+	}
+}
+
+// chatCompletionRequest is the OpenAI chat completion request body
+type chatCompletionRequest struct {
+	Model       string    `json:"model"`
+	Messages    []Message `json:"messages"`
+	Temperature float64   `json:"temperature"`
+	MaxTokens   int       `json:"max_tokens,omitempty"`
+	Stop        []string  `json:"stop,omitempty"`
+	Stream      bool      `json:"stream,omitempty"`
+}
+
+// chatCompletionResponse is the OpenAI chat completion response body. Answer
+// and Answers cover older question-answering models.
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message      Message `json:"message"`
+		FinishReason string  `json:"finish_reason"`
+	} `json:"choices"`
+	Answer  string `json:"answer"`
+	Answers []struct {
+		Answer string `json:"answer"`
+	} `json:"answers"`
+}
+
+// chatCompletionChunk is one server-sent event of a streaming completion
+type chatCompletionChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+// Chat sends a chat completion request
+func (c *OpenAIClient) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	httpReq, err := c.newRequest(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := doRequest(c.httpClient, httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	var completion chatCompletionResponse
+	if err := json.Unmarshal(respBody, &completion); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	switch {
+	case len(completion.Choices) > 0:
+		return &ChatResponse{
+			Content:      completion.Choices[0].Message.Content,
+			FinishReason: completion.Choices[0].FinishReason,
+		}, nil
+	case completion.Answer != "":
+		return &ChatResponse{Content: completion.Answer}, nil
+	case len(completion.Answers) > 0 && completion.Answers[0].Answer != "":
+		return &ChatResponse{Content: completion.Answers[0].Answer}, nil
+	}
+	return nil, ErrEmptyResponse
+}
+
+// ChatStream sends a streaming chat completion request and calls onDelta with
+// each piece of content as it arrives over server-sent events
+func (c *OpenAIClient) ChatStream(ctx context.Context, req ChatRequest, onDelta func(delta string)) (*ChatResponse, error) {
+	httpReq, err := c.newRequest(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	resp, err := doStream(c.httpClient, httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var content strings.Builder
+	result := &ChatResponse{}
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLine)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue // blank separators, comments and other SSE fields
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk chatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("failed to decode stream chunk: %w", err)
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		if delta := chunk.Choices[0].Delta.Content; delta != "" {
+			content.WriteString(delta)
+			onDelta(delta)
+		}
+		if reason := chunk.Choices[0].FinishReason; reason != nil {
+			result.FinishReason = *reason
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	if content.Len() == 0 {
+		return nil, ErrEmptyResponse
+	}
+	result.Content = content.String()
+	return result, nil
+}
+
+// newRequest builds the HTTP request of a chat completion
+func (c *OpenAIClient) newRequest(ctx context.Context, req ChatRequest, stream bool) (*http.Request, error) {
+	body, err := json.Marshal(chatCompletionRequest{
+		Model:       req.Model,
+		Messages:    req.Messages,
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+		Stop:        req.Stop,
+		Stream:      stream,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request body: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(req.Model), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	return httpReq, nil
+}
+
+// doRequest sends a request and returns the body of a successful response,
+// or an *APIError for non-2xx statuses
+func doRequest(httpClient *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, newAPIError(resp, body)
+	}
+	return body, nil
+}
+
+// doStream sends a request and returns the response of a successful request
+// for the caller to read and close, or an *APIError for non-2xx statuses
+func doStream(httpClient *http.Client, req *http.Request) (*http.Response, error) {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxStreamLine))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		return nil, newAPIError(resp, body)
+	}
+	return resp, nil
+}
+
+// newAPIError builds an APIError from an error response, reading the message
+// and the wait hints of OpenAI-style and Hugging Face-style bodies
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+
+	var payload struct {
+		Error         json.RawMessage `json:"error"`
+		EstimatedTime float64         `json:"estimated_time"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && len(payload.Error) > 0 {
+		// Hugging Face sends a string, OpenAI an object with a message
+		var text string
+		var object struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(payload.Error, &text) == nil {
+			apiErr.Message = text
+		} else if json.Unmarshal(payload.Error, &object) == nil {
+			apiErr.Message = object.Message
+		}
+		if payload.EstimatedTime > 0 {
+			apiErr.RetryAfter = time.Duration(payload.EstimatedTime * float64(time.Second))
+		}
+	}
+
+	if apiErr.Message == "" {
+		apiErr.Message = strings.TrimSpace(string(body))
+		if len(apiErr.Message) > maxErrorBody {
+			apiErr.Message = apiErr.Message[:maxErrorBody]
+		}
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && apiErr.RetryAfter == 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return apiErr
+}
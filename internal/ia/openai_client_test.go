@@ -0,0 +1,241 @@
+package ia
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestOpenAIClient returns a client talking to a test server running handler
+func newTestOpenAIClient(t *testing.T, handler http.HandlerFunc) *OpenAIClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewOpenAIClient(server.URL+"/v1/", "secret", server.Client())
+}
+
+// testChatRequest is a short chat request to model "test"
+var testChatRequest = ChatRequest{
+	Model:     "test",
+	Messages:  []Message{{Role: "user", Content: "Hi"}},
+	MaxTokens: 10,
+}
+
+func TestOpenAIChat(t *testing.T) {
+	client := newTestOpenAIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" || r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("request to %s with %q, want the completions endpoint with the key", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		var body chatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Model != "test" || body.MaxTokens != 10 || body.Stream {
+			t.Errorf("request body = %+v, %v", body, err)
+		}
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "Hello"}, "finish_reason": "stop"}]}`))
+	})
+
+	resp, err := client.Chat(context.Background(), testChatRequest)
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if resp.Content != "Hello" || resp.FinishReason != "stop" {
+		t.Errorf("Chat() = %+v, want Hello", resp)
+	}
+}
+
+func TestOpenAIChatAnswers(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+		err  error
+	}{
+		{`{"answer": "Hello"}`, "Hello", nil},
+		{`{"answers": [{"answer": "Hello"}]}`, "Hello", nil},
+		{`{"choices": []}`, "", ErrEmptyResponse},
+		{`{"answers": [{"answer": ""}]}`, "", ErrEmptyResponse},
+	}
+	for _, tt := range tests {
+		client := newTestOpenAIClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(tt.body))
+		})
+		resp, err := client.Chat(context.Background(), testChatRequest)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Chat() with %s error = %v, want %v", tt.body, err, tt.err)
+			continue
+		}
+		if err == nil && resp.Content != tt.want {
+			t.Errorf("Chat() with %s = %q, want %q", tt.body, resp.Content, tt.want)
+		}
+	}
+}
+
+func TestHuggingFaceEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/org/model/v1/chat/completions" {
+			t.Errorf("path = %q, want the model's completions endpoint", r.URL.Path)
+		}
+		w.Write([]byte(`{"choices": [{"message": {"content": "Hello"}}]}`))
+	}))
+	defer server.Close()
+
+	request := testChatRequest
+	request.Model = "org/model"
+	client := NewHuggingFaceClient(server.URL+"/models/", "secret", server.Client())
+	if _, err := client.Chat(context.Background(), request); err != nil {
+		t.Errorf("Chat() error = %v", err)
+	}
+}
+
+func TestOpenAIChatAPIErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		body       string
+		want       error
+		message    string
+		wait       time.Duration
+	}{
+		{"hugging face model loading", http.StatusServiceUnavailable, "",
+			`{"error": "Model org/model is currently loading", "estimated_time": 20.5}`,
+			ErrModelLoading, "Model org/model is currently loading", 20500 * time.Millisecond},
+		{"estimated time before Retry-After", http.StatusServiceUnavailable, "5",
+			`{"error": "Model is loading", "estimated_time": 30}`, ErrModelLoading, "Model is loading", 30 * time.Second},
+		{"hugging face string error", http.StatusBadRequest, "",
+			`{"error": "Model not supported"}`, ErrBadRequest, "Model not supported", 0},
+		{"openai object error", http.StatusUnauthorized, "",
+			`{"error": {"message": "Incorrect API key provided", "type": "invalid_request_error", "code": "invalid_api_key"}}`,
+			ErrUnauthorized, "Incorrect API key provided", 0},
+		{"rate limited with Retry-After", http.StatusTooManyRequests, "7",
+			`{"error": {"message": "Rate limit reached"}}`, ErrRateLimited, "Rate limit reached", 7 * time.Second},
+		{"Retry-After as a date", http.StatusTooManyRequests, "Wed, 21 Oct 2026 07:28:00 GMT", "", ErrRateLimited, "", 0},
+		{"forbidden", http.StatusForbidden, "", `{"error": {"code": 403}}`, ErrUnauthorized, `{"error": {"code": 403}}`, 0},
+		{"unavailable without loading", http.StatusServiceUnavailable, "", `{"error": "Overloaded"}`, ErrUnavailable, "Overloaded", 0},
+		{"html error page", http.StatusBadGateway, "", "\n<html>Bad Gateway</html>\n", ErrUnavailable, "<html>Bad Gateway</html>", 0},
+		{"long body", http.StatusInternalServerError, "", strings.Repeat("x", 2*maxErrorBody), ErrUnavailable, strings.Repeat("x", maxErrorBody), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestOpenAIClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := client.Chat(context.Background(), testChatRequest)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Chat() error = %v, want %v", err, tt.want)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Chat() error = %T, want *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Message != tt.message {
+				t.Errorf("APIError = %+v, want status %d and message %q", apiErr, tt.status, tt.message)
+			}
+			if apiErr.RetryAfter != tt.wait {
+				t.Errorf("RetryAfter = %v, want %v", apiErr.RetryAfter, tt.wait)
+			}
+		})
+	}
+}
+
+// streamLines returns a handler writing lines one by one, flushed, as a
+// streamed response of contentType
+func streamLines(contentType string, lines ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		for _, line := range lines {
+			w.Write([]byte(line + "\n"))
+			w.(http.Flusher).Flush()
+		}
+	}
+}
+
+// sseChunk is a server-sent event carrying delta, finishing with reason when
+// it is set
+func sseChunk(delta, reason string) string {
+	finish := "null"
+	if reason != "" {
+		finish = `"` + reason + `"`
+	}
+	return `data: {"choices": [{"delta": {"content": "` + delta + `"}, "finish_reason": ` + finish + `}]}` + "\n"
+}
+
+func TestOpenAIChatStream(t *testing.T) {
+	client := newTestOpenAIClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body chatCompletionRequest
+		if json.NewDecoder(r.Body).Decode(&body); !body.Stream || r.Header.Get("Accept") != "text/event-stream" {
+			t.Errorf("request = %+v accepting %q, want a stream", body, r.Header.Get("Accept"))
+		}
+		streamLines("text/event-stream",
+			": keep-alive",
+			`data: {"choices": [{"delta": {"role": "assistant"}, "finish_reason": null}]}`,
+			sseChunk("Hel", ""),
+			"event: message",
+			"data:"+strings.TrimPrefix(sseChunk("lo", ""), "data: "),
+			`data: {"choices": []}`,
+			sseChunk("!", "stop"),
+			"data: [DONE]",
+			// Nothing after [DONE] is read
+			sseChunk(" ignored", ""),
+		)(w, r)
+	})
+
+	var deltas []string
+	resp, err := client.ChatStream(context.Background(), testChatRequest, func(delta string) {
+		deltas = append(deltas, delta)
+	})
+	if err != nil {
+		t.Fatalf("ChatStream() error = %v", err)
+	}
+	if resp.Content != "Hello!" || resp.FinishReason != "stop" {
+		t.Errorf("ChatStream() = %+v, want Hello! finished by stop", resp)
+	}
+	if strings.Join(deltas, "|") != "Hel|lo|!" {
+		t.Errorf("deltas = %q, want each piece once", deltas)
+	}
+}
+
+func TestOpenAIChatStreamErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    string
+	}{
+		{"malformed chunk", streamLines("text/event-stream", sseChunk("Hel", ""), `data: {"choices": [`), "failed to decode stream chunk"},
+		{"no content", streamLines("text/event-stream", sseChunk("", "stop"), "data: [DONE]"), ErrEmptyResponse.Error()},
+		{"line too long", streamLines("text/event-stream", "data: "+strings.Repeat("x", maxStreamLine)), "failed to read stream"},
+		{"error status", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error": {"message": "Rate limit reached"}}`))
+		}, "llm: status 429: Rate limit reached"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestOpenAIClient(t, tt.handler)
+			_, err := client.ChatStream(context.Background(), testChatRequest, func(string) {})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ChatStream() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAIChatUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	client := NewOpenAIClient(server.URL, "", server.Client())
+	server.Close()
+
+	_, err := client.Chat(context.Background(), testChatRequest)
+	var apiErr *APIError
+	if err == nil || errors.As(err, &apiErr) {
+		t.Errorf("Chat() to a closed server error = %v, want a request error", err)
+	}
+}
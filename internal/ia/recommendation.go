@@ -0,0 +1,193 @@
+package ia
+
+import (
+	"blockmind/internal/i18n"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Verdicts of a recommendation
+const (
+	VerdictBuy  = "buy"
+	VerdictHold = "hold"
+	VerdictSell = "sell"
+)
+
+// Time horizons of a recommendation
+const (
+	HorizonShort  = "short"
+	HorizonMedium = "medium"
+	HorizonLong   = "long"
+)
+
+// Limits of the recommendation schema
+const (
+	maxKeyRisks       = 5
+	maxReasoningChars = 600
+)
+
+// ErrInvalidRecommendation is returned when the model's answer does not match
+// the recommendation schema
+var ErrInvalidRecommendation = errors.New("llm: invalid recommendation")
+
+// Recommendation is the structured answer of a recommendation request
+type Recommendation struct {
+	Verdict     string   `json:"verdict"`
+	Confidence  int      `json:"confidence"`
+	TimeHorizon string   `json:"time_horizon"`
+	KeyRisks    []string `json:"key_risks"`
+	Reasoning   string   `json:"reasoning"`
+
+	// Fallback is set when the model gave no valid answer and the
+	// recommendation was not produced by analysis
+	Fallback bool `json:"-"`
+}
+
+// recommendationSchema describes the expected JSON object to the model
+const recommendationSchema = `{
+  "verdict": "buy" | "hold" | "sell",
+  "confidence": integer from 0 to 100,
+  "time_horizon": "short" | "medium" | "long",
+  "key_risks": array of 1 to 5 short strings,
+  "reasoning": string of at most 2 sentences
+}`
+
+// fallbackRecommendation is returned when the model gives no valid answer even
+// after being asked to repair it
+func fallbackRecommendation(locale string) Recommendation {
+	return Recommendation{
+		Verdict:     VerdictHold,
+		Confidence:  0,
+		TimeHorizon: HorizonShort,
+		KeyRisks:    []string{i18n.Translate(locale, "recommendation.fallback.risk")},
+		Reasoning:   i18n.Translate(locale, "recommendation.fallback.reasoning"),
+		Fallback:    true,
+	}
+}
+
+// parseRecommendation extracts the JSON object from a model answer, which may
+// be wrapped in code fences or surrounded by text, and validates it
+func parseRecommendation(content string) (Recommendation, error) {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return Recommendation{}, fmt.Errorf("%w: no JSON object found", ErrInvalidRecommendation)
+	}
+
+	// Numbers are decoded loosely so "confidence": 70.0 is accepted
+	var raw struct {
+		Verdict     string   `json:"verdict"`
+		Confidence  *float64 `json:"confidence"`
+		TimeHorizon string   `json:"time_horizon"`
+		KeyRisks    []string `json:"key_risks"`
+		Reasoning   string   `json:"reasoning"`
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &raw); err != nil {
+		return Recommendation{}, fmt.Errorf("%w: %v", ErrInvalidRecommendation, err)
+	}
+	if raw.Confidence == nil {
+		return Recommendation{}, fmt.Errorf("%w: confidence is missing", ErrInvalidRecommendation)
+	}
+	// Rounding would turn -0.4 or a 0.7 on a 0-1 scale into valid values
+	if confidence := *raw.Confidence; confidence < 0 || confidence != math.Trunc(confidence) {
+		return Recommendation{}, fmt.Errorf("%w: confidence %v is not a whole number from 0 to 100", ErrInvalidRecommendation, confidence)
+	}
+
+	rec := Recommendation{
+		Verdict:     strings.ToLower(strings.TrimSpace(raw.Verdict)),
+		Confidence:  int(*raw.Confidence),
+		TimeHorizon: normalizeHorizon(raw.TimeHorizon),
+		Reasoning:   strings.TrimSpace(raw.Reasoning),
+	}
+	for _, risk := range raw.KeyRisks {
+		if risk = strings.TrimSpace(risk); risk != "" {
+			rec.KeyRisks = append(rec.KeyRisks, risk)
+		}
+	}
+
+	if err := rec.Validate(); err != nil {
+		return Recommendation{}, err
+	}
+	return rec, nil
+}
+
+// normalizeHorizon accepts variants such as "Short-term" or "long term"
+func normalizeHorizon(horizon string) string {
+	horizon = strings.ToLower(strings.TrimSpace(horizon))
+	horizon = strings.TrimSuffix(horizon, "-term")
+	return strings.TrimSpace(strings.TrimSuffix(horizon, " term"))
+}
+
+// Validate checks the recommendation against the schema
+func (r Recommendation) Validate() error {
+	switch r.Verdict {
+	case VerdictBuy, VerdictHold, VerdictSell:
+	default:
+		return fmt.Errorf("%w: verdict %q is not buy, hold or sell", ErrInvalidRecommendation, r.Verdict)
+	}
+
+	if r.Confidence < 0 || r.Confidence > 100 {
+		return fmt.Errorf("%w: confidence %d is not between 0 and 100", ErrInvalidRecommendation, r.Confidence)
+	}
+
+	switch r.TimeHorizon {
+	case HorizonShort, HorizonMedium, HorizonLong:
+	default:
+		return fmt.Errorf("%w: time_horizon %q is not short, medium or long", ErrInvalidRecommendation, r.TimeHorizon)
+	}
+
+	if len(r.KeyRisks) == 0 || len(r.KeyRisks) > maxKeyRisks {
+		return fmt.Errorf("%w: key_risks must list 1 to %d risks", ErrInvalidRecommendation, maxKeyRisks)
+	}
+
+	if r.Reasoning == "" {
+		return fmt.Errorf("%w: reasoning is empty", ErrInvalidRecommendation)
+	}
+	if utf8.RuneCountInString(r.Reasoning) > maxReasoningChars {
+		return fmt.Errorf("%w: reasoning is longer than %d characters", ErrInvalidRecommendation, maxReasoningChars)
+	}
+	return nil
+}
+
+// Format renders the recommendation for a chat message in locale
+func (r Recommendation) Format(crypto, locale string) string {
+	var b strings.Builder
+	b.WriteString(i18n.Translate(locale, "recommendation.title", crypto, formatVerdict(r.Verdict, locale)) + "\n")
+	if r.Fallback {
+		b.WriteString(i18n.Translate(locale, "recommendation.fallback") + "\n")
+	} else {
+		b.WriteString(i18n.Translate(locale, "recommendation.details", r.Confidence,
+			i18n.Translate(locale, "horizon."+r.TimeHorizon)) + "\n")
+	}
+	b.WriteString("\n" + r.Reasoning + "\n")
+
+	b.WriteString("\n" + i18n.Translate(locale, "recommendation.key_risks"))
+	for _, risk := range r.KeyRisks {
+		b.WriteString("\n• " + risk)
+	}
+	return b.String()
+}
+
+// FormatChange describes in locale how the recommendation differs from an
+// earlier one made at the given time
+func (r Recommendation) FormatChange(previous Recommendation, at time.Time, locale string) string {
+	date := at.Format("2006-01-02")
+	verdict := formatVerdict(previous.Verdict, locale)
+	if previous.Verdict == r.Verdict {
+		return i18n.Translate(locale, "recommendation.unchanged", date, verdict, previous.Confidence)
+	}
+	return i18n.Translate(locale, "recommendation.changed", verdict, date, previous.Confidence)
+}
+
+// formatVerdict names a verdict in locale, e.g. "BUY"
+func formatVerdict(verdict, locale string) string {
+	if key := "verdict." + verdict; i18n.Has(key) {
+		return i18n.Translate(locale, key)
+	}
+	return strings.ToUpper(verdict)
+}
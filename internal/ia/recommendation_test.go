@@ -0,0 +1,184 @@
+package ia
+
+import (
+	"blockmind/internal/config"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validRecommendation is a reply matching the schema
+const validRecommendation = `{"verdict": "buy", "confidence": 70, "time_horizon": "medium", "key_risks": ["volatility", "regulation"], "reasoning": "Momentum is strong."}`
+
+func TestParseRecommendation(t *testing.T) {
+	want := Recommendation{
+		Verdict:     VerdictBuy,
+		Confidence:  70,
+		TimeHorizon: HorizonMedium,
+		KeyRisks:    []string{"volatility", "regulation"},
+		Reasoning:   "Momentum is strong.",
+	}
+	for _, content := range []string{
+		validRecommendation,
+		"```json\n" + validRecommendation + "\n```",
+		"Here is my analysis: " + validRecommendation + " Hope it helps.",
+		`{"verdict": " BUY ", "confidence": 70.0, "time_horizon": "Medium-term", "key_risks": ["volatility", " ", "regulation"], "reasoning": " Momentum is strong. "}`,
+	} {
+		got, err := parseRecommendation(content)
+		if err != nil {
+			t.Errorf("parseRecommendation(%q) error = %v", content, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("parseRecommendation(%q) = %+v, want %+v", content, got, want)
+		}
+	}
+}
+
+func TestParseRecommendationRejectsInvalid(t *testing.T) {
+	reply := func(field, value string) string {
+		fields := map[string]string{
+			"verdict":      `"hold"`,
+			"confidence":   `50`,
+			"time_horizon": `"short"`,
+			"key_risks":    `["liquidity"]`,
+			"reasoning":    `"Sideways market."`,
+		}
+		if value == "" {
+			delete(fields, field)
+		} else {
+			fields[field] = value
+		}
+		var parts []string
+		for _, name := range []string{"verdict", "confidence", "time_horizon", "key_risks", "reasoning"} {
+			if value, ok := fields[name]; ok {
+				parts = append(parts, `"`+name+`": `+value)
+			}
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	}
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"no JSON", "I think you should buy."},
+		{"broken JSON", `{"verdict": "buy"`},
+		{"unknown verdict", reply("verdict", `"moon"`)},
+		{"missing confidence", reply("confidence", "")},
+		{"negative confidence", reply("confidence", "-0.4")},
+		{"fractional confidence", reply("confidence", "0.7")},
+		{"confidence above 100", reply("confidence", "101")},
+		{"unknown horizon", reply("time_horizon", `"forever"`)},
+		{"no risks", reply("key_risks", `[]`)},
+		{"blank risks", reply("key_risks", `["", " "]`)},
+		{"too many risks", reply("key_risks", `["a", "b", "c", "d", "e", "f"]`)},
+		{"empty reasoning", reply("reasoning", `" "`)},
+		{"reasoning too long", reply("reasoning", `"`+strings.Repeat("a", maxReasoningChars+1)+`"`)},
+	}
+	for _, tt := range tests {
+		if _, err := parseRecommendation(tt.content); !errors.Is(err, ErrInvalidRecommendation) {
+			t.Errorf("%s: parseRecommendation() error = %v, want ErrInvalidRecommendation", tt.name, err)
+		}
+	}
+
+	// The reasoning limit counts characters, not bytes
+	accented := strings.Repeat("á", maxReasoningChars)
+	if _, err := parseRecommendation(reply("reasoning", `"`+accented+`"`)); err != nil {
+		t.Errorf("parseRecommendation() with %d accented characters error = %v", maxReasoningChars, err)
+	}
+}
+
+// newRecommendAssistant creates an assistant answering from client
+func newRecommendAssistant(client LLMClient) *Assistant {
+	return NewAssistant(client, &config.Config{
+		LLMRecommendModel: "recommend",
+		AITimeout:         time.Second,
+		AIMaxTokens:       100,
+	}, nil, nil)
+}
+
+func TestGetInvestmentRecommendation(t *testing.T) {
+	tests := []struct {
+		name    string
+		replies []string
+	}{
+		{"valid reply", []string{validRecommendation}},
+		{"fenced reply", []string{"```json\n" + validRecommendation + "\n```"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newScriptedClient(tt.replies...)
+			rec, err := newRecommendAssistant(client).GetInvestmentRecommendation(context.Background(), "bitcoin", "price: 65000")
+			if err != nil {
+				t.Fatalf("GetInvestmentRecommendation() error = %v", err)
+			}
+			if rec.Verdict != VerdictBuy || rec.Confidence != 70 || rec.Fallback {
+				t.Errorf("GetInvestmentRecommendation() = %+v, want the model's buy at 70", rec)
+			}
+
+			requests := client.Requests()
+			if len(requests) != 1 {
+				t.Fatalf("model asked %d times, want 1", len(requests))
+			}
+			if requests[0].Model != "recommend" || requests[0].MaxTokens != minRecommendationTokens {
+				t.Errorf("request model %q with %d tokens, want recommend with %d", requests[0].Model, requests[0].MaxTokens, minRecommendationTokens)
+			}
+			if prompt := requests[0].Messages[1].Content; !strings.Contains(prompt, "price: 65000") || !strings.Contains(prompt, `"verdict"`) {
+				t.Errorf("prompt %q lacks the data or the schema", prompt)
+			}
+		})
+	}
+}
+
+func TestGetInvestmentRecommendationRepairsReply(t *testing.T) {
+	invalid := `{"verdict": "moon", "confidence": 70, "time_horizon": "medium", "key_risks": ["volatility"], "reasoning": "Up only."}`
+	client := newScriptedClient(invalid, validRecommendation)
+
+	rec, err := newRecommendAssistant(client).GetInvestmentRecommendation(context.Background(), "bitcoin", "price: 65000")
+	if err != nil {
+		t.Fatalf("GetInvestmentRecommendation() error = %v", err)
+	}
+	if rec.Verdict != VerdictBuy || rec.Fallback {
+		t.Errorf("GetInvestmentRecommendation() = %+v, want the repaired recommendation", rec)
+	}
+
+	requests := client.Requests()
+	if len(requests) != maxRecommendationAttempts {
+		t.Fatalf("model asked %d times, want %d", len(requests), maxRecommendationAttempts)
+	}
+	messages := requests[1].Messages
+	answer, repair := messages[len(messages)-2], messages[len(messages)-1]
+	if answer.Role != "assistant" || answer.Content != invalid {
+		t.Errorf("repair request repeats %+v, want the invalid answer", answer)
+	}
+	if repair.Role != "user" || !strings.Contains(repair.Content, `verdict "moon"`) {
+		t.Errorf("repair request ends with %+v, want the problem explained", repair)
+	}
+}
+
+func TestGetInvestmentRecommendationFallsBack(t *testing.T) {
+	client := newScriptedClient("I cannot answer that.", `{"verdict": "buy", "confidence": 0.7}`)
+	ctx := context.Background()
+
+	rec, err := newRecommendAssistant(client).GetInvestmentRecommendation(ctx, "bitcoin", "price: 65000")
+	if err != nil {
+		t.Fatalf("GetInvestmentRecommendation() error = %v", err)
+	}
+	if want := fallbackRecommendation("en"); !reflect.DeepEqual(rec, want) {
+		t.Errorf("GetInvestmentRecommendation() = %+v, want the fallback %+v", rec, want)
+	}
+	if len(client.Requests()) != maxRecommendationAttempts {
+		t.Errorf("model asked %d times, want %d", len(client.Requests()), maxRecommendationAttempts)
+	}
+}
+
+func TestGetInvestmentRecommendationUnreachable(t *testing.T) {
+	_, err := newRecommendAssistant(newScriptedClient()).GetInvestmentRecommendation(context.Background(), "bitcoin", "")
+	if !errors.Is(err, errScriptExhausted) {
+		t.Errorf("GetInvestmentRecommendation() error = %v, want the client error", err)
+	}
+}
@@ -0,0 +1,54 @@
+package ia
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// errScriptExhausted is returned by a scriptedClient asked for more replies
+// than its script holds
+var errScriptExhausted = errors.New("llm: scripted client has no more replies")
+
+// scriptedClient is an LLMClient that plays back a fixed list of replies
+// instead of calling a model. It records every request, so flows such as tool
+// calls or recommendation repairs can be exercised and inspected without a
+// backend.
+type scriptedClient struct {
+	mu       sync.Mutex
+	replies  []string
+	requests []ChatRequest
+}
+
+// newScriptedClient creates a client that answers with replies, in order
+func newScriptedClient(replies ...string) *scriptedClient {
+	return &scriptedClient{replies: replies}
+}
+
+// Chat returns the next scripted reply
+func (c *scriptedClient) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Keep a copy: callers reuse and append to the message slice
+	req.Messages = append([]Message(nil), req.Messages...)
+	c.requests = append(c.requests, req)
+
+	if len(c.replies) == 0 {
+		return nil, errScriptExhausted
+	}
+	reply := c.replies[0]
+	c.replies = c.replies[1:]
+	return &ChatResponse{Content: reply, FinishReason: "stop"}, nil
+}
+
+// Requests returns the requests received so far
+func (c *scriptedClient) Requests() []ChatRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]ChatRequest(nil), c.requests...)
+}
@@ -0,0 +1,90 @@
+package ia
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTooManyToolCalls is returned when the model keeps calling tools instead of answering
+var ErrTooManyToolCalls = errors.New("llm: too many tool calls")
+
+// Tool is a function the model may call while answering a question, for
+// data it cannot know such as current prices
+type Tool struct {
+	Name        string
+	Description string
+	// Arguments describes the JSON arguments to the model, e.g.
+	// {"coin": "name or symbol"}
+	Arguments string
+	// Run executes the tool. Its result or error is given back to the model.
+	Run func(ctx context.Context, args json.RawMessage) (string, error)
+}
+
+// toolCall is a request from the model to run a tool
+type toolCall struct {
+	Tool      string          `json:"tool"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// RegisterTool makes a tool available to Q&A
+func (a *Assistant) RegisterTool(tool Tool) {
+	a.tools = append(a.tools, tool)
+}
+
+// toolPrompt explains the registered tools and how to call them
+func (a *Assistant) toolPrompt() string {
+	var b strings.Builder
+	b.WriteString(`You can call tools to get live data. To call one, reply with only a JSON object such as {"tool": "get_price", "arguments": {"coin": "bitcoin"}} and nothing else; the result is sent back to you. Call a tool whenever the question needs current prices or market data, then answer normally. Available tools:`)
+	for _, tool := range a.tools {
+		fmt.Fprintf(&b, "\n- %s: %s Arguments: %s", tool.Name, tool.Description, tool.Arguments)
+	}
+	return b.String()
+}
+
+// parseToolCall reports whether a reply is a tool call rather than an answer.
+// A call is a reply made only of a JSON object with a "tool" field, possibly
+// in a code fence.
+func parseToolCall(reply string) (toolCall, bool) {
+	reply = strings.TrimSpace(reply)
+	reply = strings.TrimPrefix(reply, "```json")
+	reply = strings.TrimSpace(strings.Trim(reply, "`"))
+	if !strings.HasPrefix(reply, "{") || !strings.HasSuffix(reply, "}") {
+		return toolCall{}, false
+	}
+
+	var call toolCall
+	if err := json.Unmarshal([]byte(reply), &call); err != nil || call.Tool == "" {
+		return toolCall{}, false
+	}
+	return call, true
+}
+
+// mayBeToolCall reports whether a partial reply could turn out to be a tool call
+func mayBeToolCall(partial string) bool {
+	partial = strings.TrimSpace(partial)
+	return strings.HasPrefix(partial, "{") || strings.HasPrefix(partial, "`")
+}
+
+// runTool executes a call and returns the result as the model will see it.
+// Failures are reported to the model so it can correct the call or explain.
+func (a *Assistant) runTool(ctx context.Context, call toolCall) string {
+	for _, tool := range a.tools {
+		if tool.Name != call.Tool {
+			continue
+		}
+
+		args := call.Arguments
+		if len(args) == 0 {
+			args = json.RawMessage("{}")
+		}
+		result, err := tool.Run(ctx, args)
+		if err != nil {
+			return fmt.Sprintf("Tool %s failed: %v", call.Tool, err)
+		}
+		return fmt.Sprintf("Result of %s: %s", call.Tool, result)
+	}
+	return fmt.Sprintf("There is no tool named %s. Answer with the data you have or call one of the available tools.", call.Tool)
+}
@@ -0,0 +1,194 @@
+package ia
+
+import (
+	"blockmind/internal/config"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// priceTool is a get_price tool that records the arguments of every call
+type priceTool struct {
+	calls []string
+	err   error
+}
+
+func (p *priceTool) tool() Tool {
+	return Tool{
+		Name:        "get_price",
+		Description: "Current price of a coin.",
+		Arguments:   `{"coin": "name or symbol"}`,
+		Run: func(ctx context.Context, args json.RawMessage) (string, error) {
+			p.calls = append(p.calls, string(args))
+			if p.err != nil {
+				return "", p.err
+			}
+			return "65000 USD", nil
+		},
+	}
+}
+
+// newToolAssistant creates an assistant answering from client that may call
+// the price tool up to maxToolCalls times
+func newToolAssistant(client LLMClient, price *priceTool, maxToolCalls int) *Assistant {
+	assistant := NewAssistant(client, &config.Config{
+		AITimeout:      time.Second,
+		AIMaxToolCalls: maxToolCalls,
+	}, nil, nil)
+	assistant.RegisterTool(price.tool())
+	return assistant
+}
+
+// lastMessage returns the last message of a request
+func lastMessage(req ChatRequest) Message {
+	return req.Messages[len(req.Messages)-1]
+}
+
+func TestToolCallThenAnswer(t *testing.T) {
+	client := newScriptedClient(
+		`{"tool": "get_price", "arguments": {"coin": "bitcoin"}}`,
+		"Bitcoin trades at 65000 USD.",
+	)
+	price := &priceTool{}
+	assistant := newToolAssistant(client, price, 3)
+
+	answer, err := assistant.AskQuestion(context.Background(), "", "What is the price of bitcoin?")
+	if err != nil {
+		t.Fatalf("AskQuestion() error = %v", err)
+	}
+	if !strings.HasPrefix(answer, "Bitcoin trades at 65000 USD.") {
+		t.Errorf("AskQuestion() = %q, want the final answer", answer)
+	}
+	if len(price.calls) != 1 || price.calls[0] != `{"coin": "bitcoin"}` {
+		t.Errorf("tool calls = %q, want one call for bitcoin", price.calls)
+	}
+
+	requests := client.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("model asked %d times, want 2", len(requests))
+	}
+	if !strings.Contains(requests[0].Messages[0].Content, "get_price") {
+		t.Error("system prompt does not list the get_price tool")
+	}
+	messages := requests[1].Messages
+	call, result := messages[len(messages)-2], messages[len(messages)-1]
+	if call.Role != "assistant" || !strings.Contains(call.Content, `"get_price"`) {
+		t.Errorf("second request repeats %+v, want the tool call", call)
+	}
+	if result.Role != "user" || result.Content != "Result of get_price: 65000 USD" {
+		t.Errorf("second request ends with %+v, want the tool result", result)
+	}
+}
+
+func TestUnknownToolIsReportedToModel(t *testing.T) {
+	client := newScriptedClient(
+		"```json\n{\"tool\": \"get_weather\", \"arguments\": {\"city\": \"Madrid\"}}\n```",
+		"I cannot check the weather.",
+	)
+	price := &priceTool{}
+	assistant := newToolAssistant(client, price, 3)
+
+	answer, err := assistant.AskQuestion(context.Background(), "", "Is it raining in Madrid?")
+	if err != nil {
+		t.Fatalf("AskQuestion() error = %v", err)
+	}
+	if !strings.HasPrefix(answer, "I cannot check the weather.") {
+		t.Errorf("AskQuestion() = %q, want the final answer", answer)
+	}
+	if len(price.calls) != 0 {
+		t.Errorf("get_price ran %d times, want 0", len(price.calls))
+	}
+
+	requests := client.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("model asked %d times, want 2", len(requests))
+	}
+	if result := lastMessage(requests[1]).Content; !strings.Contains(result, "There is no tool named get_weather") {
+		t.Errorf("model was told %q, want that the tool does not exist", result)
+	}
+}
+
+func TestToolErrorIsReportedToModel(t *testing.T) {
+	client := newScriptedClient(
+		`{"tool": "get_price", "arguments": {"coin": "nocoin"}}`,
+		"I could not find that coin.",
+	)
+	price := &priceTool{err: errors.New("unknown coin")}
+	assistant := newToolAssistant(client, price, 3)
+
+	if _, err := assistant.AskQuestion(context.Background(), "", "Price of nocoin?"); err != nil {
+		t.Fatalf("AskQuestion() error = %v", err)
+	}
+	if result := lastMessage(client.Requests()[1]).Content; result != "Tool get_price failed: unknown coin" {
+		t.Errorf("model was told %q, want the tool error", result)
+	}
+}
+
+func TestToolCallLimit(t *testing.T) {
+	call := `{"tool": "get_price", "arguments": {"coin": "bitcoin"}}`
+	client := newScriptedClient(call, call, call, "never reached")
+	price := &priceTool{}
+	assistant := newToolAssistant(client, price, 2)
+
+	_, err := assistant.AskQuestion(context.Background(), "", "What is the price of bitcoin?")
+	if !errors.Is(err, ErrTooManyToolCalls) {
+		t.Fatalf("AskQuestion() error = %v, want ErrTooManyToolCalls", err)
+	}
+	if len(price.calls) != 2 {
+		t.Errorf("tool ran %d times, want 2", len(price.calls))
+	}
+
+	requests := client.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("model asked %d times, want 3", len(requests))
+	}
+	if result := lastMessage(requests[1]).Content; strings.Contains(result, "cannot call more tools") {
+		t.Errorf("first result %q already forbids more calls", result)
+	}
+	if result := lastMessage(requests[2]).Content; !strings.Contains(result, "You cannot call more tools") {
+		t.Errorf("last result %q does not tell the model to stop calling tools", result)
+	}
+}
+
+func TestToolCallsDisabled(t *testing.T) {
+	reply := `{"tool": "get_price", "arguments": {"coin": "bitcoin"}}`
+	client := newScriptedClient(reply)
+	price := &priceTool{}
+	assistant := newToolAssistant(client, price, 0)
+
+	answer, err := assistant.AskQuestion(context.Background(), "", "What is the price of bitcoin?")
+	if err != nil {
+		t.Fatalf("AskQuestion() error = %v", err)
+	}
+	if !strings.HasPrefix(answer, reply) || len(price.calls) != 0 {
+		t.Errorf("AskQuestion() = %q with %d tool calls, want the reply as the answer", answer, len(price.calls))
+	}
+	if strings.Contains(client.Requests()[0].Messages[0].Content, "get_price") {
+		t.Error("system prompt lists tools although they are disabled")
+	}
+}
+
+func TestParseToolCall(t *testing.T) {
+	tests := []struct {
+		reply string
+		tool  string
+		ok    bool
+	}{
+		{`{"tool": "get_price", "arguments": {"coin": "eth"}}`, "get_price", true},
+		{"```json\n{\"tool\": \"get_market\"}\n```", "get_market", true},
+		{`  {"tool": "get_price"}  `, "get_price", true},
+		{"Bitcoin is a cryptocurrency.", "", false},
+		{`{"answer": "42"}`, "", false},
+		{`Here you go: {"tool": "get_price"}`, "", false},
+		{`{"tool": "get_price"`, "", false},
+	}
+	for _, tt := range tests {
+		call, ok := parseToolCall(tt.reply)
+		if ok != tt.ok || call.Tool != tt.tool {
+			t.Errorf("parseToolCall(%q) = %q, %v, want %q, %v", tt.reply, call.Tool, ok, tt.tool, tt.ok)
+		}
+	}
+}
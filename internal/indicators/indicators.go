@@ -0,0 +1,204 @@
+package indicators
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInsufficientData is returned when a series is too short for an indicator
+var ErrInsufficientData = errors.New("insufficient data")
+
+// MACDResult holds the latest values of the MACD indicator
+type MACDResult struct {
+	MACD      float64
+	Signal    float64
+	Histogram float64
+}
+
+// BollingerBands holds the latest values of the Bollinger Bands indicator
+type BollingerBands struct {
+	Upper  float64
+	Middle float64
+	Lower  float64
+}
+
+// SMA returns the simple moving average of the last period values
+func SMA(values []float64, period int) (float64, error) {
+	if err := checkLength(values, period, period); err != nil {
+		return 0, err
+	}
+	return mean(values[len(values)-period:]), nil
+}
+
+// EMASeries returns the exponential moving average of values. The series is
+// seeded with the SMA of the first period values, so the result has
+// len(values)-period+1 entries aligned with the end of values.
+func EMASeries(values []float64, period int) ([]float64, error) {
+	if err := checkLength(values, period, period); err != nil {
+		return nil, err
+	}
+
+	k := 2 / float64(period+1)
+	series := make([]float64, 0, len(values)-period+1)
+	ema := mean(values[:period])
+	series = append(series, ema)
+	for _, value := range values[period:] {
+		ema = (value-ema)*k + ema
+		series = append(series, ema)
+	}
+	return series, nil
+}
+
+// EMA returns the latest exponential moving average of values
+func EMA(values []float64, period int) (float64, error) {
+	series, err := EMASeries(values, period)
+	if err != nil {
+		return 0, err
+	}
+	return series[len(series)-1], nil
+}
+
+// RSI returns the latest Relative Strength Index using Wilder's smoothing
+func RSI(values []float64, period int) (float64, error) {
+	if err := checkLength(values, period, period+1); err != nil {
+		return 0, err
+	}
+
+	var avgGain, avgLoss float64
+	for i := 1; i <= period; i++ {
+		change := values[i] - values[i-1]
+		if change > 0 {
+			avgGain += change
+		} else {
+			avgLoss -= change
+		}
+	}
+	avgGain /= float64(period)
+	avgLoss /= float64(period)
+
+	for i := period + 1; i < len(values); i++ {
+		change := values[i] - values[i-1]
+		gain, loss := 0.0, 0.0
+		if change > 0 {
+			gain = change
+		} else {
+			loss = -change
+		}
+		avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+		avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
+	}
+
+	if avgLoss == 0 {
+		if avgGain == 0 {
+			return 50, nil
+		}
+		return 100, nil
+	}
+	rs := avgGain / avgLoss
+	return 100 - 100/(1+rs), nil
+}
+
+// MACD returns the latest Moving Average Convergence Divergence values for the
+// given fast, slow and signal periods (commonly 12, 26 and 9)
+func MACD(values []float64, fast, slow, signal int) (MACDResult, error) {
+	if fast <= 0 || slow <= fast || signal <= 0 {
+		return MACDResult{}, fmt.Errorf("invalid MACD periods %d/%d/%d", fast, slow, signal)
+	}
+	if len(values) < slow+signal-1 {
+		return MACDResult{}, fmt.Errorf("MACD needs %d values, got %d: %w", slow+signal-1, len(values), ErrInsufficientData)
+	}
+
+	fastEMA, err := EMASeries(values, fast)
+	if err != nil {
+		return MACDResult{}, err
+	}
+	slowEMA, err := EMASeries(values, slow)
+	if err != nil {
+		return MACDResult{}, err
+	}
+
+	// Align the fast series with the shorter slow series
+	offset := len(fastEMA) - len(slowEMA)
+	macdLine := make([]float64, len(slowEMA))
+	for i := range slowEMA {
+		macdLine[i] = fastEMA[i+offset] - slowEMA[i]
+	}
+
+	signalLine, err := EMASeries(macdLine, signal)
+	if err != nil {
+		return MACDResult{}, err
+	}
+
+	result := MACDResult{
+		MACD:   macdLine[len(macdLine)-1],
+		Signal: signalLine[len(signalLine)-1],
+	}
+	result.Histogram = result.MACD - result.Signal
+	return result, nil
+}
+
+// Bollinger returns the latest Bollinger Bands: the SMA of the last period
+// values plus and minus k population standard deviations
+func Bollinger(values []float64, period int, k float64) (BollingerBands, error) {
+	if err := checkLength(values, period, period); err != nil {
+		return BollingerBands{}, err
+	}
+
+	window := values[len(values)-period:]
+	middle := mean(window)
+	deviation := math.Sqrt(variance(window, middle, float64(period)))
+	return BollingerBands{
+		Upper:  middle + k*deviation,
+		Middle: middle,
+		Lower:  middle - k*deviation,
+	}, nil
+}
+
+// Volatility returns the annualized volatility of a price series as the sample
+// standard deviation of log returns scaled by the square root of periodsPerYear
+// (365 for daily crypto prices)
+func Volatility(values []float64, periodsPerYear float64) (float64, error) {
+	if len(values) < 3 {
+		return 0, fmt.Errorf("volatility needs 3 values, got %d: %w", len(values), ErrInsufficientData)
+	}
+
+	returns := make([]float64, 0, len(values)-1)
+	for i := 1; i < len(values); i++ {
+		if values[i-1] <= 0 || values[i] <= 0 {
+			return 0, fmt.Errorf("volatility requires positive prices")
+		}
+		returns = append(returns, math.Log(values[i]/values[i-1]))
+	}
+
+	avg := mean(returns)
+	return math.Sqrt(variance(returns, avg, float64(len(returns)-1))) * math.Sqrt(periodsPerYear), nil
+}
+
+// checkLength validates the period and that values holds at least min entries
+func checkLength(values []float64, period, min int) error {
+	if period <= 0 {
+		return fmt.Errorf("invalid period %d", period)
+	}
+	if len(values) < min {
+		return fmt.Errorf("period %d needs %d values, got %d: %w", period, min, len(values), ErrInsufficientData)
+	}
+	return nil
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+// variance returns the sum of squared deviations from avg divided by divisor
+func variance(values []float64, avg, divisor float64) float64 {
+	sum := 0.0
+	for _, value := range values {
+		sum += (value - avg) * (value - avg)
+	}
+	return sum / divisor
+}
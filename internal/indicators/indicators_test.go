@@ -0,0 +1,239 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// emaCloses and the expected 10-day SMA and EMA values are the reference
+// series of the StockCharts ChartSchool article on moving averages
+var emaCloses = []float64{
+	22.27, 22.19, 22.08, 22.17, 22.18, 22.13, 22.23, 22.43, 22.24, 22.29,
+	22.15, 22.39, 22.38, 22.61, 23.36, 24.05, 23.75, 23.83, 23.95, 23.63,
+	23.82, 23.87, 23.65, 23.19, 23.10, 23.33, 22.68, 23.10, 22.40, 22.17,
+}
+
+// rsiCloses and the expected RSI(14) values are the reference series of the
+// StockCharts ChartSchool article on the Relative Strength Index
+var rsiCloses = []float64{
+	44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08,
+	45.89, 46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64,
+	46.21, 46.25, 45.71, 46.45, 45.78, 45.35, 44.03, 44.18, 44.22, 44.57,
+	43.42, 42.66, 43.13,
+}
+
+func assertClose(t *testing.T, name string, got, want, tolerance float64) {
+	t.Helper()
+	if math.Abs(got-want) > tolerance {
+		t.Errorf("%s = %.4f, want %.4f ± %g", name, got, want, tolerance)
+	}
+}
+
+func TestSMA(t *testing.T) {
+	want := []float64{
+		22.22, 22.21, 22.23, 22.26, 22.30, 22.42, 22.61, 22.77, 22.91, 23.08,
+		23.21, 23.38, 23.52, 23.65, 23.71, 23.68, 23.61, 23.51, 23.43, 23.28, 23.13,
+	}
+	for i, expected := range want {
+		got, err := SMA(emaCloses[:10+i], 10)
+		if err != nil {
+			t.Fatalf("SMA(day %d) error = %v", 10+i, err)
+		}
+		// The reference values are rounded to cents
+		assertClose(t, "SMA", got, expected, 0.006)
+	}
+}
+
+func TestEMA(t *testing.T) {
+	want := []float64{
+		22.22, 22.21, 22.24, 22.27, 22.33, 22.52, 22.80, 22.97, 23.13, 23.28,
+		23.34, 23.43, 23.51, 23.53, 23.47, 23.40, 23.39, 23.26, 23.23, 23.08, 22.92,
+	}
+	series, err := EMASeries(emaCloses, 10)
+	if err != nil {
+		t.Fatalf("EMASeries() error = %v", err)
+	}
+	if len(series) != len(want) {
+		t.Fatalf("EMASeries() has %d values, want %d", len(series), len(want))
+	}
+	for i, expected := range want {
+		assertClose(t, "EMASeries", series[i], expected, 0.006)
+	}
+
+	latest, err := EMA(emaCloses, 10)
+	if err != nil {
+		t.Fatalf("EMA() error = %v", err)
+	}
+	if latest != series[len(series)-1] {
+		t.Errorf("EMA() = %v, want the last value of EMASeries %v", latest, series[len(series)-1])
+	}
+}
+
+func TestRSI(t *testing.T) {
+	want := []float64{
+		70.53, 66.32, 66.55, 69.41, 66.36, 57.97, 62.93, 63.26, 56.06, 62.38,
+		54.71, 50.42, 39.99, 41.46, 41.87, 45.46, 37.30, 33.08, 37.77,
+	}
+	for i, expected := range want {
+		got, err := RSI(rsiCloses[:15+i], 14)
+		if err != nil {
+			t.Fatalf("RSI(day %d) error = %v", 15+i, err)
+		}
+		// The reference spreadsheet rounds the average gains and losses to
+		// cents, which moves its RSI by up to 0.07
+		assertClose(t, "RSI", got, expected, 0.1)
+	}
+}
+
+func TestRSIWithoutLosses(t *testing.T) {
+	rising := make([]float64, 20)
+	for i := range rising {
+		rising[i] = float64(100 + i)
+	}
+	if got, _ := RSI(rising, 14); got != 100 {
+		t.Errorf("RSI(rising) = %v, want 100", got)
+	}
+
+	flat := make([]float64, 20)
+	for i := range flat {
+		flat[i] = 100
+	}
+	if got, _ := RSI(flat, 14); got != 50 {
+		t.Errorf("RSI(flat) = %v, want 50", got)
+	}
+}
+
+func TestMACD(t *testing.T) {
+	// On a straight line each SMA-seeded EMA lags the price by exactly
+	// slope*(period-1)/2, so MACD(12,26,9) is 7 times the slope and the signal
+	// line, an EMA of that constant, equals it
+	for _, slope := range []float64{1, -2.5} {
+		line := make([]float64, 60)
+		for i := range line {
+			line[i] = 1000 + slope*float64(i)
+		}
+
+		got, err := MACD(line, 12, 26, 9)
+		if err != nil {
+			t.Fatalf("MACD() error = %v", err)
+		}
+		assertClose(t, "MACD", got.MACD, 7*slope, 1e-9)
+		assertClose(t, "Signal", got.Signal, 7*slope, 1e-9)
+		assertClose(t, "Histogram", got.Histogram, 0, 1e-9)
+	}
+}
+
+func TestMACDPeriods(t *testing.T) {
+	values := make([]float64, 34)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+
+	if _, err := MACD(values, 12, 26, 9); err != nil {
+		t.Errorf("MACD() with slow+signal-1 values error = %v", err)
+	}
+	if _, err := MACD(values[:33], 12, 26, 9); !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("MACD() with 33 values error = %v, want ErrInsufficientData", err)
+	}
+
+	for _, periods := range [][3]int{{26, 12, 9}, {12, 12, 9}, {0, 26, 9}, {12, 26, 0}} {
+		_, err := MACD(values, periods[0], periods[1], periods[2])
+		if err == nil || errors.Is(err, ErrInsufficientData) {
+			t.Errorf("MACD(%v) error = %v, want an invalid periods error", periods, err)
+		}
+	}
+}
+
+func TestBollinger(t *testing.T) {
+	// 2, 4, 4, 4, 5, 5, 7, 9 is the textbook series with mean 5 and population
+	// standard deviation 2
+	bands, err := Bollinger([]float64{100, 2, 4, 4, 4, 5, 5, 7, 9}, 8, 2)
+	if err != nil {
+		t.Fatalf("Bollinger() error = %v", err)
+	}
+	assertClose(t, "Upper", bands.Upper, 9, 1e-9)
+	assertClose(t, "Middle", bands.Middle, 5, 1e-9)
+	assertClose(t, "Lower", bands.Lower, 1, 1e-9)
+
+	// Twenty closes alternating between 4 and 6 deviate from 5 by exactly 1
+	alternating := make([]float64, 20)
+	for i := range alternating {
+		alternating[i] = 4 + 2*float64(i%2)
+	}
+	bands, err = Bollinger(alternating, 20, 2)
+	if err != nil {
+		t.Fatalf("Bollinger(20, 2) error = %v", err)
+	}
+	assertClose(t, "Upper", bands.Upper, 7, 1e-9)
+	assertClose(t, "Middle", bands.Middle, 5, 1e-9)
+	assertClose(t, "Lower", bands.Lower, 3, 1e-9)
+}
+
+func TestVolatility(t *testing.T) {
+	// Log returns alternating between +r and -r have mean 0 and, over four
+	// returns, a sample variance of 4r²/3
+	r := 0.02
+	prices := []float64{100, 100 * math.Exp(r), 100, 100 * math.Exp(r), 100}
+	got, err := Volatility(prices, 365)
+	if err != nil {
+		t.Fatalf("Volatility() error = %v", err)
+	}
+	assertClose(t, "Volatility", got, r*math.Sqrt(4.0/3)*math.Sqrt(365), 1e-12)
+
+	// Constant growth has no variation around its mean return
+	growing := []float64{100, 110, 121, 133.1}
+	got, err = Volatility(growing, 365)
+	if err != nil {
+		t.Fatalf("Volatility() error = %v", err)
+	}
+	assertClose(t, "Volatility", got, 0, 1e-9)
+
+	if _, err := Volatility([]float64{100, 0, 100}, 365); err == nil {
+		t.Error("Volatility() with a zero price error = nil, want an error")
+	}
+}
+
+func TestInsufficientData(t *testing.T) {
+	short := []float64{1, 2, 3}
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"SMA", errOf(SMA(short, 5))},
+		{"EMA", errOf(EMA(short, 5))},
+		{"RSI", errOf(RSI(short, 3))},
+		{"Bollinger", errOf(Bollinger(short, 5, 2))},
+		{"Volatility", errOf(Volatility(short[:2], 365))},
+	}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, ErrInsufficientData) {
+			t.Errorf("%s error = %v, want ErrInsufficientData", tt.name, tt.err)
+		}
+	}
+}
+
+func TestInvalidPeriod(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5}
+	for _, period := range []int{0, -1} {
+		tests := []struct {
+			name string
+			err  error
+		}{
+			{"SMA", errOf(SMA(values, period))},
+			{"EMA", errOf(EMA(values, period))},
+			{"RSI", errOf(RSI(values, period))},
+			{"Bollinger", errOf(Bollinger(values, period, 2))},
+		}
+		for _, tt := range tests {
+			if tt.err == nil || errors.Is(tt.err, ErrInsufficientData) {
+				t.Errorf("%s(period %d) error = %v, want an invalid period error", tt.name, period, tt.err)
+			}
+		}
+	}
+}
+
+// errOf returns the error of a two-valued call
+func errOf[T any](_ T, err error) error {
+	return err
+}
@@ -0,0 +1,45 @@
+// Package intent maps free-text messages such as "what's the price of eth in
+// euros" to bot commands, so users do not need to know the slash syntax.
+package intent
+
+import (
+	"context"
+	"strings"
+)
+
+// Commands an intent can route to
+const (
+	Price     = "price"
+	Recommend = "recommend"
+	Help      = "help"
+)
+
+// Intent is a command recognized in a free-text message
+type Intent struct {
+	// Command is the name of the command to run
+	Command string
+	// Coins and Currency are the arguments found in the message
+	Coins    []string
+	Currency string
+	// Confidence is how sure the classifier is, from 0 to 1
+	Confidence float64
+}
+
+// Args returns the arguments of the intent in the command's syntax
+func (i Intent) Args() []string {
+	var args []string
+	if len(i.Coins) > 0 {
+		args = strings.Fields(strings.Join(i.Coins, ", "))
+	}
+	if i.Currency != "" && i.Command == Price {
+		args = append(args, "in", i.Currency)
+	}
+	return args
+}
+
+// Classifier recognizes the intent of a message. It returns a zero Intent when
+// the message matches none.
+type Classifier func(ctx context.Context, text string) (Intent, error)
+
+// KnownCoin reports whether a term names a coin
+type KnownCoin func(ctx context.Context, term string) bool
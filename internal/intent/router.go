@@ -0,0 +1,88 @@
+package intent
+
+import (
+	"blockmind/internal/logger"
+	"context"
+)
+
+// unknownCoinPenalty scales the confidence of an intent naming a coin that is
+// not known, as in "what is the price of fame"
+const unknownCoinPenalty = 0.5
+
+// Router routes free-text messages to commands. Phrasing rules are tried
+// first; the optional classifier, usually a language model, is asked when no
+// rule is confident enough.
+type Router struct {
+	known      KnownCoin
+	classifier Classifier
+	threshold  float64
+}
+
+// NewRouter creates a router. Intents below threshold confidence are not
+// routed. The classifier may be nil.
+func NewRouter(known KnownCoin, classifier Classifier, threshold float64) *Router {
+	return &Router{
+		known:      known,
+		classifier: classifier,
+		threshold:  threshold,
+	}
+}
+
+// Route returns the command intended by a message, or false when the message
+// should be answered as a question
+func (r *Router) Route(ctx context.Context, text string) (Intent, bool) {
+	if in, ok := matchRules(text); ok {
+		in = r.check(ctx, in)
+		if in.Confidence >= r.threshold {
+			logger.Debug("Routed message by rule", logger.Field{Key: "command", Value: in.Command},
+				logger.Field{Key: "confidence", Value: in.Confidence})
+			return in, true
+		}
+	}
+
+	if r.classifier == nil {
+		return Intent{}, false
+	}
+
+	in, err := r.classifier(ctx, text)
+	if err != nil {
+		logger.Warn("Intent classification failed", logger.Field{Key: "error", Value: err.Error()})
+		return Intent{}, false
+	}
+	if in.Command == "" {
+		return Intent{}, false
+	}
+
+	in = r.check(ctx, in)
+	if in.Confidence < r.threshold {
+		return Intent{}, false
+	}
+	logger.Debug("Routed message by classifier", logger.Field{Key: "command", Value: in.Command},
+		logger.Field{Key: "confidence", Value: in.Confidence})
+	return in, true
+}
+
+// check validates the arguments of an intent and lowers its confidence when
+// they do not make sense for the command
+func (r *Router) check(ctx context.Context, in Intent) Intent {
+	switch in.Command {
+	case Price, Recommend:
+		if len(in.Coins) == 0 {
+			in.Confidence = 0
+		}
+		if in.Command == Recommend && len(in.Coins) > 1 {
+			in.Confidence = 0
+		}
+		for _, coin := range in.Coins {
+			if r.known != nil && !r.known(ctx, coin) {
+				in.Confidence *= unknownCoinPenalty
+				break
+			}
+		}
+	case Help:
+		in.Coins, in.Currency = nil, ""
+	default:
+		in.Confidence = 0
+	}
+	return in
+}
@@ -0,0 +1,168 @@
+package intent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Confidence of the rule patterns. Strong patterns leave no doubt about the
+// request; weak ones are also common in open questions.
+const (
+	strongMatch = 0.9
+	weakMatch   = 0.75
+	exactMatch  = 0.95
+)
+
+// coinPart captures one to three coins of up to three words each, separated
+// by commas, "and" or "y". It is lazy so the words after the coins, such as
+// "in euros", are left to the rest of the pattern.
+const coinPart = `([\p{L}\d.\-]+(?: [\p{L}\d.\-]+){0,2}?(?:(?:, ?| and | y )[\p{L}\d.\-]+(?: [\p{L}\d.\-]+){0,2}?){0,2}?)`
+
+// currencyPart captures an optional target currency
+const currencyPart = `(?: (?:in|en|to) ([\p{L}]+(?: [\p{L}]+)?))?`
+
+// rule recognizes one phrasing of an intent
+type rule struct {
+	command    string
+	pattern    *regexp.Regexp
+	confidence float64
+}
+
+// newRule compiles a rule whose pattern must match the whole normalized message
+func newRule(command, pattern string, confidence float64) rule {
+	return rule{command: command, pattern: regexp.MustCompile(`^` + pattern + `$`), confidence: confidence}
+}
+
+// rules are matched against the normalized message, in order. Messages are
+// lowercase and without accents or punctuation, so "¿Cuánto vale?" reads
+// "cuanto vale".
+var rules = []rule{
+	// English prices
+	newRule(Price, `(?:what(?: is|s)|whats|tell me|give me|show me|check)? ?(?:the )?(?:current |latest )?price (?:of|for) `+coinPart+currencyPart+`(?: (?:now|right now|today))?`, strongMatch),
+	newRule(Price, `how much (?:is|are|does|do) (?:the |one |1 )?`+coinPart+`(?: (?:worth|cost))?`+currencyPart+`(?: (?:now|right now|today))?`, strongMatch),
+	newRule(Price, coinPart+` price`+currencyPart+`(?: (?:now|right now|today))?`, weakMatch),
+
+	// Spanish prices
+	newRule(Price, `(?:cual es |dime |dame )?(?:el )?precio (?:actual )?(?:de|del) `+coinPart+currencyPart+`(?: (?:ahora|hoy))?`, strongMatch),
+	newRule(Price, `(?:cuanto|a cuanto) (?:vale|cuesta|esta|estan|valen|cuestan) (?:el |la |los |un )?`+coinPart+currencyPart+`(?: (?:ahora|hoy))?`, strongMatch),
+
+	// English recommendations
+	newRule(Recommend, `should i (?:buy|sell|hold|invest in|get) (?:some )?`+coinPart+`(?: (?:now|right now|today))?`, strongMatch),
+	newRule(Recommend, `is `+coinPart+` a good (?:investment|buy)(?: (?:now|right now|today))?`, strongMatch),
+	newRule(Recommend, `(?:give me a |any )?(?:recommendation|advice) (?:for|on|about) `+coinPart, strongMatch),
+	newRule(Recommend, `(?:do you )?recommend (?:buying |investing in )?`+coinPart, weakMatch),
+
+	// Spanish recommendations
+	newRule(Recommend, `(?:deberia|debo|me conviene) (?:comprar|vender|invertir en|mantener) `+coinPart+`(?: (?:ahora|hoy))?`, strongMatch),
+	newRule(Recommend, `(?:es )?`+coinPart+` (?:es )?una buena (?:inversion|compra)(?: (?:ahora|hoy))?`, strongMatch),
+	newRule(Recommend, `vale la pena (?:comprar|invertir en) `+coinPart+`(?: (?:ahora|hoy))?`, strongMatch),
+	newRule(Recommend, `(?:dame una |alguna )?(?:recomendacion|consejo) (?:para|sobre|de|con) `+coinPart, strongMatch),
+	newRule(Recommend, `(?:me )?recomiendas `+coinPart, weakMatch),
+
+	// Help, only as the whole message
+	newRule(Help, `(?:help|help me|commands|menu|what can you do|what do you do|how do i use (?:this|you)|what commands (?:are there|do you have))`, exactMatch),
+	newRule(Help, `(?:ayuda|ayudame|comandos|menu|que puedes hacer|que sabes hacer|como te uso|que comandos (?:hay|tienes))`, exactMatch),
+}
+
+// currencies maps currency words to currency codes. Three-letter codes are
+// accepted as they are.
+var currencies = map[string]string{
+	"euro": "eur", "euros": "eur",
+	"dollar": "usd", "dollars": "usd", "us dollars": "usd", "dolar": "usd", "dolares": "usd",
+	"pound": "gbp", "pounds": "gbp", "libra": "gbp", "libras": "gbp",
+	"yen": "jpy", "yenes": "jpy",
+	"real": "brl", "reais": "brl", "reales": "brl",
+	"rupee": "inr", "rupees": "inr", "rupias": "inr",
+	"bitcoin": "btc", "ether": "eth", "ethereum": "eth",
+}
+
+// fillers are removed from the start and end of messages before matching
+var fillers = []string{"please", "pls", "por favor", "hey", "hi", "hello", "hola", "bot", "blockmind"}
+
+// articles are removed from the start of captured coins
+var articles = []string{"the ", "el ", "la ", "los ", "las "}
+
+// accents folds the accented letters of Spanish and Portuguese
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a",
+	"é", "e", "ê", "e",
+	"í", "i",
+	"ó", "o", "ô", "o", "õ", "o",
+	"ú", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// punctuation is removed from messages; commas are kept to separate coins
+var punctuation = strings.NewReplacer("¿", "", "?", "", "¡", "", "!", "", "'", "", "’", "", ":", "", ";", "")
+
+// normalize lowercases a message and removes accents, punctuation and fillers
+func normalize(text string) string {
+	text = punctuation.Replace(accents.Replace(strings.ToLower(text)))
+	text = strings.TrimRight(strings.TrimSpace(text), ".")
+	text = strings.Join(strings.Fields(text), " ")
+
+	for _, filler := range fillers {
+		text = strings.TrimPrefix(text, filler+", ")
+		text = strings.TrimPrefix(text, filler+" ")
+		text = strings.TrimSuffix(text, ", "+filler)
+		text = strings.TrimSuffix(text, " "+filler)
+	}
+	return strings.TrimSpace(text)
+}
+
+// matchRules returns the first rule matching the message
+func matchRules(text string) (Intent, bool) {
+	normalized := normalize(text)
+	for _, r := range rules {
+		match := r.pattern.FindStringSubmatch(normalized)
+		if match == nil {
+			continue
+		}
+
+		// The coins are the first group and the currency, when the rule has
+		// one, the second
+		in := Intent{Command: r.command, Confidence: r.confidence}
+		if len(match) > 1 {
+			in.Coins = splitCoins(match[1])
+		}
+		if len(match) > 2 && match[2] != "" {
+			currency, ok := currencyCode(match[2])
+			if !ok {
+				continue
+			}
+			in.Currency = currency
+		}
+		return in, true
+	}
+	return Intent{}, false
+}
+
+// splitCoins splits the captured coins and drops leading articles
+func splitCoins(part string) []string {
+	part = strings.ReplaceAll(part, " and ", ",")
+	part = strings.ReplaceAll(part, " y ", ",")
+
+	var coins []string
+	for _, coin := range strings.Split(part, ",") {
+		coin = strings.TrimSpace(coin)
+		for _, article := range articles {
+			coin = strings.TrimPrefix(coin, article)
+		}
+		if coin != "" {
+			coins = append(coins, coin)
+		}
+	}
+	return coins
+}
+
+// currencyCode returns the code of a currency word or code
+func currencyCode(word string) (string, bool) {
+	word = strings.TrimSpace(word)
+	if code, ok := currencies[word]; ok {
+		return code, true
+	}
+	if len(word) == 3 && !strings.Contains(word, " ") {
+		return word, true
+	}
+	return "", false
+}
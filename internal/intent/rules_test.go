@@ -0,0 +1,223 @@
+package intent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// knownCoins is a KnownCoin over a small fixed coin list
+func knownCoins(ctx context.Context, term string) bool {
+	switch term {
+	case "bitcoin", "btc", "eth", "ethereum", "ether", "sol", "solana", "shiba inu", "cardano", "doge":
+		return true
+	}
+	return false
+}
+
+func TestMatchRules(t *testing.T) {
+	tests := []struct {
+		text string
+		want Intent
+	}{
+		// English prices
+		{"What's the price of eth in euros?", Intent{Command: Price, Coins: []string{"eth"}, Currency: "eur", Confidence: strongMatch}},
+		{"what is the current price of bitcoin", Intent{Command: Price, Coins: []string{"bitcoin"}, Confidence: strongMatch}},
+		{"price of btc, eth and sol in gbp", Intent{Command: Price, Coins: []string{"btc", "eth", "sol"}, Currency: "gbp", Confidence: strongMatch}},
+		{"Hey, how much is shiba inu worth right now?", Intent{Command: Price, Coins: []string{"shiba inu"}, Confidence: strongMatch}},
+		{"how much does one ether cost in dollars", Intent{Command: Price, Coins: []string{"ether"}, Currency: "usd", Confidence: strongMatch}},
+		{"btc price today please", Intent{Command: Price, Coins: []string{"btc"}, Confidence: weakMatch}},
+		{"show me the price for the cardano in jpy", Intent{Command: Price, Coins: []string{"cardano"}, Currency: "jpy", Confidence: strongMatch}},
+
+		// Spanish prices
+		{"¿Cuál es el precio de Ethereum en euros?", Intent{Command: Price, Coins: []string{"ethereum"}, Currency: "eur", Confidence: strongMatch}},
+		{"¿A cuánto está el bitcoin hoy?", Intent{Command: Price, Coins: []string{"bitcoin"}, Confidence: strongMatch}},
+		{"cuanto vale solana y cardano en dolares", Intent{Command: Price, Coins: []string{"solana", "cardano"}, Currency: "usd", Confidence: strongMatch}},
+		{"dame el precio del btc por favor", Intent{Command: Price, Coins: []string{"btc"}, Confidence: strongMatch}},
+
+		// English recommendations
+		{"Should I buy ETH now?", Intent{Command: Recommend, Coins: []string{"eth"}, Confidence: strongMatch}},
+		{"is doge a good investment", Intent{Command: Recommend, Coins: []string{"doge"}, Confidence: strongMatch}},
+		{"any advice on solana?", Intent{Command: Recommend, Coins: []string{"solana"}, Confidence: strongMatch}},
+		{"do you recommend buying cardano", Intent{Command: Recommend, Coins: []string{"cardano"}, Confidence: weakMatch}},
+
+		// Spanish recommendations
+		{"¿Debería comprar bitcoin?", Intent{Command: Recommend, Coins: []string{"bitcoin"}, Confidence: strongMatch}},
+		{"¿Es ethereum una buena inversión ahora?", Intent{Command: Recommend, Coins: []string{"ethereum"}, Confidence: strongMatch}},
+		{"vale la pena invertir en solana", Intent{Command: Recommend, Coins: []string{"solana"}, Confidence: strongMatch}},
+		{"dame una recomendación sobre el btc", Intent{Command: Recommend, Coins: []string{"btc"}, Confidence: strongMatch}},
+		{"me recomiendas doge", Intent{Command: Recommend, Coins: []string{"doge"}, Confidence: weakMatch}},
+
+		// Help
+		{"help", Intent{Command: Help, Confidence: exactMatch}},
+		{"What can you do?", Intent{Command: Help, Confidence: exactMatch}},
+		{"¡Ayuda!", Intent{Command: Help, Confidence: exactMatch}},
+		{"hola, ¿qué puedes hacer?", Intent{Command: Help, Confidence: exactMatch}},
+	}
+	for _, tt := range tests {
+		got, ok := matchRules(tt.text)
+		if !ok {
+			t.Errorf("matchRules(%q) did not match, want %+v", tt.text, tt.want)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("matchRules(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestMatchRulesIgnoresQuestions(t *testing.T) {
+	for _, text := range []string{
+		"What is blockchain?",
+		"how does bitcoin mining work",
+		"explain proof of stake",
+		"¿Qué es una wallet?",
+		"who created ethereum",
+		"can you help me understand defi",
+		"the price of eth went up a lot yesterday, why",
+		"price of btc in something weird",
+		"",
+	} {
+		if got, ok := matchRules(text); ok {
+			t.Errorf("matchRules(%q) = %+v, want no match", text, got)
+		}
+	}
+}
+
+func TestRoute(t *testing.T) {
+	router := NewRouter(knownCoins, nil, 0.7)
+	tests := []struct {
+		text string
+		want Intent
+		ok   bool
+	}{
+		{"what's the price of eth in euros", Intent{Command: Price, Coins: []string{"eth"}, Currency: "eur", Confidence: strongMatch}, true},
+		{"¿debería vender doge?", Intent{Command: Recommend, Coins: []string{"doge"}, Confidence: strongMatch}, true},
+		{"commands", Intent{Command: Help, Confidence: exactMatch}, true},
+		// Unknown coins halve the confidence, below the threshold
+		{"what is the price of fame", Intent{}, false},
+		{"¿cuál es el precio de la fama?", Intent{}, false},
+		{"how much is the fish", Intent{}, false},
+		// One unknown coin among known ones is enough
+		{"price of btc and fame", Intent{}, false},
+		// A recommendation is about a single coin
+		{"should i buy btc and eth", Intent{}, false},
+		{"What is blockchain?", Intent{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := router.Route(context.Background(), tt.text)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Route(%q) = %+v, %v, want %+v, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestUnknownCoinPenalty(t *testing.T) {
+	router := NewRouter(knownCoins, nil, 0)
+	got, ok := router.Route(context.Background(), "what is the price of fame")
+	if !ok {
+		t.Fatal("Route() did not route with a zero threshold")
+	}
+	if want := strongMatch * unknownCoinPenalty; got.Confidence != want {
+		t.Errorf("Route() confidence = %v, want %v", got.Confidence, want)
+	}
+
+	// Without a coin list every coin is taken as known
+	got, _ = NewRouter(nil, nil, 0).Route(context.Background(), "what is the price of fame")
+	if got.Confidence != strongMatch {
+		t.Errorf("Route() without KnownCoin confidence = %v, want %v", got.Confidence, strongMatch)
+	}
+}
+
+func TestRouteFallsBackToClassifier(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		classified Intent
+		err        error
+		want       Intent
+		ok         bool
+		asked      bool
+	}{
+		{
+			name:       "no rule matches",
+			text:       "is it a good moment to get into ether",
+			classified: Intent{Command: Recommend, Coins: []string{"ether"}, Confidence: 0.8},
+			want:       Intent{Command: Recommend, Coins: []string{"ether"}, Confidence: 0.8},
+			ok:         true, asked: true,
+		},
+		{
+			name:       "rule below the threshold",
+			text:       "what is the price of fame",
+			classified: Intent{},
+			ok:         false, asked: true,
+		},
+		{
+			name:       "classifier below the threshold",
+			text:       "thinking about solana",
+			classified: Intent{Command: Recommend, Coins: []string{"solana"}, Confidence: 0.6},
+			ok:         false, asked: true,
+		},
+		{
+			name:       "classifier names an unknown coin",
+			text:       "my cat loves tuna, is it worth it",
+			classified: Intent{Command: Price, Coins: []string{"tuna"}, Confidence: 0.9},
+			ok:         false, asked: true,
+		},
+		{
+			name:       "classifier names an unsupported command",
+			text:       "set an alert for btc",
+			classified: Intent{Command: "alert", Coins: []string{"btc"}, Confidence: 0.9},
+			ok:         false, asked: true,
+		},
+		{
+			name:  "classifier fails",
+			text:  "tell me about eth",
+			err:   errors.New("model unavailable"),
+			ok:    false,
+			asked: true,
+		},
+		{
+			name:  "confident rule skips the classifier",
+			text:  "price of btc",
+			want:  Intent{Command: Price, Coins: []string{"btc"}, Confidence: strongMatch},
+			ok:    true,
+			asked: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asked := false
+			classifier := func(ctx context.Context, text string) (Intent, error) {
+				asked = true
+				return tt.classified, tt.err
+			}
+
+			got, ok := NewRouter(knownCoins, classifier, 0.7).Route(context.Background(), tt.text)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Route(%q) = %+v, %v, want %+v, %v", tt.text, got, ok, tt.want, tt.ok)
+			}
+			if asked != tt.asked {
+				t.Errorf("classifier asked = %v, want %v", asked, tt.asked)
+			}
+		})
+	}
+}
+
+func TestIntentArgs(t *testing.T) {
+	tests := []struct {
+		in   Intent
+		want []string
+	}{
+		{Intent{Command: Price, Coins: []string{"eth"}, Currency: "eur"}, []string{"eth", "in", "eur"}},
+		{Intent{Command: Price, Coins: []string{"shiba inu", "btc"}}, []string{"shiba", "inu,", "btc"}},
+		{Intent{Command: Recommend, Coins: []string{"sol"}, Currency: "eur"}, []string{"sol"}},
+		{Intent{Command: Help}, nil},
+	}
+	for _, tt := range tests {
+		if got := tt.in.Args(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v.Args() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package knowledge
+
+import (
+	"sync"
+	"time"
+)
+
+// Stats describes the result of indexing the knowledge directory
+type Stats struct {
+	Documents int
+	Passages  int
+	Duration  time.Duration
+}
+
+// Base is the knowledge base of a directory. Searches use the last index
+// built, so reindexing does not block them.
+type Base struct {
+	dir string
+
+	mu    sync.RWMutex
+	index *Index
+}
+
+// NewBase creates a knowledge base for dir. It is empty until Reindex is called.
+func NewBase(dir string) *Base {
+	return &Base{dir: dir, index: NewIndex(nil)}
+}
+
+// Dir returns the directory the knowledge base is read from
+func (b *Base) Dir() string {
+	return b.dir
+}
+
+// Reindex reads the directory again and replaces the index. On error the
+// previous index is kept.
+func (b *Base) Reindex() (Stats, error) {
+	start := time.Now()
+	passages, documents, err := LoadDir(b.dir)
+	if err != nil {
+		return Stats{}, err
+	}
+	index := NewIndex(passages)
+
+	b.mu.Lock()
+	b.index = index
+	b.mu.Unlock()
+
+	return Stats{Documents: documents, Passages: len(passages), Duration: time.Since(start)}, nil
+}
+
+// Search returns up to limit passages relevant to the query. Passages scoring
+// less than half the best match are left out. A nil base finds nothing.
+func (b *Base) Search(query string, limit int) []Result {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.RLock()
+	index := b.index
+	b.mu.RUnlock()
+
+	results := index.Search(query, limit)
+	for i, result := range results {
+		if result.Score < results[0].Score/2 {
+			return results[:i]
+		}
+	}
+	return results
+}
@@ -0,0 +1,164 @@
+package knowledge
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// BM25 parameters, the usual defaults
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Passage is a piece of a document small enough to send to the model
+type Passage struct {
+	// Source is the path of the document relative to the knowledge directory
+	Source string
+	// Heading is the closest markdown heading above the passage, if any
+	Heading string
+	Text    string
+}
+
+// Result is a passage matching a query
+type Result struct {
+	Passage
+	Score float64
+}
+
+// Index ranks passages for a query with BM25
+type Index struct {
+	passages  []Passage
+	terms     []map[string]int
+	lengths   []int
+	docFreq   map[string]int
+	avgLength float64
+}
+
+// NewIndex indexes the given passages
+func NewIndex(passages []Passage) *Index {
+	idx := &Index{
+		passages: passages,
+		terms:    make([]map[string]int, len(passages)),
+		lengths:  make([]int, len(passages)),
+		docFreq:  make(map[string]int),
+	}
+
+	total := 0
+	for i, passage := range passages {
+		tokens := tokenize(passage.Heading + " " + passage.Text)
+		counts := make(map[string]int, len(tokens))
+		for _, token := range tokens {
+			counts[token]++
+		}
+		for term := range counts {
+			idx.docFreq[term]++
+		}
+		idx.terms[i] = counts
+		idx.lengths[i] = len(tokens)
+		total += len(tokens)
+	}
+	if len(passages) > 0 {
+		idx.avgLength = float64(total) / float64(len(passages))
+	}
+	return idx
+}
+
+// Len returns the number of indexed passages
+func (idx *Index) Len() int {
+	return len(idx.passages)
+}
+
+// Search returns up to limit passages matching the query, best first
+func (idx *Index) Search(query string, limit int) []Result {
+	queryTerms := unique(tokenize(query))
+	if len(queryTerms) == 0 || len(idx.passages) == 0 || limit <= 0 {
+		return nil
+	}
+
+	n := float64(len(idx.passages))
+	var results []Result
+	for i, counts := range idx.terms {
+		score := 0.0
+		for _, term := range queryTerms {
+			freq := float64(counts[term])
+			if freq == 0 {
+				continue
+			}
+			df := float64(idx.docFreq[term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(idx.lengths[i])/idx.avgLength)
+			score += idf * freq * (bm25K1 + 1) / (freq + norm)
+		}
+		if score > 0 {
+			results = append(results, Result{Passage: idx.passages[i], Score: score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// accents folds Spanish and Portuguese accented letters so "información"
+// matches "informacion"
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a",
+	"é", "e", "ê", "e",
+	"í", "i",
+	"ó", "o", "ô", "o", "õ", "o",
+	"ú", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// stopwords are frequent English and Spanish words that carry no meaning for retrieval
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"can": true, "do": true, "does": true, "for": true, "from": true, "how": true, "i": true, "in": true,
+	"is": true, "it": true, "its": true, "of": true, "on": true, "or": true, "that": true, "the": true,
+	"this": true, "to": true, "was": true, "what": true, "when": true, "where": true, "which": true,
+	"who": true, "why": true, "will": true, "with": true, "you": true,
+	"al": true, "como": true, "con": true, "cual": true, "de": true, "del": true, "el": true, "en": true,
+	"es": true, "la": true, "las": true, "lo": true, "los": true, "mas": true, "para": true, "por": true,
+	"que": true, "se": true, "su": true, "un": true, "una": true, "y": true,
+}
+
+// tokenize splits text into lowercase terms without accents or stopwords. A
+// trailing plural "s" is dropped so "tokens" matches "token".
+func tokenize(text string) []string {
+	text = accents.Replace(strings.ToLower(text))
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := words[:0]
+	for _, word := range words {
+		if stopwords[word] {
+			continue
+		}
+		if len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+			word = word[:len(word)-1]
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// unique removes repeated terms, keeping the first occurrence
+func unique(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	result := terms[:0]
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			result = append(result, term)
+		}
+	}
+	return result
+}
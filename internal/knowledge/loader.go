@@ -0,0 +1,95 @@
+package knowledge
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxPassageChars bounds the size of a passage; paragraphs are merged up to it
+const maxPassageChars = 1000
+
+// documentExtensions are the file types read from the knowledge directory
+var documentExtensions = map[string]bool{
+	".md":       true,
+	".markdown": true,
+	".txt":      true,
+}
+
+// LoadDir reads every markdown and text document below dir and splits them
+// into passages. It returns the passages and the number of documents read.
+func LoadDir(dir string) ([]Passage, int, error) {
+	var passages []Passage
+	documents := 0
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !documentExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		source, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		documents++
+		passages = append(passages, splitDocument(filepath.ToSlash(source), string(content))...)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return passages, documents, nil
+}
+
+// splitDocument splits a document into passages at markdown headings and,
+// within a section, at paragraph boundaries once a passage is large enough
+func splitDocument(source, content string) []Passage {
+	var passages []Passage
+	heading := ""
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			passages = append(passages, Passage{Source: source, Heading: heading, Text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for _, paragraph := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+
+		// A heading starts a new section; text below it stays in the paragraph
+		if strings.HasPrefix(paragraph, "#") {
+			flush()
+			line, rest, _ := strings.Cut(paragraph, "\n")
+			heading = strings.TrimSpace(strings.TrimLeft(line, "#"))
+			paragraph = strings.TrimSpace(rest)
+			if paragraph == "" {
+				continue
+			}
+		}
+
+		if text.Len() > 0 && text.Len()+len(paragraph) > maxPassageChars {
+			flush()
+		}
+		if text.Len() > 0 {
+			text.WriteString("\n\n")
+		}
+		text.WriteString(paragraph)
+	}
+	flush()
+
+	return passages
+}
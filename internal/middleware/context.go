@@ -12,6 +12,7 @@ type ContextKey string
 // Context keys
 const (
 	UserIDKey ContextKey = "user_jid"
+	ChatIDKey ContextKey = "chat_jid"
 )
 
 // GetUserID extracts the user ID from the context
@@ -29,6 +30,33 @@ func GetUserID(ctx context.Context) (string, bool) {
 	return "", false
 }
 
+// GetUserJID extracts the full WhatsApp JID (e.g. phone@s.whatsapp.net) of the
+// sender from the context, for features that store data per user or notify them
+func GetUserJID(ctx context.Context) (string, bool) {
+	if jid, ok := ctx.Value(UserIDKey).(string); ok && jid != "" {
+		return jid, true
+	}
+
+	// Fall back to string key for backward compatibility
+	if jid, ok := ctx.Value(string(UserIDKey)).(string); ok && jid != "" {
+		return jid, true
+	}
+
+	return "", false
+}
+
+// GetChatJID extracts the JID of the chat the message came from, which is a
+// group rather than the sender for group messages
+func GetChatJID(ctx context.Context) (string, bool) {
+	jid, ok := ctx.Value(ChatIDKey).(string)
+	return jid, ok && jid != ""
+}
+
+// WithChatJID returns a new context with the JID of the chat
+func WithChatJID(ctx context.Context, chatJID string) context.Context {
+	return context.WithValue(ctx, ChatIDKey, chatJID)
+}
+
 // WithUserID returns a new context with the user ID
 func WithUserID(ctx context.Context, userID string) context.Context {
 	return context.WithValue(ctx, UserIDKey, userID)
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"blockmind/internal/i18n"
 	"context"
 	"fmt"
 	"sync"
@@ -51,7 +52,7 @@ func RateLimiter(limit int, period time.Duration) func(HandlerFunc) HandlerFunc
 
 			// Check if limit is reached
 			if ul.count >= limit {
-				return "You're sending messages too quickly. Please wait a moment.", nil
+				return i18n.T(ctx, "middleware.rate_limited"), nil
 			}
 
 			// Increment counter
@@ -91,7 +92,7 @@ func Timeout(duration time.Duration) func(HandlerFunc) HandlerFunc {
 				return result.response, result.err
 			case <-ctx.Done():
 				if ctx.Err() == context.DeadlineExceeded {
-					return "Request timed out. Please try again later.", nil
+					return i18n.T(ctx, "middleware.timeout"), nil
 				}
 				return "", ctx.Err()
 			}
@@ -0,0 +1,162 @@
+package portfolio
+
+import (
+	"blockmind/internal/crypto"
+	"blockmind/internal/i18n"
+	"blockmind/internal/storage"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Holding is a valued portfolio position
+type Holding struct {
+	Coin       crypto.Coin
+	Amount     float64
+	Price      float64
+	Value      float64
+	Cost       float64
+	PnL        float64
+	PnLPct     float64
+	Allocation float64
+	// Priced is false when no current price was available for the coin
+	Priced bool
+}
+
+// Report is the valuation of a portfolio in a single currency
+type Report struct {
+	Currency   string
+	Holdings   []Holding
+	TotalValue float64
+	TotalCost  float64
+	PnL        float64
+	PnLPct     float64
+}
+
+// Evaluate values positions in currency using one batched price request.
+// Cost bases recorded in another currency are converted at the current rate,
+// derived from the coin's own quotes in both currencies.
+func Evaluate(ctx context.Context, provider crypto.MarketDataProvider, positions []storage.Position, currency string) (*Report, error) {
+	report := &Report{Currency: currency}
+	if len(positions) == 0 {
+		return report, nil
+	}
+
+	coins := make([]crypto.Coin, 0, len(positions))
+	currencies := []string{currency}
+	seen := map[string]bool{currency: true}
+	for _, position := range positions {
+		coins = append(coins, positionCoin(position))
+		if !seen[position.Currency] {
+			seen[position.Currency] = true
+			currencies = append(currencies, position.Currency)
+		}
+	}
+
+	quotes, err := provider.SpotPrices(ctx, coins, currencies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch portfolio prices: %w", err)
+	}
+
+	for _, position := range positions {
+		holding := Holding{Coin: positionCoin(position), Amount: position.Amount}
+
+		quote, ok := quotes.Get(position.CoinID, currency)
+		if ok {
+			holding.Priced = true
+			holding.Price = quote.Price
+			holding.Value = position.Amount * quote.Price
+			holding.Cost = position.Cost
+			if position.Currency != currency {
+				costQuote, ok := quotes.Get(position.CoinID, position.Currency)
+				if ok && costQuote.Price > 0 {
+					holding.Cost = position.Cost * quote.Price / costQuote.Price
+				} else {
+					holding.Priced = false
+				}
+			}
+		}
+
+		if holding.Priced {
+			holding.PnL = holding.Value - holding.Cost
+			if holding.Cost > 0 {
+				holding.PnLPct = holding.PnL / holding.Cost * 100
+			}
+			report.TotalValue += holding.Value
+			report.TotalCost += holding.Cost
+		}
+		report.Holdings = append(report.Holdings, holding)
+	}
+
+	report.PnL = report.TotalValue - report.TotalCost
+	if report.TotalCost > 0 {
+		report.PnLPct = report.PnL / report.TotalCost * 100
+	}
+	for i := range report.Holdings {
+		if report.Holdings[i].Priced && report.TotalValue > 0 {
+			report.Holdings[i].Allocation = report.Holdings[i].Value / report.TotalValue * 100
+		}
+	}
+
+	// Largest holdings first
+	sort.SliceStable(report.Holdings, func(i, j int) bool {
+		return report.Holdings[i].Value > report.Holdings[j].Value
+	})
+	return report, nil
+}
+
+// Format renders the report as a WhatsApp message in locale
+func (r *Report) Format(locale string) string {
+	na := i18n.Translate(locale, "table.na")
+	rows := [][]string{{
+		i18n.Translate(locale, "table.coin"), i18n.Translate(locale, "table.amount"),
+		i18n.Translate(locale, "table.value"), i18n.Translate(locale, "table.pnl"),
+		i18n.Translate(locale, "table.allocation"),
+	}}
+	var unpriced []string
+	for _, holding := range r.Holdings {
+		symbol := strings.ToUpper(holding.Coin.Symbol)
+		if !holding.Priced {
+			unpriced = append(unpriced, symbol)
+			rows = append(rows, []string{symbol, FormatAmount(holding.Amount), na, na, na})
+			continue
+		}
+		rows = append(rows, []string{
+			symbol,
+			FormatAmount(holding.Amount),
+			crypto.FormatPrice(holding.Value, r.Currency),
+			fmt.Sprintf("%+.2f%%", holding.PnLPct),
+			fmt.Sprintf("%.1f%%", holding.Allocation),
+		})
+	}
+
+	var reply strings.Builder
+	reply.WriteString(i18n.Translate(locale, "portfolio.report.title", strings.ToUpper(r.Currency)) + "\n")
+	reply.WriteString("```\n" + crypto.RenderTable(rows) + "```\n")
+	reply.WriteString(i18n.Translate(locale, "portfolio.report.total_value", crypto.FormatPrice(r.TotalValue, r.Currency)) + "\n")
+	reply.WriteString(i18n.Translate(locale, "portfolio.report.cost_basis", crypto.FormatPrice(r.TotalCost, r.Currency)) + "\n")
+	reply.WriteString(i18n.Translate(locale, "portfolio.report.pnl",
+		sign(r.PnL), crypto.FormatPrice(r.PnL, r.Currency), crypto.FormatChange(r.PnLPct)))
+	if len(unpriced) > 0 {
+		reply.WriteString("\n" + i18n.Translate(locale, "portfolio.report.unpriced", strings.Join(unpriced, ", ")))
+	}
+	return reply.String()
+}
+
+func positionCoin(position storage.Position) crypto.Coin {
+	return crypto.Coin{ID: position.CoinID, Symbol: position.CoinSymbol, Name: position.CoinName}
+}
+
+// FormatAmount formats a coin amount without trailing zeros, e.g. "0.5"
+func FormatAmount(amount float64) string {
+	text := strings.TrimRight(fmt.Sprintf("%.8f", amount), "0")
+	return strings.TrimSuffix(text, ".")
+}
+
+func sign(value float64) string {
+	if value > 0 {
+		return "+"
+	}
+	return ""
+}
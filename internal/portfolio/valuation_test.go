@@ -0,0 +1,158 @@
+package portfolio
+
+import (
+	"blockmind/internal/crypto"
+	"blockmind/internal/storage"
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// fakeProvider answers SpotPrices with fixed quotes and records the request
+type fakeProvider struct {
+	quotes     crypto.Quotes
+	err        error
+	calls      int
+	coins      []string
+	currencies []string
+}
+
+func (p *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *fakeProvider) SpotPrices(ctx context.Context, coins []crypto.Coin, vsCurrencies []string) (crypto.Quotes, error) {
+	p.calls++
+	p.coins = nil
+	for _, coin := range coins {
+		p.coins = append(p.coins, coin.ID)
+	}
+	p.currencies = vsCurrencies
+	return p.quotes, p.err
+}
+
+func (p *fakeProvider) MarketSnapshot(ctx context.Context, coin crypto.Coin, vsCurrency string) (*crypto.Snapshot, error) {
+	return nil, crypto.ErrNotSupported
+}
+
+func (p *fakeProvider) History(ctx context.Context, coin crypto.Coin, vsCurrency string, days int) ([]crypto.PricePoint, error) {
+	return nil, crypto.ErrNotSupported
+}
+
+func (p *fakeProvider) CoinInfo(ctx context.Context, coin crypto.Coin) (*crypto.CoinInfo, error) {
+	return nil, crypto.ErrNotSupported
+}
+
+// position is a holding of amount coins bought for cost in currency
+func position(coinID string, amount, cost float64, currency string) storage.Position {
+	return storage.Position{UserJID: "alice", CoinID: coinID, CoinSymbol: coinID[:3], Amount: amount, Cost: cost, Currency: currency}
+}
+
+// closeTo reports whether two values are equal up to rounding
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestEvaluate(t *testing.T) {
+	provider := &fakeProvider{quotes: crypto.Quotes{
+		"bitcoin":  {"usd": {Price: 60000}},
+		"ethereum": {"usd": {Price: 3000}},
+	}}
+	positions := []storage.Position{
+		position("ethereum", 10, 40000, "usd"),
+		position("bitcoin", 0.5, 20000, "usd"),
+	}
+
+	report, err := Evaluate(context.Background(), provider, positions, "usd")
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if provider.calls != 1 || !reflect.DeepEqual(provider.currencies, []string{"usd"}) {
+		t.Errorf("prices fetched %d times in %v, want one batch in usd", provider.calls, provider.currencies)
+	}
+
+	if report.TotalValue != 60000 || report.TotalCost != 60000 || report.PnL != 0 || report.PnLPct != 0 {
+		t.Errorf("Evaluate() totals = %+v, want 60000 value at 60000 cost", report)
+	}
+
+	// Largest holdings first
+	eth, btc := report.Holdings[0], report.Holdings[1]
+	if eth.Coin.ID != "ethereum" || btc.Coin.ID != "bitcoin" {
+		t.Fatalf("Holdings = %s, %s, want ethereum first", eth.Coin.ID, btc.Coin.ID)
+	}
+	if eth.Value != 30000 || eth.PnL != -10000 || eth.PnLPct != -25 || eth.Allocation != 50 {
+		t.Errorf("ethereum holding = %+v, want 30000 at -25%%", eth)
+	}
+	if btc.Price != 60000 || btc.Value != 30000 || btc.PnL != 10000 || btc.PnLPct != 50 || !btc.Priced {
+		t.Errorf("bitcoin holding = %+v, want 30000 at +50%%", btc)
+	}
+}
+
+func TestEvaluateConvertsCostCurrency(t *testing.T) {
+	// Bitcoin trades at 60000 usd and 50000 eur, so a euro is worth 1.2 usd
+	provider := &fakeProvider{quotes: crypto.Quotes{
+		"bitcoin": {"usd": {Price: 60000}, "eur": {Price: 50000}},
+	}}
+	positions := []storage.Position{position("bitcoin", 1, 40000, "eur")}
+
+	report, err := Evaluate(context.Background(), provider, positions, "usd")
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if !reflect.DeepEqual(provider.currencies, []string{"usd", "eur"}) {
+		t.Errorf("prices fetched in %v, want usd and eur in one batch", provider.currencies)
+	}
+
+	holding := report.Holdings[0]
+	if !holding.Priced || !closeTo(holding.Cost, 48000) || !closeTo(holding.PnL, 12000) || !closeTo(holding.PnLPct, 25) {
+		t.Errorf("holding = %+v, want a 48000 usd cost and 25%% gain", holding)
+	}
+	if !closeTo(report.TotalCost, 48000) || !closeTo(report.PnLPct, 25) {
+		t.Errorf("report = %+v, want the converted cost in the totals", report)
+	}
+}
+
+func TestEvaluateUnpricedHoldings(t *testing.T) {
+	provider := &fakeProvider{quotes: crypto.Quotes{
+		"bitcoin":  {"usd": {Price: 60000}},
+		"ethereum": {"usd": {Price: 3000}},
+	}}
+	positions := []storage.Position{
+		position("bitcoin", 1, 50000, "usd"),
+		position("obscure", 100, 10, "usd"),
+		// No eur quote to convert the cost with
+		position("ethereum", 1, 2000, "eur"),
+	}
+
+	report, err := Evaluate(context.Background(), provider, positions, "usd")
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if report.TotalValue != 60000 || report.TotalCost != 50000 || report.PnL != 10000 {
+		t.Errorf("report = %+v, want only bitcoin in the totals", report)
+	}
+	for _, holding := range report.Holdings {
+		if priced := holding.Coin.ID == "bitcoin"; holding.Priced != priced {
+			t.Errorf("%s Priced = %v, want %v", holding.Coin.ID, holding.Priced, priced)
+		}
+		if !holding.Priced && holding.Allocation != 0 {
+			t.Errorf("%s Allocation = %v, want 0 without a price", holding.Coin.ID, holding.Allocation)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	provider := &fakeProvider{err: errors.New("rate limited")}
+
+	report, err := Evaluate(context.Background(), provider, nil, "usd")
+	if err != nil || len(report.Holdings) != 0 || provider.calls != 0 {
+		t.Errorf("Evaluate() of no positions = %+v, %v after %d calls, want an empty report without prices", report, err, provider.calls)
+	}
+
+	_, err = Evaluate(context.Background(), provider, []storage.Position{position("bitcoin", 1, 1, "usd")}, "usd")
+	if !errors.Is(err, provider.err) {
+		t.Errorf("Evaluate() error = %v, want the provider error", err)
+	}
+}
@@ -17,7 +17,8 @@ var (
 	homoglyphPattern = regexp.MustCompile(`\p{So}`) // Matches all Unicode symbols (category So)
 )
 
-// SanitizeInput cleans user input to prevent security issues
+// SanitizeInput cleans user input to prevent security issues. When the input
+// is rejected, the second result is the message key of the reason.
 func SanitizeInput(input string) (string, string) {
 	// Trim whitespace
 	sanitized := strings.TrimSpace(input)
@@ -27,11 +28,11 @@ func SanitizeInput(input string) (string, string) {
 
 	// Check for suspicious patterns and flag them
 	if scriptPattern.MatchString(sanitized) {
-		return "nil", "⚠️ Suspicious script detected in input"
+		return "nil", "security.script"
 	}
 
 	if sqlPattern.MatchString(sanitized) && len(sanitized) > 15 {
-		return "nil", "⚠️ Suspicious SQL syntax detected in input"
+		return "nil", "security.sql"
 	}
 
 	// Limit input length
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Alert conditions
+const (
+	AlertAbove = "above"
+	AlertBelow = "below"
+)
+
+// Alert is a price alert owned by a user and delivered to the chat it was
+// created in
+type Alert struct {
+	ID              int64
+	UserJID         string
+	ChatJID         string
+	CoinID          string
+	CoinSymbol      string
+	CoinName        string
+	Currency        string
+	Condition       string
+	Target          float64
+	Repeat          bool
+	Armed           bool
+	Active          bool
+	CreatedAt       time.Time
+	LastTriggeredAt time.Time
+}
+
+// Triggered reports whether the alert condition holds at the given price
+func (a Alert) Triggered(price float64) bool {
+	if a.Condition == AlertAbove {
+		return price >= a.Target
+	}
+	return price <= a.Target
+}
+
+const alertColumns = `id, user_jid, chat_jid, coin_id, coin_symbol, coin_name, currency, condition,
+	target, repeat, armed, active, created_at, last_triggered_at`
+
+// CreateAlert stores a new active alert and sets its ID
+func (s *Store) CreateAlert(ctx context.Context, alert *Alert) error {
+	alert.Armed = true
+	alert.Active = true
+	alert.CreatedAt = time.Now()
+
+	result, err := s.db.ExecContext(ctx, `INSERT INTO alerts
+		(user_jid, chat_jid, coin_id, coin_symbol, coin_name, currency, condition, target, repeat, armed, active, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, 1, 1, ?)`,
+		alert.UserJID, alert.ChatJID, alert.CoinID, alert.CoinSymbol, alert.CoinName, alert.Currency,
+		alert.Condition, alert.Target, alert.Repeat, alert.CreatedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to create alert: %w", err)
+	}
+
+	alert.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to read alert id: %w", err)
+	}
+	return nil
+}
+
+// ListAlerts returns the active alerts of a user, oldest first
+func (s *Store) ListAlerts(ctx context.Context, userJID string) ([]Alert, error) {
+	return s.queryAlerts(ctx, `SELECT `+alertColumns+` FROM alerts
+		WHERE user_jid = ? AND active = 1 ORDER BY id`, userJID)
+}
+
+// CountAlerts returns the number of active alerts of a user
+func (s *Store) CountAlerts(ctx context.Context, userJID string) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM alerts WHERE user_jid = ? AND active = 1`, userJID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count alerts: %w", err)
+	}
+	return count, nil
+}
+
+// ActiveAlerts returns the active alerts of all users
+func (s *Store) ActiveAlerts(ctx context.Context) ([]Alert, error) {
+	return s.queryAlerts(ctx, `SELECT `+alertColumns+` FROM alerts WHERE active = 1 ORDER BY id`)
+}
+
+// DeleteAlert removes an alert owned by the user
+func (s *Store) DeleteAlert(ctx context.Context, userJID string, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM alerts WHERE id = ? AND user_jid = ?`, id, userJID)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert: %w", err)
+	}
+	return requireAffected(result)
+}
+
+// MarkAlertTriggered records that an alert fired. One-shot alerts are
+// deactivated; repeating alerts are disarmed until the condition clears.
+func (s *Store) MarkAlertTriggered(ctx context.Context, id int64, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE alerts
+		SET armed = 0, active = repeat, last_triggered_at = ? WHERE id = ?`, at.Unix(), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark alert triggered: %w", err)
+	}
+	return nil
+}
+
+// RearmAlert arms a repeating alert again once its condition no longer holds
+func (s *Store) RearmAlert(ctx context.Context, id int64) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE alerts SET armed = 1 WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to rearm alert: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) queryAlerts(ctx context.Context, query string, args ...interface{}) ([]Alert, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []Alert
+	for rows.Next() {
+		var alert Alert
+		var createdAt int64
+		var lastTriggeredAt sql.NullInt64
+		if err := rows.Scan(&alert.ID, &alert.UserJID, &alert.ChatJID, &alert.CoinID, &alert.CoinSymbol, &alert.CoinName,
+			&alert.Currency, &alert.Condition, &alert.Target, &alert.Repeat, &alert.Armed, &alert.Active,
+			&createdAt, &lastTriggeredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+		alert.CreatedAt = time.Unix(createdAt, 0)
+		if lastTriggeredAt.Valid {
+			alert.LastTriggeredAt = time.Unix(lastTriggeredAt.Int64, 0)
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
+
+// requireAffected returns ErrNotFound when a statement changed no rows
+func requireAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCurrencyMismatch is returned when adding to a position tracked in another currency
+var ErrCurrencyMismatch = errors.New("position is tracked in another currency")
+
+// Position is the holding of one coin in a user's portfolio. Cost is the
+// total amount paid, in Currency.
+type Position struct {
+	UserJID    string
+	CoinID     string
+	CoinSymbol string
+	CoinName   string
+	Amount     float64
+	Cost       float64
+	Currency   string
+	UpdatedAt  time.Time
+}
+
+// AddToPosition adds a purchase of amount coins for cost to the user's position,
+// creating it if needed, and returns the updated position
+func (s *Store) AddToPosition(ctx context.Context, purchase Position) (Position, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Position{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	position, err := getPosition(ctx, tx, purchase.UserJID, purchase.CoinID)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		position = purchase
+	case err != nil:
+		return Position{}, err
+	case position.Currency != purchase.Currency:
+		return position, ErrCurrencyMismatch
+	default:
+		position.Amount += purchase.Amount
+		position.Cost += purchase.Cost
+	}
+	position.UpdatedAt = time.Now()
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO portfolio_positions
+		(user_jid, coin_id, coin_symbol, coin_name, amount, cost, currency, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_jid, coin_id) DO UPDATE SET
+			amount = excluded.amount, cost = excluded.cost, updated_at = excluded.updated_at`,
+		position.UserJID, position.CoinID, position.CoinSymbol, position.CoinName,
+		position.Amount, position.Cost, position.Currency, position.UpdatedAt.Unix())
+	if err != nil {
+		return Position{}, fmt.Errorf("failed to store position: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Position{}, fmt.Errorf("failed to commit position: %w", err)
+	}
+	return position, nil
+}
+
+// ReducePosition removes amount coins from the user's position at average
+// cost. An amount of zero or at least the position size removes the position
+// entirely. The remaining position is returned, with a zero Amount if removed.
+func (s *Store) ReducePosition(ctx context.Context, userJID, coinID string, amount float64) (Position, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Position{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	position, err := getPosition(ctx, tx, userJID, coinID)
+	if err != nil {
+		return Position{}, err
+	}
+
+	if amount <= 0 || amount >= position.Amount {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM portfolio_positions WHERE user_jid = ? AND coin_id = ?`,
+			userJID, coinID); err != nil {
+			return Position{}, fmt.Errorf("failed to remove position: %w", err)
+		}
+		position.Amount, position.Cost = 0, 0
+	} else {
+		position.Cost *= (position.Amount - amount) / position.Amount
+		position.Amount -= amount
+		position.UpdatedAt = time.Now()
+		if _, err := tx.ExecContext(ctx, `UPDATE portfolio_positions SET amount = ?, cost = ?, updated_at = ?
+			WHERE user_jid = ? AND coin_id = ?`,
+			position.Amount, position.Cost, position.UpdatedAt.Unix(), userJID, coinID); err != nil {
+			return Position{}, fmt.Errorf("failed to update position: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Position{}, fmt.Errorf("failed to commit position: %w", err)
+	}
+	return position, nil
+}
+
+// ListPositions returns all positions of a user ordered by coin
+func (s *Store) ListPositions(ctx context.Context, userJID string) ([]Position, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT user_jid, coin_id, coin_symbol, coin_name, amount, cost, currency, updated_at
+		FROM portfolio_positions WHERE user_jid = ? ORDER BY coin_id`, userJID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query positions: %w", err)
+	}
+	defer rows.Close()
+
+	var positions []Position
+	for rows.Next() {
+		position, err := scanPosition(rows)
+		if err != nil {
+			return nil, err
+		}
+		positions = append(positions, position)
+	}
+	return positions, rows.Err()
+}
+
+func getPosition(ctx context.Context, tx *sql.Tx, userJID, coinID string) (Position, error) {
+	row := tx.QueryRowContext(ctx, `SELECT user_jid, coin_id, coin_symbol, coin_name, amount, cost, currency, updated_at
+		FROM portfolio_positions WHERE user_jid = ? AND coin_id = ?`, userJID, coinID)
+	position, err := scanPosition(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Position{}, ErrNotFound
+	}
+	return position, err
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPosition(row scanner) (Position, error) {
+	var position Position
+	var updatedAt int64
+	if err := row.Scan(&position.UserJID, &position.CoinID, &position.CoinSymbol, &position.CoinName,
+		&position.Amount, &position.Cost, &position.Currency, &updatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Position{}, err
+		}
+		return Position{}, fmt.Errorf("failed to scan position: %w", err)
+	}
+	position.UpdatedAt = time.Unix(updatedAt, 0)
+	return position, nil
+}
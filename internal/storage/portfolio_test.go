@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+// newTestStore opens a migrated in-memory database
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// purchase is a bitcoin purchase by alice
+func purchase(amount, cost float64, currency string) Position {
+	return Position{
+		UserJID:    "alice",
+		CoinID:     "bitcoin",
+		CoinSymbol: "btc",
+		CoinName:   "Bitcoin",
+		Amount:     amount,
+		Cost:       cost,
+		Currency:   currency,
+	}
+}
+
+// closeTo reports whether two amounts are equal up to rounding
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestAddToPosition(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	position, err := store.AddToPosition(ctx, purchase(1, 30000, "usd"))
+	if err != nil {
+		t.Fatalf("AddToPosition() error = %v", err)
+	}
+	if position.Amount != 1 || position.Cost != 30000 {
+		t.Errorf("AddToPosition() = %+v, want 1 for 30000", position)
+	}
+
+	// The cost basis is the total paid, so the average is weighted by amount
+	position, err = store.AddToPosition(ctx, purchase(0.5, 30000, "usd"))
+	if err != nil {
+		t.Fatalf("AddToPosition() error = %v", err)
+	}
+	if position.Amount != 1.5 || position.Cost != 60000 || position.Cost/position.Amount != 40000 {
+		t.Errorf("AddToPosition() = %+v, want 1.5 for 60000 at 40000 each", position)
+	}
+
+	if _, err := store.AddToPosition(ctx, purchase(1, 28000, "eur")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("AddToPosition() in eur error = %v, want ErrCurrencyMismatch", err)
+	}
+
+	// Other users' positions are separate
+	other := purchase(2, 10, "eur")
+	other.UserJID = "bob"
+	if _, err := store.AddToPosition(ctx, other); err != nil {
+		t.Fatalf("AddToPosition() for bob error = %v", err)
+	}
+
+	positions, err := store.ListPositions(ctx, "alice")
+	if err != nil {
+		t.Fatalf("ListPositions() error = %v", err)
+	}
+	if len(positions) != 1 || positions[0].Amount != 1.5 || positions[0].Cost != 60000 || positions[0].Currency != "usd" {
+		t.Errorf("ListPositions() = %+v, want the stored position unchanged by the mismatch", positions)
+	}
+}
+
+func TestReducePosition(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	if _, err := store.AddToPosition(ctx, purchase(2, 60000, "usd")); err != nil {
+		t.Fatalf("AddToPosition() error = %v", err)
+	}
+
+	// A partial sale keeps the average cost
+	position, err := store.ReducePosition(ctx, "alice", "bitcoin", 0.5)
+	if err != nil {
+		t.Fatalf("ReducePosition() error = %v", err)
+	}
+	if position.Amount != 1.5 || !closeTo(position.Cost, 45000) {
+		t.Errorf("ReducePosition(0.5) = %+v, want 1.5 for 45000", position)
+	}
+	positions, _ := store.ListPositions(ctx, "alice")
+	if len(positions) != 1 || positions[0].Amount != 1.5 || !closeTo(positions[0].Cost, 45000) {
+		t.Errorf("ListPositions() = %+v, want the reduced position", positions)
+	}
+
+	// Selling more than held removes the position
+	position, err = store.ReducePosition(ctx, "alice", "bitcoin", 5)
+	if err != nil {
+		t.Fatalf("ReducePosition(5) error = %v", err)
+	}
+	if position.Amount != 0 || position.Cost != 0 {
+		t.Errorf("ReducePosition(5) = %+v, want a removed position", position)
+	}
+	if positions, _ := store.ListPositions(ctx, "alice"); len(positions) != 0 {
+		t.Errorf("ListPositions() = %+v, want none", positions)
+	}
+
+	if _, err := store.ReducePosition(ctx, "alice", "bitcoin", 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ReducePosition() of a removed position error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestReducePositionEntirely(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	for _, amount := range []float64{0, 1} {
+		if _, err := store.AddToPosition(ctx, purchase(1, 30000, "usd")); err != nil {
+			t.Fatalf("AddToPosition() error = %v", err)
+		}
+		position, err := store.ReducePosition(ctx, "alice", "bitcoin", amount)
+		if err != nil {
+			t.Fatalf("ReducePosition(%v) error = %v", amount, err)
+		}
+		if position.Amount != 0 {
+			t.Errorf("ReducePosition(%v) = %+v, want a removed position", amount, position)
+		}
+		if positions, _ := store.ListPositions(ctx, "alice"); len(positions) != 0 {
+			t.Errorf("ListPositions() after ReducePosition(%v) = %+v, want none", amount, positions)
+		}
+	}
+
+	// Another user cannot reduce alice's position
+	store.AddToPosition(ctx, purchase(1, 30000, "usd"))
+	if _, err := store.ReducePosition(ctx, "bob", "bitcoin", 0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ReducePosition() by bob error = %v, want ErrNotFound", err)
+	}
+}
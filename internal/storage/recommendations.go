@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Recommendation is a recommendation given to a user for a coin, kept to
+// compare recommendations over time
+type Recommendation struct {
+	ID          int64
+	UserJID     string
+	CoinID      string
+	Verdict     string
+	Confidence  int
+	TimeHorizon string
+	KeyRisks    []string
+	Reasoning   string
+	// Fallback is set when the recommendation was not produced by analysis
+	Fallback  bool
+	CreatedAt time.Time
+}
+
+// SaveRecommendation stores a recommendation and sets its ID
+func (s *Store) SaveRecommendation(ctx context.Context, rec *Recommendation) error {
+	risks, err := json.Marshal(rec.KeyRisks)
+	if err != nil {
+		return fmt.Errorf("failed to encode key risks: %w", err)
+	}
+	rec.CreatedAt = time.Now()
+
+	result, err := s.db.ExecContext(ctx, `INSERT INTO recommendations
+		(user_jid, coin_id, verdict, confidence, time_horizon, key_risks, reasoning, fallback, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rec.UserJID, rec.CoinID, rec.Verdict, rec.Confidence, rec.TimeHorizon,
+		string(risks), rec.Reasoning, rec.Fallback, rec.CreatedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to save recommendation: %w", err)
+	}
+
+	rec.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to read recommendation id: %w", err)
+	}
+	return nil
+}
+
+// LatestRecommendation returns the most recent recommendation given to the
+// user for a coin, ignoring fallbacks
+func (s *Store) LatestRecommendation(ctx context.Context, userJID, coinID string) (Recommendation, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT id, user_jid, coin_id, verdict, confidence, time_horizon,
+		key_risks, reasoning, fallback, created_at FROM recommendations
+		WHERE user_jid = ? AND coin_id = ? AND fallback = 0
+		ORDER BY created_at DESC, id DESC LIMIT 1`, userJID, coinID)
+
+	var rec Recommendation
+	var risks string
+	var createdAt int64
+	err := row.Scan(&rec.ID, &rec.UserJID, &rec.CoinID, &rec.Verdict, &rec.Confidence, &rec.TimeHorizon,
+		&risks, &rec.Reasoning, &rec.Fallback, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Recommendation{}, ErrNotFound
+	}
+	if err != nil {
+		return Recommendation{}, fmt.Errorf("failed to query recommendation: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(risks), &rec.KeyRisks); err != nil {
+		return Recommendation{}, fmt.Errorf("failed to decode key risks of recommendation %d: %w", rec.ID, err)
+	}
+	rec.CreatedAt = time.Unix(createdAt, 0)
+	return rec, nil
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Roles, from most to least privileged
+const (
+	RoleOwner   = "owner"
+	RoleAdmin   = "admin"
+	RoleUser    = "user"
+	RoleBlocked = "blocked"
+)
+
+// Stats summarizes how the bot is used
+type Stats struct {
+	Users           int
+	Admins          int
+	Blocked         int
+	Alerts          int
+	Subscriptions   int
+	Positions       int
+	WatchedCoins    int
+	Recommendations int
+}
+
+// GetRole returns the role of a user, RoleUser when none is stored
+func (s *Store) GetRole(ctx context.Context, userJID string) (string, error) {
+	role := RoleUser
+	err := s.db.QueryRowContext(ctx, `SELECT role FROM user_roles WHERE user_jid = ?`, userJID).Scan(&role)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return RoleUser, fmt.Errorf("failed to read user role: %w", err)
+	}
+	return role, nil
+}
+
+// SetRole stores the role of a user
+func (s *Store) SetRole(ctx context.Context, userJID, role string) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO user_roles (user_jid, role, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (user_jid) DO UPDATE SET role = excluded.role, updated_at = excluded.updated_at`,
+		userJID, role, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("failed to store user role: %w", err)
+	}
+	return nil
+}
+
+// SetOwner makes userJID the only owner. A previous owner becomes an admin,
+// so changing the configured owner does not leave two.
+func (s *Store) SetOwner(ctx context.Context, userJID string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+	if _, err := tx.ExecContext(ctx, `UPDATE user_roles SET role = ?, updated_at = ? WHERE role = ? AND user_jid != ?`,
+		RoleAdmin, now, RoleOwner, userJID); err != nil {
+		return fmt.Errorf("failed to demote previous owner: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO user_roles (user_jid, role, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (user_jid) DO UPDATE SET role = excluded.role, updated_at = excluded.updated_at`,
+		userJID, RoleOwner, now); err != nil {
+		return fmt.Errorf("failed to store owner: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit owner: %w", err)
+	}
+	return nil
+}
+
+// Stats counts the users known to the bot and the data they keep in it
+func (s *Store) Stats(ctx context.Context) (Stats, error) {
+	var stats Stats
+	err := s.db.QueryRowContext(ctx, `SELECT
+		(SELECT COUNT(*) FROM (
+			SELECT user_jid FROM user_settings
+			UNION SELECT user_jid FROM user_roles
+			UNION SELECT user_jid FROM alerts
+			UNION SELECT user_jid FROM portfolio_positions
+			UNION SELECT user_jid FROM subscriptions
+			UNION SELECT user_jid FROM watchlist
+			UNION SELECT user_jid FROM recommendations
+		)),
+		(SELECT COUNT(*) FROM user_roles WHERE role IN (?, ?)),
+		(SELECT COUNT(*) FROM user_roles WHERE role = ?),
+		(SELECT COUNT(*) FROM alerts WHERE active = 1),
+		(SELECT COUNT(*) FROM subscriptions),
+		(SELECT COUNT(*) FROM portfolio_positions),
+		(SELECT COUNT(*) FROM watchlist),
+		(SELECT COUNT(*) FROM recommendations)`,
+		RoleOwner, RoleAdmin, RoleBlocked).
+		Scan(&stats.Users, &stats.Admins, &stats.Blocked, &stats.Alerts,
+			&stats.Subscriptions, &stats.Positions, &stats.WatchedCoins, &stats.Recommendations)
+	if err != nil {
+		return Stats{}, fmt.Errorf("failed to read statistics: %w", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRoles(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	if role, err := store.GetRole(ctx, "alice"); err != nil || role != RoleUser {
+		t.Errorf("GetRole() of an unknown user = %q, %v, want %q", role, err, RoleUser)
+	}
+
+	for _, role := range []string{RoleAdmin, RoleBlocked, RoleUser} {
+		if err := store.SetRole(ctx, "alice", role); err != nil {
+			t.Fatalf("SetRole(%q) error = %v", role, err)
+		}
+		if got, err := store.GetRole(ctx, "alice"); err != nil || got != role {
+			t.Errorf("GetRole() after SetRole(%q) = %q, %v", role, got, err)
+		}
+	}
+}
+
+func TestSetOwner(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	if err := store.SetOwner(ctx, "alice"); err != nil {
+		t.Fatalf("SetOwner(alice) error = %v", err)
+	}
+	// Setting the same owner again changes nothing
+	if err := store.SetOwner(ctx, "alice"); err != nil {
+		t.Fatalf("SetOwner(alice) again error = %v", err)
+	}
+	if role, _ := store.GetRole(ctx, "alice"); role != RoleOwner {
+		t.Errorf("GetRole(alice) = %q, want %q", role, RoleOwner)
+	}
+
+	// A new owner replaces the previous one, who stays an admin
+	store.SetRole(ctx, "bob", RoleBlocked)
+	if err := store.SetOwner(ctx, "bob"); err != nil {
+		t.Fatalf("SetOwner(bob) error = %v", err)
+	}
+	for user, want := range map[string]string{"alice": RoleAdmin, "bob": RoleOwner} {
+		if role, _ := store.GetRole(ctx, user); role != want {
+			t.Errorf("GetRole(%s) = %q, want %q", user, role, want)
+		}
+	}
+}
+
+func TestStatsCountsRoles(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	store.SetOwner(ctx, "owner")
+	store.SetRole(ctx, "admin", RoleAdmin)
+	store.SetRole(ctx, "spammer", RoleBlocked)
+	store.SetRole(ctx, "pardoned", RoleUser)
+	store.SetCurrency(ctx, "newcomer", "eur")
+
+	stats, err := store.Stats(ctx)
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.Users != 5 || stats.Admins != 2 || stats.Blocked != 1 {
+		t.Errorf("Stats() = %+v, want 5 users, 2 admins and 1 blocked", stats)
+	}
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// DefaultCurrency is the preferred currency of users who did not choose one
+const DefaultCurrency = "usd"
+
+// UserSettings holds the preferences of a chat
+type UserSettings struct {
+	UserJID  string
+	Currency string
+	// Language is the chosen locale, or empty to detect it
+	Language string
+}
+
+// GetSettings returns the settings of a user, with defaults when none are stored
+func (s *Store) GetSettings(ctx context.Context, userJID string) (UserSettings, error) {
+	settings := UserSettings{UserJID: userJID, Currency: DefaultCurrency}
+	err := s.db.QueryRowContext(ctx, `SELECT currency, language FROM user_settings WHERE user_jid = ?`, userJID).
+		Scan(&settings.Currency, &settings.Language)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return settings, fmt.Errorf("failed to read user settings: %w", err)
+	}
+	return settings, nil
+}
+
+// SetCurrency stores the preferred currency of a user
+func (s *Store) SetCurrency(ctx context.Context, userJID, currency string) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO user_settings (user_jid, currency) VALUES (?, ?)
+		ON CONFLICT (user_jid) DO UPDATE SET currency = excluded.currency`, userJID, currency)
+	if err != nil {
+		return fmt.Errorf("failed to store preferred currency: %w", err)
+	}
+	return nil
+}
+
+// SetLanguage stores the preferred language of a user. An empty language
+// goes back to detecting it.
+func (s *Store) SetLanguage(ctx context.Context, userJID, language string) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO user_settings (user_jid, language) VALUES (?, ?)
+		ON CONFLICT (user_jid) DO UPDATE SET language = excluded.language`, userJID, language)
+	if err != nil {
+		return fmt.Errorf("failed to store preferred language: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// ErrNotFound is returned when a record does not exist or belongs to another user
+var ErrNotFound = errors.New("record not found")
+
+// migrations are applied in order; PRAGMA user_version records how many ran.
+// Never edit an existing entry, append a new one instead.
+var migrations = []string{
+	// 1: price alerts
+	`CREATE TABLE alerts (
+		id                INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_jid          TEXT    NOT NULL,
+		coin_id           TEXT    NOT NULL,
+		coin_symbol       TEXT    NOT NULL,
+		coin_name         TEXT    NOT NULL,
+		currency          TEXT    NOT NULL,
+		condition         TEXT    NOT NULL CHECK (condition IN ('above', 'below')),
+		target            REAL    NOT NULL,
+		repeat            INTEGER NOT NULL DEFAULT 0,
+		armed             INTEGER NOT NULL DEFAULT 1,
+		active            INTEGER NOT NULL DEFAULT 1,
+		created_at        INTEGER NOT NULL,
+		last_triggered_at INTEGER
+	);
+	CREATE INDEX idx_alerts_user ON alerts (user_jid);
+	CREATE INDEX idx_alerts_active ON alerts (active);`,
+
+	// 2: portfolios and per-user settings
+	`CREATE TABLE portfolio_positions (
+		user_jid    TEXT    NOT NULL,
+		coin_id     TEXT    NOT NULL,
+		coin_symbol TEXT    NOT NULL,
+		coin_name   TEXT    NOT NULL,
+		amount      REAL    NOT NULL,
+		cost        REAL    NOT NULL,
+		currency    TEXT    NOT NULL,
+		updated_at  INTEGER NOT NULL,
+		PRIMARY KEY (user_jid, coin_id)
+	);
+	CREATE TABLE user_settings (
+		user_jid TEXT PRIMARY KEY,
+		currency TEXT NOT NULL DEFAULT 'usd'
+	);`,
+
+	// 3: scheduled digests
+	`CREATE TABLE subscriptions (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_jid    TEXT    NOT NULL,
+		kind        TEXT    NOT NULL,
+		frequency   TEXT    NOT NULL CHECK (frequency IN ('daily', 'weekly')),
+		weekday     INTEGER NOT NULL DEFAULT 0,
+		minute      INTEGER NOT NULL,
+		timezone    TEXT    NOT NULL,
+		coins       TEXT    NOT NULL,
+		next_run_at INTEGER NOT NULL,
+		created_at  INTEGER NOT NULL
+	);
+	CREATE INDEX idx_subscriptions_user ON subscriptions (user_jid);
+	CREATE INDEX idx_subscriptions_next_run ON subscriptions (next_run_at);`,
+
+	// 4: watchlists
+	`CREATE TABLE watchlist (
+		user_jid    TEXT    NOT NULL,
+		coin_id     TEXT    NOT NULL,
+		coin_symbol TEXT    NOT NULL,
+		coin_name   TEXT    NOT NULL,
+		added_at    INTEGER NOT NULL,
+		PRIMARY KEY (user_jid, coin_id)
+	);`,
+
+	// 5: recommendation history
+	`CREATE TABLE recommendations (
+		id           INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_jid     TEXT    NOT NULL,
+		coin_id      TEXT    NOT NULL,
+		verdict      TEXT    NOT NULL CHECK (verdict IN ('buy', 'hold', 'sell')),
+		confidence   INTEGER NOT NULL,
+		time_horizon TEXT    NOT NULL,
+		key_risks    TEXT    NOT NULL,
+		reasoning    TEXT    NOT NULL,
+		fallback     INTEGER NOT NULL DEFAULT 0,
+		created_at   INTEGER NOT NULL
+	);
+	CREATE INDEX idx_recommendations_user_coin ON recommendations (user_jid, coin_id, created_at);`,
+
+	// 6: preferred language, empty when detected automatically
+	`ALTER TABLE user_settings ADD COLUMN language TEXT NOT NULL DEFAULT '';`,
+
+	// 7: user roles; users without a row have the user role
+	`CREATE TABLE user_roles (
+		user_jid   TEXT    PRIMARY KEY,
+		role       TEXT    NOT NULL CHECK (role IN ('owner', 'admin', 'user', 'blocked')),
+		updated_at INTEGER NOT NULL
+	);
+	CREATE INDEX idx_user_roles_role ON user_roles (role);`,
+
+	// 8: the chat alerts and digests are delivered to, a group when created
+	// there; older rows were stored under the chat they were created in
+	`ALTER TABLE alerts ADD COLUMN chat_jid TEXT NOT NULL DEFAULT '';
+	UPDATE alerts SET chat_jid = user_jid;
+	ALTER TABLE subscriptions ADD COLUMN chat_jid TEXT NOT NULL DEFAULT '';
+	UPDATE subscriptions SET chat_jid = user_jid;`,
+}
+
+// Store persists bot data such as alerts and portfolios in SQLite
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the SQLite database at dsn and applies pending migrations
+func Open(dsn string) (*Store, error) {
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite allows a single writer; serializing access avoids "database is locked"
+	db.SetMaxOpenConns(1)
+
+	store := &Store{db: db}
+	if err := store.migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// migrate applies the migrations that have not run yet
+func (s *Store) migrate(ctx context.Context) error {
+	var version int
+	if err := s.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %d: %w", i+1, err)
+		}
+		if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d: %w", i+1, err)
+		}
+		// PRAGMA does not accept placeholders
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
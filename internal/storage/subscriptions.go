@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Subscription kinds
+const (
+	SubscriptionDigest = "digest"
+)
+
+// Subscription frequencies
+const (
+	FrequencyDaily  = "daily"
+	FrequencyWeekly = "weekly"
+)
+
+// SubscribedCoin is a coin included in a subscription
+type SubscribedCoin struct {
+	ID     string `json:"id"`
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+}
+
+// Subscription is a scheduled message owned by a user and delivered to the
+// chat it was created in. Minute is the local time of day in minutes since
+// midnight in Timezone; Weekday is only used by weekly subscriptions.
+type Subscription struct {
+	ID        int64
+	UserJID   string
+	ChatJID   string
+	Kind      string
+	Frequency string
+	Weekday   time.Weekday
+	Minute    int
+	Timezone  string
+	Coins     []SubscribedCoin
+	NextRunAt time.Time
+	CreatedAt time.Time
+}
+
+const subscriptionColumns = `id, user_jid, chat_jid, kind, frequency, weekday, minute, timezone, coins, next_run_at, created_at`
+
+// CreateSubscription stores a new subscription and sets its ID
+func (s *Store) CreateSubscription(ctx context.Context, sub *Subscription) error {
+	coins, err := json.Marshal(sub.Coins)
+	if err != nil {
+		return fmt.Errorf("failed to encode subscription coins: %w", err)
+	}
+	sub.CreatedAt = time.Now()
+
+	result, err := s.db.ExecContext(ctx, `INSERT INTO subscriptions
+		(user_jid, chat_jid, kind, frequency, weekday, minute, timezone, coins, next_run_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		sub.UserJID, sub.ChatJID, sub.Kind, sub.Frequency, int(sub.Weekday), sub.Minute, sub.Timezone,
+		string(coins), sub.NextRunAt.Unix(), sub.CreatedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	sub.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to read subscription id: %w", err)
+	}
+	return nil
+}
+
+// ListSubscriptions returns the subscriptions of a user, oldest first
+func (s *Store) ListSubscriptions(ctx context.Context, userJID string) ([]Subscription, error) {
+	return s.querySubscriptions(ctx, `SELECT `+subscriptionColumns+` FROM subscriptions
+		WHERE user_jid = ? ORDER BY id`, userJID)
+}
+
+// CountSubscriptions returns the number of subscriptions of a user
+func (s *Store) CountSubscriptions(ctx context.Context, userJID string) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM subscriptions WHERE user_jid = ?`, userJID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count subscriptions: %w", err)
+	}
+	return count, nil
+}
+
+// DueSubscriptions returns the subscriptions of all users due at or before now
+func (s *Store) DueSubscriptions(ctx context.Context, now time.Time) ([]Subscription, error) {
+	return s.querySubscriptions(ctx, `SELECT `+subscriptionColumns+` FROM subscriptions
+		WHERE next_run_at <= ? ORDER BY next_run_at`, now.Unix())
+}
+
+// DeleteSubscription removes a subscription owned by the user
+func (s *Store) DeleteSubscription(ctx context.Context, userJID string, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM subscriptions WHERE id = ? AND user_jid = ?`, id, userJID)
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	return requireAffected(result)
+}
+
+// ScheduleSubscription sets the next time a subscription runs
+func (s *Store) ScheduleSubscription(ctx context.Context, id int64, next time.Time) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE subscriptions SET next_run_at = ? WHERE id = ?`, next.Unix(), id)
+	if err != nil {
+		return fmt.Errorf("failed to schedule subscription: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) querySubscriptions(ctx context.Context, query string, args ...interface{}) ([]Subscription, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []Subscription
+	for rows.Next() {
+		var sub Subscription
+		var weekday int
+		var coins string
+		var nextRunAt, createdAt int64
+		if err := rows.Scan(&sub.ID, &sub.UserJID, &sub.ChatJID, &sub.Kind, &sub.Frequency, &weekday, &sub.Minute,
+			&sub.Timezone, &coins, &nextRunAt, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan subscription: %w", err)
+		}
+		if err := json.Unmarshal([]byte(coins), &sub.Coins); err != nil {
+			return nil, fmt.Errorf("failed to decode coins of subscription %d: %w", sub.ID, err)
+		}
+		sub.Weekday = time.Weekday(weekday)
+		sub.NextRunAt = time.Unix(nextRunAt, 0)
+		sub.CreatedAt = time.Unix(createdAt, 0)
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WatchedCoin is a coin on a user's watchlist
+type WatchedCoin struct {
+	ID      string
+	Symbol  string
+	Name    string
+	AddedAt time.Time
+}
+
+// AddToWatchlist adds a coin to the user's watchlist. It reports false when
+// the coin was already on the list.
+func (s *Store) AddToWatchlist(ctx context.Context, userJID string, coin WatchedCoin) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `INSERT INTO watchlist (user_jid, coin_id, coin_symbol, coin_name, added_at)
+		VALUES (?, ?, ?, ?, ?) ON CONFLICT (user_jid, coin_id) DO NOTHING`,
+		userJID, coin.ID, coin.Symbol, coin.Name, time.Now().Unix())
+	if err != nil {
+		return false, fmt.Errorf("failed to add to watchlist: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// RemoveFromWatchlist removes a coin from the user's watchlist
+func (s *Store) RemoveFromWatchlist(ctx context.Context, userJID, coinID string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM watchlist WHERE user_jid = ? AND coin_id = ?`, userJID, coinID)
+	if err != nil {
+		return fmt.Errorf("failed to remove from watchlist: %w", err)
+	}
+	return requireAffected(result)
+}
+
+// Watchlist returns the coins on the user's watchlist in the order they were added
+func (s *Store) Watchlist(ctx context.Context, userJID string) ([]WatchedCoin, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT coin_id, coin_symbol, coin_name, added_at
+		FROM watchlist WHERE user_jid = ? ORDER BY added_at, rowid`, userJID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watchlist: %w", err)
+	}
+	defer rows.Close()
+
+	var coins []WatchedCoin
+	for rows.Next() {
+		var coin WatchedCoin
+		var addedAt int64
+		if err := rows.Scan(&coin.ID, &coin.Symbol, &coin.Name, &addedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan watchlist: %w", err)
+		}
+		coin.AddedAt = time.Unix(addedAt, 0)
+		coins = append(coins, coin)
+	}
+	return coins, rows.Err()
+}